@@ -92,14 +92,25 @@ func createMenuLikePermissions() map[string]*model.Permission {
 		// ETL调度
 		{"实时数据集成", "etl:stream", "实时数据集成", "seatunnel", "/seatunnel/stream", 1},
 		{"离线数据集成", "etl:batch", "离线数据集成", "seatunnel", "/seatunnel/batch", 2},
+		{"数据源", "etl:datasource", "数据源目录", "seatunnel", "/seatunnel/datasource", 3},
+		{"GitOps 同步", "etl:gitops", "GitOps 同步报告", "seatunnel", "/seatunnel/gitops", 4},
+		{"任务模板", "etl:template", "ETL 任务模板", "seatunnel", "/seatunnel/template", 5},
+		{"批量操作", "etl:bulk", "ETL 任务批量操作", "seatunnel", "/seatunnel/bulk", 6},
 		// 任务管理
 		{"调度器", "task:scheduler", "调度器", "task", "/task/scheduler", 1},
 		{"自定义任务", "task:custom", "自定义任务", "task", "/task/custom", 2},
 		{"任务日志", "task:log", "任务日志", "task", "/task/log", 3},
+		{"导入导出", "task:bundle", "任务与告警配置导入导出", "task", "/task/bundle", 4},
+		{"触发器", "task:trigger", "任务入站触发器", "task", "/task/trigger", 5},
 		// 消息通知
 		{"告警组管理", "notify:group", "告警组管理", "notify", "/alert/group", 1},
 		{"告警模板", "notify:template", "告警模板", "notify", "/alert/template", 2},
 		{"告警渠道", "notify:channel", "告警渠道", "notify", "/alert/channel", 3},
+		{"Webhook", "notify:webhook", "出站 Webhook 订阅", "notify", "/alert/webhook", 4},
+		{"告警规则", "notify:rule", "告警规则", "notify", "/alert/rule", 5},
+		{"告警静默", "notify:silence", "告警静默", "notify", "/alert/silence", 6},
+		{"升级策略", "notify:escalation", "告警升级策略", "notify", "/alert/escalation", 7},
+		{"值班表", "notify:oncall", "值班表与用户告警接收方式", "notify", "/alert/oncall", 8},
 		// 权限管理
 		{"用户管理", "rbac:user", "用户管理", "rbac", "/rbac/user", 1},
 		{"角色管理", "rbac:role", "角色管理", "rbac", "/rbac/role", 2},
@@ -156,12 +167,30 @@ func createMenuLikePermissions() map[string]*model.Permission {
 		{Name: "提交作业", Code: "etl:stream:submit", Description: "提交实时数据集成作业", Type: "api", Path: "/api/seatunnel/tasks/:id/start", Method: "POST", Status: 1, ParentID: subMenuMap["etl:stream"].ID},
 		{Name: "停止作业", Code: "etl:stream:stop", Description: "停止实时数据集成作业", Type: "api", Path: "/api/seatunnel/tasks/:id/stop", Method: "POST", Status: 1, ParentID: subMenuMap["etl:stream"].ID},
 		{Name: "同步状态", Code: "etl:stream:sync_status", Description: "同步实时数据集成作业状态", Type: "api", Path: "/api/seatunnel/tasks/sync-status", Method: "POST", Status: 1, ParentID: subMenuMap["etl:stream"].ID},
+		{Name: "配置加密", Code: "etl:stream:encrypt_config", Description: "批量加密实时任务存量配置", Type: "api", Path: "/api/seatunnel/tasks/encrypt-config/migrate", Method: "POST", Status: 1, ParentID: subMenuMap["etl:stream"].ID},
 		// batch权限
 		{Name: "查看", Code: "etl:batch:read", Description: "查看离线数据集成列表/详情", Type: "api", Path: "/api/seatunnel/batch", Method: "GET", Status: 1, ParentID: subMenuMap["etl:batch"].ID},
 		{Name: "创建", Code: "etl:batch:create", Description: "创建离线数据集成", Type: "api", Path: "/api/seatunnel/batch", Method: "POST", Status: 1, ParentID: subMenuMap["etl:batch"].ID},
 		{Name: "更新", Code: "etl:batch:update", Description: "更新离线数据集成", Type: "api", Path: "/api/seatunnel/batch/:id", Method: "PUT", Status: 1, ParentID: subMenuMap["etl:batch"].ID},
 		{Name: "删除", Code: "etl:batch:delete", Description: "删除离线数据集成", Type: "api", Path: "/api/seatunnel/batch/:id", Method: "DELETE", Status: 1, ParentID: subMenuMap["etl:batch"].ID},
 		{Name: "手动执行", Code: "etl:batch:submit", Description: "提交离线数据集成作业", Type: "api", Path: "/api/seatunnel/tasks/:id/start", Method: "POST", Status: 1, ParentID: subMenuMap["etl:batch"].ID},
+		{Name: "补数", Code: "etl:batch:backfill", Description: "按日期区间补数及暂停/恢复/取消补数", Type: "api", Path: "/api/seatunnel/batch/:id/backfill", Method: "POST", Status: 1, ParentID: subMenuMap["etl:batch"].ID},
+		{Name: "配置加密", Code: "etl:batch:encrypt_config", Description: "批量加密离线任务存量配置", Type: "api", Path: "/api/seatunnel/tasks/encrypt-config/migrate", Method: "POST", Status: 1, ParentID: subMenuMap["etl:batch"].ID},
+		// 数据源权限
+		{Name: "查看", Code: "etl:datasource:read", Description: "查看数据源", Type: "api", Path: "/api/seatunnel/datasource", Method: "GET", Status: 1, ParentID: subMenuMap["etl:datasource"].ID},
+		{Name: "创建", Code: "etl:datasource:create", Description: "创建数据源", Type: "api", Path: "/api/seatunnel/datasource", Method: "POST", Status: 1, ParentID: subMenuMap["etl:datasource"].ID},
+		{Name: "更新", Code: "etl:datasource:update", Description: "更新数据源", Type: "api", Path: "/api/seatunnel/datasource/:id", Method: "PUT", Status: 1, ParentID: subMenuMap["etl:datasource"].ID},
+		{Name: "删除", Code: "etl:datasource:delete", Description: "删除数据源", Type: "api", Path: "/api/seatunnel/datasource/:id", Method: "DELETE", Status: 1, ParentID: subMenuMap["etl:datasource"].ID},
+		{Name: "测试连接", Code: "etl:datasource:test", Description: "测试数据源连接", Type: "api", Path: "/api/seatunnel/datasource/:id/test", Method: "POST", Status: 1, ParentID: subMenuMap["etl:datasource"].ID},
+		{Name: "查看", Code: "etl:gitops:read", Description: "查看 GitOps 同步报告", Type: "api", Path: "/api/seatunnel/gitops/reports", Method: "GET", Status: 1, ParentID: subMenuMap["etl:gitops"].ID},
+		{Name: "同步", Code: "etl:gitops:reconcile", Description: "手动触发 GitOps 同步", Type: "api", Path: "/api/seatunnel/gitops/reconcile", Method: "POST", Status: 1, ParentID: subMenuMap["etl:gitops"].ID},
+		{Name: "查看", Code: "etl:template:read", Description: "查看任务模板及从模板创建任务", Type: "api", Path: "/api/seatunnel/template", Method: "GET", Status: 1, ParentID: subMenuMap["etl:template"].ID},
+		{Name: "创建", Code: "etl:template:create", Description: "创建任务模板", Type: "api", Path: "/api/seatunnel/template", Method: "POST", Status: 1, ParentID: subMenuMap["etl:template"].ID},
+		{Name: "更新", Code: "etl:template:update", Description: "更新任务模板", Type: "api", Path: "/api/seatunnel/template/:id", Method: "PUT", Status: 1, ParentID: subMenuMap["etl:template"].ID},
+		{Name: "删除", Code: "etl:template:delete", Description: "删除任务模板", Type: "api", Path: "/api/seatunnel/template/:id", Method: "DELETE", Status: 1, ParentID: subMenuMap["etl:template"].ID},
+		{Name: "下发", Code: "etl:template:propagate", Description: "将模板变更下发到派生任务", Type: "api", Path: "/api/seatunnel/template/:id/propagate", Method: "POST", Status: 1, ParentID: subMenuMap["etl:template"].ID},
+		{Name: "查看", Code: "etl:bulk:read", Description: "查看批量操作进度", Type: "api", Path: "/api/seatunnel/bulk", Method: "GET", Status: 1, ParentID: subMenuMap["etl:bulk"].ID},
+		{Name: "执行", Code: "etl:bulk:create", Description: "创建及取消批量操作（仍按单任务权限校验）", Type: "api", Path: "/api/seatunnel/bulk", Method: "POST", Status: 1, ParentID: subMenuMap["etl:bulk"].ID},
 		// 任务管理
 		// 调度器权限
 		{Name: "查看状态", Code: "task:scheduler:status", Description: "获取调度器状态", Type: "api", Path: "/api/task/scheduler/status", Method: "GET", Status: 1, ParentID: subMenuMap["task:scheduler"].ID},
@@ -173,6 +202,13 @@ func createMenuLikePermissions() map[string]*model.Permission {
 		{Name: "更新", Code: "task:custom:update", Description: "更新自定义任务", Type: "api", Path: "/api/task/custom/:id", Method: "PUT", Status: 1, ParentID: subMenuMap["task:custom"].ID},
 		// 任务日志权限
 		{Name: "查看", Code: "task:log:read", Description: "查看任务日志", Type: "api", Path: "/api/task/log", Method: "GET", Status: 1, ParentID: subMenuMap["task:log"].ID},
+		// 导入导出权限
+		{Name: "导出", Code: "task:bundle:export", Description: "导出任务与告警配置", Type: "api", Path: "/api/bundle/export", Method: "GET", Status: 1, ParentID: subMenuMap["task:bundle"].ID},
+		{Name: "导入", Code: "task:bundle:import", Description: "导入任务与告警配置", Type: "api", Path: "/api/bundle/import", Method: "POST", Status: 1, ParentID: subMenuMap["task:bundle"].ID},
+		{Name: "查看", Code: "task:trigger:read", Description: "查看任务触发器", Type: "api", Path: "/api/task/trigger", Method: "GET", Status: 1, ParentID: subMenuMap["task:trigger"].ID},
+		{Name: "创建", Code: "task:trigger:create", Description: "创建任务触发器", Type: "api", Path: "/api/task/trigger", Method: "POST", Status: 1, ParentID: subMenuMap["task:trigger"].ID},
+		{Name: "更新", Code: "task:trigger:update", Description: "更新任务触发器及重置token", Type: "api", Path: "/api/task/trigger/:id", Method: "PUT", Status: 1, ParentID: subMenuMap["task:trigger"].ID},
+		{Name: "删除", Code: "task:trigger:delete", Description: "删除任务触发器", Type: "api", Path: "/api/task/trigger/:id", Method: "DELETE", Status: 1, ParentID: subMenuMap["task:trigger"].ID},
 		// 告警管理
 		// 告警组权限
 		{Name: "查看", Code: "notify:group:read", Description: "查看告警组", Type: "api", Path: "/api/alert/group", Method: "GET", Status: 1, ParentID: subMenuMap["notify:group"].ID},
@@ -182,6 +218,7 @@ func createMenuLikePermissions() map[string]*model.Permission {
 		{Name: "查看成员", Code: "notify:group:member:read", Description: "查看告警组成员", Type: "api", Path: "/api/alert/group/:id/members", Method: "GET", Status: 1, ParentID: subMenuMap["notify:group"].ID},
 		{Name: "添加成员", Code: "notify:group:member:create", Description: "添加告警组成员", Type: "api", Path: "/api/alert/group/:id/members", Method: "POST", Status: 1, ParentID: subMenuMap["notify:group"].ID},
 		{Name: "删除成员", Code: "notify:group:member:delete", Description: "删除告警组成员", Type: "api", Path: "/api/alert/group/:id/members/:member_id", Method: "DELETE", Status: 1, ParentID: subMenuMap["notify:group"].ID},
+		{Name: "关联任务", Code: "notify:group:task", Description: "设置任务关联的告警组及通知条件", Type: "api", Path: "/api/alert/task-groups/:task_id", Method: "PUT", Status: 1, ParentID: subMenuMap["notify:group"].ID},
 		// 告警模板权限
 		{Name: "查看", Code: "notify:template:read", Description: "查看告警模板", Type: "api", Path: "/api/alert/template", Method: "GET", Status: 1, ParentID: subMenuMap["notify:template"].ID},
 		{Name: "创建", Code: "notify:template:create", Description: "创建告警模板", Type: "api", Path: "/api/alert/template", Method: "POST", Status: 1, ParentID: subMenuMap["notify:template"].ID},
@@ -193,6 +230,32 @@ func createMenuLikePermissions() map[string]*model.Permission {
 		{Name: "更新", Code: "notify:channel:update", Description: "更新告警渠道", Type: "api", Path: "/api/alert/channel/:id", Method: "PUT", Status: 1, ParentID: subMenuMap["notify:channel"].ID},
 		{Name: "删除", Code: "notify:channel:delete", Description: "删除告警渠道", Type: "api", Path: "/api/alert/channel/:id", Method: "DELETE", Status: 1, ParentID: subMenuMap["notify:channel"].ID},
 		{Name: "测试", Code: "notify:channel:test", Description: "测试告警渠道", Type: "api", Path: "/api/alert/channel/:id/test", Method: "POST", Status: 1, ParentID: subMenuMap["notify:channel"].ID},
+		{Name: "查看", Code: "notify:webhook:read", Description: "查看Webhook订阅及投递记录", Type: "api", Path: "/api/alert/webhook", Method: "GET", Status: 1, ParentID: subMenuMap["notify:webhook"].ID},
+		{Name: "创建", Code: "notify:webhook:create", Description: "创建Webhook订阅", Type: "api", Path: "/api/alert/webhook", Method: "POST", Status: 1, ParentID: subMenuMap["notify:webhook"].ID},
+		{Name: "更新", Code: "notify:webhook:update", Description: "更新Webhook订阅", Type: "api", Path: "/api/alert/webhook/:id", Method: "PUT", Status: 1, ParentID: subMenuMap["notify:webhook"].ID},
+		{Name: "删除", Code: "notify:webhook:delete", Description: "删除Webhook订阅", Type: "api", Path: "/api/alert/webhook/:id", Method: "DELETE", Status: 1, ParentID: subMenuMap["notify:webhook"].ID},
+		{Name: "测试", Code: "notify:webhook:test", Description: "发送Webhook测试事件", Type: "api", Path: "/api/alert/webhook/:id/test", Method: "POST", Status: 1, ParentID: subMenuMap["notify:webhook"].ID},
+		{Name: "重新投递", Code: "notify:webhook:redeliver", Description: "重新投递Webhook事件", Type: "api", Path: "/api/alert/webhook/deliveries/:id/redeliver", Method: "POST", Status: 1, ParentID: subMenuMap["notify:webhook"].ID},
+		{Name: "查看", Code: "notify:rule:read", Description: "查看告警规则及告警记录", Type: "api", Path: "/api/alert/rule", Method: "GET", Status: 1, ParentID: subMenuMap["notify:rule"].ID},
+		{Name: "创建", Code: "notify:rule:create", Description: "创建告警规则", Type: "api", Path: "/api/alert/rule", Method: "POST", Status: 1, ParentID: subMenuMap["notify:rule"].ID},
+		{Name: "更新", Code: "notify:rule:update", Description: "更新告警规则", Type: "api", Path: "/api/alert/rule/:id", Method: "PUT", Status: 1, ParentID: subMenuMap["notify:rule"].ID},
+		{Name: "删除", Code: "notify:rule:delete", Description: "删除告警规则", Type: "api", Path: "/api/alert/rule/:id", Method: "DELETE", Status: 1, ParentID: subMenuMap["notify:rule"].ID},
+		{Name: "处理", Code: "notify:rule:ack", Description: "确认或恢复告警", Type: "api", Path: "/api/alert/rule/events/:id/ack", Method: "POST", Status: 1, ParentID: subMenuMap["notify:rule"].ID},
+		{Name: "重试发送", Code: "notify:rule:retry", Description: "重新发送进入死信的告警", Type: "api", Path: "/api/alert/rule/deliveries/:id/retry", Method: "POST", Status: 1, ParentID: subMenuMap["notify:rule"].ID},
+		{Name: "查看", Code: "notify:silence:read", Description: "查看告警静默", Type: "api", Path: "/api/alert/silence", Method: "GET", Status: 1, ParentID: subMenuMap["notify:silence"].ID},
+		{Name: "创建", Code: "notify:silence:create", Description: "创建告警静默", Type: "api", Path: "/api/alert/silence", Method: "POST", Status: 1, ParentID: subMenuMap["notify:silence"].ID},
+		{Name: "更新", Code: "notify:silence:update", Description: "更新告警静默", Type: "api", Path: "/api/alert/silence/:id", Method: "PUT", Status: 1, ParentID: subMenuMap["notify:silence"].ID},
+		{Name: "删除", Code: "notify:silence:delete", Description: "删除告警静默", Type: "api", Path: "/api/alert/silence/:id", Method: "DELETE", Status: 1, ParentID: subMenuMap["notify:silence"].ID},
+		{Name: "查看", Code: "notify:escalation:read", Description: "查看升级策略", Type: "api", Path: "/api/alert/escalation", Method: "GET", Status: 1, ParentID: subMenuMap["notify:escalation"].ID},
+		{Name: "创建", Code: "notify:escalation:create", Description: "创建升级策略", Type: "api", Path: "/api/alert/escalation", Method: "POST", Status: 1, ParentID: subMenuMap["notify:escalation"].ID},
+		{Name: "更新", Code: "notify:escalation:update", Description: "更新升级策略", Type: "api", Path: "/api/alert/escalation/:id", Method: "PUT", Status: 1, ParentID: subMenuMap["notify:escalation"].ID},
+		{Name: "删除", Code: "notify:escalation:delete", Description: "删除升级策略", Type: "api", Path: "/api/alert/escalation/:id", Method: "DELETE", Status: 1, ParentID: subMenuMap["notify:escalation"].ID},
+		{Name: "查看", Code: "notify:oncall:read", Description: "查看值班表、当前值班人及用户接收方式", Type: "api", Path: "/api/alert/oncall", Method: "GET", Status: 1, ParentID: subMenuMap["notify:oncall"].ID},
+		{Name: "创建", Code: "notify:oncall:create", Description: "创建值班表", Type: "api", Path: "/api/alert/oncall", Method: "POST", Status: 1, ParentID: subMenuMap["notify:oncall"].ID},
+		{Name: "更新", Code: "notify:oncall:update", Description: "更新值班表", Type: "api", Path: "/api/alert/oncall/:id", Method: "PUT", Status: 1, ParentID: subMenuMap["notify:oncall"].ID},
+		{Name: "删除", Code: "notify:oncall:delete", Description: "删除值班表", Type: "api", Path: "/api/alert/oncall/:id", Method: "DELETE", Status: 1, ParentID: subMenuMap["notify:oncall"].ID},
+		{Name: "替班", Code: "notify:oncall:override", Description: "创建或删除临时替班", Type: "api", Path: "/api/alert/oncall/:id/overrides", Method: "POST", Status: 1, ParentID: subMenuMap["notify:oncall"].ID},
+		{Name: "接收方式", Code: "notify:oncall:contact", Description: "配置用户的告警接收方式", Type: "api", Path: "/api/alert/contact", Method: "POST", Status: 1, ParentID: subMenuMap["notify:oncall"].ID},
 		// 用户管理
 		{Name: "查看", Code: "rbac:user:read", Description: "查看用户", Type: "api", Path: "/api/users", Method: "GET", Status: 1, ParentID: subMenuMap["rbac:user"].ID},
 		{Name: "创建", Code: "rbac:user:create", Description: "创建用户", Type: "api", Path: "/api/users", Method: "POST", Status: 1, ParentID: subMenuMap["rbac:user"].ID},
@@ -324,7 +387,11 @@ func assignPermissionsToRoles(roles map[string]*model.Role, permissions map[stri
 			// Seatunnel API
 			"etl:stream", "etl:stream:read", "etl:stream:create", "etl:stream:update",
 			"etl:batch", "etl:batch:read", "etl:batch:create", "etl:batch:update",
-			"notify:group:read",
+			"etl:datasource", "etl:datasource:read", "etl:datasource:test",
+			"etl:gitops", "etl:gitops:read",
+			"etl:template", "etl:template:read",
+			"etl:bulk", "etl:bulk:read", "etl:bulk:create",
+			"notify:group:read", "notify:group:task",
 			// 任务中心 API
 			"task:scheduler", "task:scheduler:status",
 			"task:custom", "task:custom:read",
@@ -8,14 +8,24 @@ import (
 	"net/http"
 	alertApi "octoops/internal/api/alert"
 	aliyunApi "octoops/internal/api/aliyun"
+	bundleApi "octoops/internal/api/bundle"
 	rbacApi "octoops/internal/api/rbac"
+	realtimeApi "octoops/internal/api/realtime"
 	seatunnelApi "octoops/internal/api/seatunnel"
 	taskApi "octoops/internal/api/task"
 	"octoops/internal/config"
+	"octoops/internal/event"
 	"octoops/internal/infra/postgres"
 	infraRedis "octoops/internal/infra/redis"
 	"octoops/internal/pkg/jwt"
 	"octoops/internal/scheduler"
+	alertService "octoops/internal/service/alert"
+	alertingService "octoops/internal/service/alerting"
+	auditService "octoops/internal/service/audit"
+	bulkService "octoops/internal/service/bulk"
+	realtimeService "octoops/internal/service/realtime"
+	seatunnelService "octoops/internal/service/seatunnel"
+	webhookService "octoops/internal/service/webhook"
 	"os"
 	"os/signal"
 	"syscall"
@@ -43,7 +53,23 @@ func main() {
 	if err := infraRedis.Init(redisCfg); err != nil {
 		log.Fatalf("初始化Redis失败: %v", err)
 	}
+	// 注册领域事件订阅者并启动发件箱分发
+	seatunnelService.RegisterEventHandlers()
+	realtimeService.RegisterEventHandlers()
+	auditService.RegisterEventHandlers()
+	scheduler.RegisterEventHandlers()
+	webhookService.RegisterEventHandlers()
+	alertingService.RegisterEventHandlers()
+	event.StartDispatcher()
+	webhookService.StartWorker()
+	realtimeService.Start()   // 订阅实时事件频道
 	scheduler.InitScheduler() // 初始化定时任务
+	seatunnelService.ResumeBackfills()
+	bulkService.ResumeOperations()
+	seatunnelService.ResumeTaskOperations()
+	alertService.EncryptChannelSecrets() // 加密升级前明文保存的短信/语音渠道密钥
+	alertingService.EnsureDefaultRules()
+	alertingService.Start() // 定时评估告警规则，异步发送告警
 
 	// 初始化 Gin 引擎
 	r := gin.New()
@@ -59,14 +85,28 @@ func main() {
 	taskApi.RegisterCustomTaskRoutes(apiGroup)
 	taskApi.RegisterSchedulerRoutes(apiGroup)
 	taskApi.RegisterTaskLogRoutes(apiGroup)
+	taskApi.RegisterTaskTriggerRoutes(apiGroup)
 	seatunnelApi.RegisterStreamTaskRoutes(apiGroup)
 	seatunnelApi.RegisterBatchTaskRoutes(apiGroup)
+	seatunnelApi.RegisterBackfillRoutes(apiGroup)
+	seatunnelApi.RegisterDatasourceRoutes(apiGroup)
+	seatunnelApi.RegisterConfigEncryptRoutes(apiGroup)
+	seatunnelApi.RegisterGitOpsRoutes(apiGroup)
+	seatunnelApi.RegisterTemplateRoutes(apiGroup)
+	seatunnelApi.RegisterBulkRoutes(apiGroup)
+	seatunnelApi.RegisterTaskOperationRoutes(apiGroup)
+	bundleApi.RegisterBundleRoutes(apiGroup)
+	realtimeApi.RegisterRealtimeRoutes(apiGroup)
 	aliyunApi.RegisterAliyunRoutes(apiGroup)
 	// 告警相关路由
 	alertApi.RegisterAlertChannelRoutes(apiGroup)
 	alertApi.RegisterAlertGroupRoutes(apiGroup)
 	alertApi.RegisterAlertGroupMemberRoutes(apiGroup)
 	alertApi.RegisterAlertTemplateRoutes(apiGroup)
+	alertApi.RegisterWebhookRoutes(apiGroup)
+	alertApi.RegisterAlertRuleRoutes(apiGroup)
+	alertApi.RegisterAlertSilenceRoutes(apiGroup)
+	alertApi.RegisterAlertOnCallRoutes(apiGroup)
 
 	// RBAC管理路由
 	rbacApi.RegisterUserRoutes(apiGroup)
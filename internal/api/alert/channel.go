@@ -19,6 +19,9 @@ func ListChannels(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询渠道失败: " + err.Error()})
 		return
 	}
+	for i := range channels {
+		channels[i] = alertService.MaskChannel(channels[i])
+	}
 	c.JSON(http.StatusOK, channels)
 }
 
@@ -33,7 +36,7 @@ func CreateChannel(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "创建渠道失败: " + err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, channel)
+	c.JSON(http.StatusOK, alertService.MaskChannel(channel))
 }
 
 // UpdateChannel 更新渠道
@@ -49,7 +52,7 @@ func UpdateChannel(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
 		return
 	}
-	c.JSON(http.StatusOK, channel)
+	c.JSON(http.StatusOK, alertService.MaskChannel(channel))
 }
 
 // DeleteChannel 删除渠道
@@ -93,43 +96,22 @@ func TestChannel(c *gin.Context) {
 func testChannelMessage(channel alertModel.AlertChannel, templateContent string) error {
 	if templateContent == "" && channel.TemplateID != 0 {
 		if tpl, err := alertService.GetAlertTemplateByID(fmt.Sprintf("%d", channel.TemplateID)); err == nil {
-			templateContent = strings.TrimSpace(tpl.Content)
+			_, content := tpl.Resolve(channel.Type)
+			templateContent = strings.TrimSpace(content)
 		}
 	}
+	return alertService.SendTest(&channel, templateContent)
+}
 
-	var err error
-	switch channel.Type {
-	case "email":
-		if templateContent != "" {
-			err = alertService.SendEmailWithTemplate(&channel, templateContent, nil)
-		} else {
-			err = alertService.SendTestEmail(&channel)
-		}
-	case "dingtalk":
-		if templateContent != "" {
-			err = alertService.SendDingTalkMarkdownWithTemplate(
-				channel.Target,
-				channel.DingtalkSecret,
-				"OctoOps 测试通知",
-				templateContent,
-				nil,
-			)
-		} else {
-			err = alertService.SendTestRobot(&channel)
-		}
-	case "wechat":
-		err = fmt.Errorf("暂未实现企业微信测试发送")
-	case "feishu":
-		err = fmt.Errorf("暂未实现飞书测试发送")
-	default:
-		err = fmt.Errorf("未知渠道类型: %s", channel.Type)
-	}
-	return err
+// ListChannelTypes 支持的渠道类型
+func ListChannelTypes(c *gin.Context) {
+	c.JSON(http.StatusOK, alertService.NotifierTypes())
 }
 
 // RegisterAlertChannelRoutes 路由注册函数
 func RegisterAlertChannelRoutes(r *gin.RouterGroup) {
 	r.GET("/alert/channel", middleware.AuthMiddleware(), middleware.RequirePermission("notify:channel:read"), ListChannels)
+	r.GET("/alert/channel/types", middleware.AuthMiddleware(), middleware.RequirePermission("notify:channel:read"), ListChannelTypes)
 	r.POST("/alert/channel", middleware.AuthMiddleware(), middleware.RequirePermission("notify:channel:create"), CreateChannel)
 	r.PUT("/alert/channel/:id", middleware.AuthMiddleware(), middleware.RequirePermission("notify:channel:update"), UpdateChannel)
 	r.DELETE("/alert/channel/:id", middleware.AuthMiddleware(), middleware.RequirePermission("notify:channel:delete"), DeleteChannel)
@@ -1,14 +1,30 @@
 package alert
 
 import (
+	"errors"
 	"net/http"
 	"octoops/internal/middleware"
 	alertModel "octoops/internal/model/alert"
 	alertService "octoops/internal/service/alert"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
+func writeGroupError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	case errors.Is(err, alertService.ErrInvalidGroup):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, alertService.ErrAlertGroupInUse):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message + ": " + err.Error()})
+	}
+}
+
 // ListAlertGroups 告警组列表
 func ListAlertGroups(c *gin.Context) {
 	groups, err := alertService.ListAlertGroups()
@@ -27,7 +43,7 @@ func CreateAlertGroup(c *gin.Context) {
 		return
 	}
 	if err := alertService.CreateAlertGroup(&group); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "创建告警组失败: " + err.Error()})
+		writeGroupError(c, err, "创建告警组失败")
 		return
 	}
 	c.JSON(http.StatusOK, group)
@@ -43,26 +59,69 @@ func UpdateAlertGroup(c *gin.Context) {
 	}
 	group, err := alertService.UpdateAlertGroup(id, req)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		writeGroupError(c, err, "更新告警组失败")
 		return
 	}
 	c.JSON(http.StatusOK, group)
 }
 
-// DeleteAlertGroup 删除告警组
+// DeleteAlertGroup 删除告警组，仍有任务关联时返回 409，cascade=true 时一并解除关联
 func DeleteAlertGroup(c *gin.Context) {
 	id := c.Param("id")
-	if err := alertService.DeleteAlertGroup(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "删除失败: " + err.Error()})
+	if err := alertService.DeleteAlertGroup(id, c.Query("cascade") == "true"); err != nil {
+		writeGroupError(c, err, "删除失败")
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
 }
 
+// ListTaskGroups 任务与告警组的关联，可按 source、task_id、group_id 筛选
+func ListTaskGroups(c *gin.Context) {
+	var ids [2]uint
+	for i, key := range []string{"task_id", "group_id"} {
+		if v := c.Query(key); v != "" {
+			id, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "无效的 " + key + ": " + err.Error()})
+				return
+			}
+			ids[i] = uint(id)
+		}
+	}
+	links, err := alertService.ListTaskGroups(c.Query("source"), ids[0], ids[1])
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询任务告警组失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, links)
+}
+
+// SetTaskGroups 整体替换任务关联的告警组及每个关联的级别筛选与通知事件，source=custom 时为自定义任务
+func SetTaskGroups(c *gin.Context) {
+	taskID, err := alertService.ParseUint(c.Param("task_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的任务ID: " + err.Error()})
+		return
+	}
+	var links []alertService.TaskGroupLink
+	if err := c.ShouldBindJSON(&links); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	result, err := alertService.SetTaskGroups(c.DefaultQuery("source", alertModel.TaskSourceETL), taskID, links)
+	if err != nil {
+		writeGroupError(c, err, "更新任务告警组失败")
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
 // RegisterAlertGroupRoutes 路由注册
 func RegisterAlertGroupRoutes(r *gin.RouterGroup) {
 	r.GET("/alert/group", middleware.AuthMiddleware(), middleware.RequirePermission("notify:group:read"), ListAlertGroups)
 	r.POST("/alert/group", middleware.AuthMiddleware(), middleware.RequirePermission("notify:group:create"), CreateAlertGroup)
 	r.PUT("/alert/group/:id", middleware.AuthMiddleware(), middleware.RequirePermission("notify:group:update"), UpdateAlertGroup)
 	r.DELETE("/alert/group/:id", middleware.AuthMiddleware(), middleware.RequirePermission("notify:group:delete"), DeleteAlertGroup)
+	r.GET("/alert/task-groups", middleware.AuthMiddleware(), middleware.RequirePermission("notify:group:read"), ListTaskGroups)
+	r.PUT("/alert/task-groups/:task_id", middleware.AuthMiddleware(), middleware.RequirePermission("notify:group:task"), SetTaskGroups)
 }
@@ -39,6 +39,10 @@ func AddAlertGroupMember(c *gin.Context) {
 			c.JSON(http.StatusConflict, gin.H{"error": "成员已存在，不能重复添加"})
 			return
 		}
+		if errors.Is(err, alertService.ErrInvalidGroup) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "创建成员失败: " + err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "创建成员失败: " + err.Error()})
 		return
 	}
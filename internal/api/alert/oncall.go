@@ -0,0 +1,224 @@
+package alert
+
+import (
+	"errors"
+	"net/http"
+	"octoops/internal/middleware"
+	alertModel "octoops/internal/model/alert"
+	alertService "octoops/internal/service/alert"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func writeOnCallError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	case errors.Is(err, alertService.ErrInvalidSchedule):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message + ": " + err.Error()})
+	}
+}
+
+// scheduleID 解析路径中的值班表 ID
+func scheduleID(c *gin.Context) (uint, bool) {
+	id, err := alertService.ParseUint(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的值班表ID: " + err.Error()})
+		return 0, false
+	}
+	return id, true
+}
+
+// ListOnCallSchedules 值班表列表
+func ListOnCallSchedules(c *gin.Context) {
+	schedules, err := alertService.ListSchedules()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询值班表失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, schedules)
+}
+
+// GetOnCallSchedule 值班表详情
+func GetOnCallSchedule(c *gin.Context) {
+	schedule, err := alertService.GetSchedule(c.Param("id"))
+	if err != nil {
+		writeOnCallError(c, err, "查询值班表失败")
+		return
+	}
+	c.JSON(http.StatusOK, schedule)
+}
+
+// CreateOnCallSchedule 新建值班表
+func CreateOnCallSchedule(c *gin.Context) {
+	var schedule alertModel.AlertOnCallSchedule
+	if err := c.ShouldBindJSON(&schedule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := alertService.CreateSchedule(&schedule); err != nil {
+		writeOnCallError(c, err, "创建值班表失败")
+		return
+	}
+	c.JSON(http.StatusOK, schedule)
+}
+
+// UpdateOnCallSchedule 更新值班表
+func UpdateOnCallSchedule(c *gin.Context) {
+	var req alertModel.AlertOnCallSchedule
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	schedule, err := alertService.UpdateSchedule(c.Param("id"), req)
+	if err != nil {
+		writeOnCallError(c, err, "更新值班表失败")
+		return
+	}
+	c.JSON(http.StatusOK, schedule)
+}
+
+// DeleteOnCallSchedule 删除值班表，同时移除引用该值班表的告警组成员
+func DeleteOnCallSchedule(c *gin.Context) {
+	if err := alertService.DeleteSchedule(c.Param("id")); err != nil {
+		writeOnCallError(c, err, "删除失败")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// GetCurrentOnCall 当前值班人，可通过 at（RFC3339）查询指定时刻
+func GetCurrentOnCall(c *gin.Context) {
+	id, ok := scheduleID(c)
+	if !ok {
+		return
+	}
+	at := time.Now()
+	if v := c.Query("at"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "at 格式应为 RFC3339"})
+			return
+		}
+		at = t
+	}
+	shift, err := alertService.CurrentShift(id, at)
+	if err != nil {
+		writeOnCallError(c, err, "查询当前值班人失败")
+		return
+	}
+	c.JSON(http.StatusOK, shift)
+}
+
+// ListOnCallOverrides 值班表的替班记录
+func ListOnCallOverrides(c *gin.Context) {
+	id, ok := scheduleID(c)
+	if !ok {
+		return
+	}
+	overrides, err := alertService.ListOverrides(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询替班记录失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, overrides)
+}
+
+// CreateOnCallOverride 新建替班
+func CreateOnCallOverride(c *gin.Context) {
+	id, ok := scheduleID(c)
+	if !ok {
+		return
+	}
+	var override alertModel.AlertOnCallOverride
+	if err := c.ShouldBindJSON(&override); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	override.ScheduleID = id
+	override.CreatedBy = ""
+	if user := middleware.GetCurrentUser(c); user != nil {
+		override.CreatedBy = user.Username
+	}
+	if err := alertService.CreateOverride(&override); err != nil {
+		writeOnCallError(c, err, "创建替班失败")
+		return
+	}
+	c.JSON(http.StatusOK, override)
+}
+
+// DeleteOnCallOverride 删除替班
+func DeleteOnCallOverride(c *gin.Context) {
+	id, ok := scheduleID(c)
+	if !ok {
+		return
+	}
+	if err := alertService.DeleteOverride(id, c.Param("override_id")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "删除失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// ListAlertContacts 用户的告警接收方式，可按 user_id 筛选
+func ListAlertContacts(c *gin.Context) {
+	var userID uint
+	if v := c.Query("user_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的用户ID: " + err.Error()})
+			return
+		}
+		userID = uint(id)
+	}
+	contacts, err := alertService.ListContacts(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询接收方式失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, contacts)
+}
+
+// CreateAlertContact 新增用户的告警接收方式
+func CreateAlertContact(c *gin.Context) {
+	var contact alertModel.AlertUserContact
+	if err := c.ShouldBindJSON(&contact); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := alertService.CreateContact(&contact); err != nil {
+		writeOnCallError(c, err, "创建接收方式失败")
+		return
+	}
+	c.JSON(http.StatusOK, contact)
+}
+
+// DeleteAlertContact 删除用户的告警接收方式
+func DeleteAlertContact(c *gin.Context) {
+	if err := alertService.DeleteContact(c.Param("id")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "删除失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// RegisterAlertOnCallRoutes 值班表与用户接收方式路由
+func RegisterAlertOnCallRoutes(r *gin.RouterGroup) {
+	r.GET("/alert/oncall", middleware.AuthMiddleware(), middleware.RequirePermission("notify:oncall:read"), ListOnCallSchedules)
+	r.GET("/alert/oncall/:id", middleware.AuthMiddleware(), middleware.RequirePermission("notify:oncall:read"), GetOnCallSchedule)
+	r.POST("/alert/oncall", middleware.AuthMiddleware(), middleware.RequirePermission("notify:oncall:create"), CreateOnCallSchedule)
+	r.PUT("/alert/oncall/:id", middleware.AuthMiddleware(), middleware.RequirePermission("notify:oncall:update"), UpdateOnCallSchedule)
+	r.DELETE("/alert/oncall/:id", middleware.AuthMiddleware(), middleware.RequirePermission("notify:oncall:delete"), DeleteOnCallSchedule)
+	r.GET("/alert/oncall/:id/current", middleware.AuthMiddleware(), middleware.RequirePermission("notify:oncall:read"), GetCurrentOnCall)
+	r.GET("/alert/oncall/:id/overrides", middleware.AuthMiddleware(), middleware.RequirePermission("notify:oncall:read"), ListOnCallOverrides)
+	r.POST("/alert/oncall/:id/overrides", middleware.AuthMiddleware(), middleware.RequirePermission("notify:oncall:override"), CreateOnCallOverride)
+	r.DELETE("/alert/oncall/:id/overrides/:override_id", middleware.AuthMiddleware(), middleware.RequirePermission("notify:oncall:override"), DeleteOnCallOverride)
+	r.GET("/alert/contact", middleware.AuthMiddleware(), middleware.RequirePermission("notify:oncall:read"), ListAlertContacts)
+	r.POST("/alert/contact", middleware.AuthMiddleware(), middleware.RequirePermission("notify:oncall:contact"), CreateAlertContact)
+	r.DELETE("/alert/contact/:id", middleware.AuthMiddleware(), middleware.RequirePermission("notify:oncall:contact"), DeleteAlertContact)
+}
@@ -0,0 +1,207 @@
+package alert
+
+import (
+	"errors"
+	"net/http"
+	"octoops/internal/middleware"
+	alertModel "octoops/internal/model/alert"
+	alertingService "octoops/internal/service/alerting"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func writeRuleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	case errors.Is(err, alertingService.ErrEventResolved), errors.Is(err, alertingService.ErrDeliveryNotRetryable):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, alertingService.ErrInvalidRule), errors.Is(err, alertingService.ErrInvalidSilence), errors.Is(err, alertingService.ErrInvalidEscalation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message + ": " + err.Error()})
+	}
+}
+
+// ListAlertRules 告警规则列表，可按 task_id 过滤
+func ListAlertRules(c *gin.Context) {
+	taskID, _ := strconv.ParseUint(c.Query("task_id"), 10, 64)
+	rules, err := alertingService.ListRules(uint(taskID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询告警规则失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rules)
+}
+
+// GetAlertRule 获取单个告警规则
+func GetAlertRule(c *gin.Context) {
+	rule, err := alertingService.GetRule(c.Param("id"))
+	if err != nil {
+		writeRuleError(c, err, "查询告警规则失败")
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// CreateAlertRule 新增告警规则
+func CreateAlertRule(c *gin.Context) {
+	var rule alertModel.AlertRule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := alertingService.CreateRule(&rule); err != nil {
+		writeRuleError(c, err, "创建告警规则失败")
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// UpdateAlertRule 更新告警规则
+func UpdateAlertRule(c *gin.Context) {
+	var req alertModel.AlertRule
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	rule, err := alertingService.UpdateRule(c.Param("id"), req)
+	if err != nil {
+		writeRuleError(c, err, "更新告警规则失败")
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteAlertRule 删除告警规则
+func DeleteAlertRule(c *gin.Context) {
+	if err := alertingService.DeleteRule(c.Param("id")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "删除失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// ListAlertEvents 告警记录，可按 rule_id、source、task_id、severity、state、suppressed 过滤
+func ListAlertEvents(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	ruleID, _ := strconv.ParseUint(c.Query("rule_id"), 10, 64)
+	taskID, _ := strconv.ParseUint(c.Query("task_id"), 10, 64)
+	events, total, err := alertingService.ListEvents(alertingService.EventFilter{
+		RuleID:     uint(ruleID),
+		Source:     c.Query("source"),
+		TaskID:     uint(taskID),
+		Severity:   c.Query("severity"),
+		State:      c.Query("state"),
+		Suppressed: c.Query("suppressed"),
+	}, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询告警记录失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": events, "total": total})
+}
+
+// GetAlertEvent 告警详情及各渠道发送记录
+func GetAlertEvent(c *gin.Context) {
+	record, err := alertingService.GetEvent(c.Param("id"))
+	if err != nil {
+		writeRuleError(c, err, "查询告警失败")
+		return
+	}
+	deliveries, err := alertingService.ListDeliveries(record.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询发送记录失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"event": record, "deliveries": deliveries})
+}
+
+// AckAlertEvent 确认告警，停止升级
+func AckAlertEvent(c *gin.Context) {
+	operator := ""
+	if user := middleware.GetCurrentUser(c); user != nil {
+		operator = user.Username
+	}
+	record, err := alertingService.AckEvent(c.Param("id"), operator)
+	if err != nil {
+		writeRuleError(c, err, "确认告警失败")
+		return
+	}
+	c.JSON(http.StatusOK, record)
+}
+
+// ResolveAlertEvent 手动恢复告警
+func ResolveAlertEvent(c *gin.Context) {
+	operator := ""
+	if user := middleware.GetCurrentUser(c); user != nil {
+		operator = user.Username
+	}
+	record, err := alertingService.ResolveEvent(c.Param("id"), operator)
+	if err != nil {
+		writeRuleError(c, err, "恢复告警失败")
+		return
+	}
+	c.JSON(http.StatusOK, record)
+}
+
+// ListAlertDeliveries 告警发送记录，可按 event_id、digest_id、channel_id、kind、status 过滤，status=dead 为重试耗尽的死信
+func ListAlertDeliveries(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	eventID, _ := strconv.ParseUint(c.Query("event_id"), 10, 64)
+	digestID, _ := strconv.ParseUint(c.Query("digest_id"), 10, 64)
+	channelID, _ := strconv.ParseUint(c.Query("channel_id"), 10, 64)
+	deliveries, total, err := alertingService.ListAllDeliveries(alertingService.DeliveryFilter{
+		EventID:   uint(eventID),
+		DigestID:  uint(digestID),
+		ChannelID: uint(channelID),
+		Kind:      c.Query("kind"),
+		Status:    c.Query("status"),
+	}, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询发送记录失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": deliveries, "total": total})
+}
+
+// RetryAlertDelivery 死信记录重新入队发送
+func RetryAlertDelivery(c *gin.Context) {
+	delivery, err := alertingService.RetryDelivery(c.Param("id"))
+	if err != nil {
+		writeRuleError(c, err, "重试发送失败")
+		return
+	}
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+// RegisterAlertRuleRoutes 路由注册
+func RegisterAlertRuleRoutes(r *gin.RouterGroup) {
+	r.GET("/alert/rule", middleware.AuthMiddleware(), middleware.RequirePermission("notify:rule:read"), ListAlertRules)
+	r.GET("/alert/rule/events", middleware.AuthMiddleware(), middleware.RequirePermission("notify:rule:read"), ListAlertEvents)
+	r.GET("/alert/rule/events/:id", middleware.AuthMiddleware(), middleware.RequirePermission("notify:rule:read"), GetAlertEvent)
+	r.POST("/alert/rule/events/:id/resolve", middleware.AuthMiddleware(), middleware.RequirePermission("notify:rule:ack"), ResolveAlertEvent)
+	r.POST("/alert/rule/events/:id/ack", middleware.AuthMiddleware(), middleware.RequirePermission("notify:rule:ack"), AckAlertEvent)
+	r.GET("/alert/rule/deliveries", middleware.AuthMiddleware(), middleware.RequirePermission("notify:rule:read"), ListAlertDeliveries)
+	r.POST("/alert/rule/deliveries/:id/retry", middleware.AuthMiddleware(), middleware.RequirePermission("notify:rule:retry"), RetryAlertDelivery)
+	r.GET("/alert/rule/:id", middleware.AuthMiddleware(), middleware.RequirePermission("notify:rule:read"), GetAlertRule)
+	r.POST("/alert/rule", middleware.AuthMiddleware(), middleware.RequirePermission("notify:rule:create"), CreateAlertRule)
+	r.PUT("/alert/rule/:id", middleware.AuthMiddleware(), middleware.RequirePermission("notify:rule:update"), UpdateAlertRule)
+	r.DELETE("/alert/rule/:id", middleware.AuthMiddleware(), middleware.RequirePermission("notify:rule:delete"), DeleteAlertRule)
+}
@@ -0,0 +1,124 @@
+package alert
+
+import (
+	"net/http"
+	"octoops/internal/middleware"
+	alertingService "octoops/internal/service/alerting"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListAlertSilences 静默列表，active=true 时只返回当前生效的静默
+func ListAlertSilences(c *gin.Context) {
+	silences, err := alertingService.ListSilences(c.Query("active") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询静默失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, silences)
+}
+
+// CreateAlertSilence 新增静默
+func CreateAlertSilence(c *gin.Context) {
+	var req alertingService.SilenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	operator := ""
+	if user := middleware.GetCurrentUser(c); user != nil {
+		operator = user.Username
+	}
+	silence, err := alertingService.CreateSilence(req, operator)
+	if err != nil {
+		writeRuleError(c, err, "创建静默失败")
+		return
+	}
+	c.JSON(http.StatusOK, silence)
+}
+
+// UpdateAlertSilence 更新静默
+func UpdateAlertSilence(c *gin.Context) {
+	var req alertingService.SilenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	silence, err := alertingService.UpdateSilence(c.Param("id"), req)
+	if err != nil {
+		writeRuleError(c, err, "更新静默失败")
+		return
+	}
+	c.JSON(http.StatusOK, silence)
+}
+
+// DeleteAlertSilence 删除静默
+func DeleteAlertSilence(c *gin.Context) {
+	if err := alertingService.DeleteSilence(c.Param("id")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "删除失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// ListAlertEscalations 升级策略列表
+func ListAlertEscalations(c *gin.Context) {
+	escalations, err := alertingService.ListEscalations()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询升级策略失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, escalations)
+}
+
+// CreateAlertEscalation 新增升级策略
+func CreateAlertEscalation(c *gin.Context) {
+	var req alertingService.EscalationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	escalation, err := alertingService.CreateEscalation(req)
+	if err != nil {
+		writeRuleError(c, err, "创建升级策略失败")
+		return
+	}
+	c.JSON(http.StatusOK, escalation)
+}
+
+// UpdateAlertEscalation 更新升级策略
+func UpdateAlertEscalation(c *gin.Context) {
+	var req alertingService.EscalationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	escalation, err := alertingService.UpdateEscalation(c.Param("id"), req)
+	if err != nil {
+		writeRuleError(c, err, "更新升级策略失败")
+		return
+	}
+	c.JSON(http.StatusOK, escalation)
+}
+
+// DeleteAlertEscalation 删除升级策略
+func DeleteAlertEscalation(c *gin.Context) {
+	if err := alertingService.DeleteEscalation(c.Param("id")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "删除失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// RegisterAlertSilenceRoutes 静默与升级策略路由注册
+func RegisterAlertSilenceRoutes(r *gin.RouterGroup) {
+	r.GET("/alert/silence", middleware.AuthMiddleware(), middleware.RequirePermission("notify:silence:read"), ListAlertSilences)
+	r.POST("/alert/silence", middleware.AuthMiddleware(), middleware.RequirePermission("notify:silence:create"), CreateAlertSilence)
+	r.PUT("/alert/silence/:id", middleware.AuthMiddleware(), middleware.RequirePermission("notify:silence:update"), UpdateAlertSilence)
+	r.DELETE("/alert/silence/:id", middleware.AuthMiddleware(), middleware.RequirePermission("notify:silence:delete"), DeleteAlertSilence)
+
+	r.GET("/alert/escalation", middleware.AuthMiddleware(), middleware.RequirePermission("notify:escalation:read"), ListAlertEscalations)
+	r.POST("/alert/escalation", middleware.AuthMiddleware(), middleware.RequirePermission("notify:escalation:create"), CreateAlertEscalation)
+	r.PUT("/alert/escalation/:id", middleware.AuthMiddleware(), middleware.RequirePermission("notify:escalation:update"), UpdateAlertEscalation)
+	r.DELETE("/alert/escalation/:id", middleware.AuthMiddleware(), middleware.RequirePermission("notify:escalation:delete"), DeleteAlertEscalation)
+}
@@ -1,17 +1,50 @@
 package alert
 
 import (
+	"errors"
 	"net/http"
 	"octoops/internal/middleware"
 	alertModel "octoops/internal/model/alert"
 	alertService "octoops/internal/service/alert"
+	alertingService "octoops/internal/service/alerting"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type alertTemplateReq struct {
-	Name    string `json:"name" binding:"required"`
-	Content string `json:"content" binding:"required"`
+	Name     string                                `json:"name" binding:"required"`
+	Kind     string                                `json:"kind"` // alert/digest/summary，默认 alert
+	Title    string                                `json:"title"`
+	Content  string                                `json:"content" binding:"required"`
+	Variants map[string]alertModel.TemplateVariant `json:"variants"` // 按渠道类型覆盖，如 email、dingtalk
+}
+
+// alertTemplatePreviewReq 预览已保存的模板（template_id）或未保存的模板内容；task_id 仅用于单条告警模板，为空时使用示例数据
+type alertTemplatePreviewReq struct {
+	TemplateID   uint                                  `json:"template_id"`
+	Kind         string                                `json:"kind"`
+	Title        string                                `json:"title"`
+	Content      string                                `json:"content"`
+	Variants     map[string]alertModel.TemplateVariant `json:"variants"`
+	ChannelTypes []string                              `json:"channel_types"`
+	TaskID       uint                                  `json:"task_id"`
+}
+
+func writeTemplateError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	case errors.Is(err, alertService.ErrInvalidTemplate):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, alertService.ErrTemplateInUse):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, alertingService.ErrPreviewForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message + ": " + err.Error()})
+	}
 }
 
 // ListAlertTemplates 告警模板列表
@@ -32,11 +65,14 @@ func CreateAlertTemplate(c *gin.Context) {
 		return
 	}
 	tpl := alertModel.AlertTemplate{
-		Name:    req.Name,
-		Content: req.Content,
+		Name:     req.Name,
+		Kind:     req.Kind,
+		Title:    req.Title,
+		Content:  req.Content,
+		Variants: req.Variants,
 	}
 	if err := alertService.CreateAlertTemplate(&tpl); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "创建模板失败: " + err.Error()})
+		writeTemplateError(c, err, "创建模板失败")
 		return
 	}
 	c.JSON(http.StatusOK, tpl)
@@ -51,11 +87,14 @@ func UpdateAlertTemplate(c *gin.Context) {
 		return
 	}
 	tpl, err := alertService.UpdateAlertTemplate(id, alertModel.AlertTemplate{
-		Name:    req.Name,
-		Content: req.Content,
+		Name:     req.Name,
+		Kind:     req.Kind,
+		Title:    req.Title,
+		Content:  req.Content,
+		Variants: req.Variants,
 	})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		writeTemplateError(c, err, "更新模板失败")
 		return
 	}
 	c.JSON(http.StatusOK, tpl)
@@ -65,16 +104,61 @@ func UpdateAlertTemplate(c *gin.Context) {
 func DeleteAlertTemplate(c *gin.Context) {
 	id := c.Param("id")
 	if err := alertService.DeleteAlertTemplate(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "删除失败: " + err.Error()})
+		writeTemplateError(c, err, "删除失败")
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
 }
 
+// PreviewAlertTemplate 按渠道类型预览模板渲染结果，如邮件 HTML、钉钉 Markdown
+func PreviewAlertTemplate(c *gin.Context) {
+	var req alertTemplatePreviewReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	tpl := alertModel.AlertTemplate{
+		Name:     "preview",
+		Kind:     req.Kind,
+		Title:    req.Title,
+		Content:  req.Content,
+		Variants: req.Variants,
+	}
+	if req.TemplateID > 0 {
+		saved, err := alertService.GetAlertTemplateByID(strconv.FormatUint(uint64(req.TemplateID), 10))
+		if err != nil {
+			writeTemplateError(c, err, "查询模板失败")
+			return
+		}
+		tpl = saved
+	}
+	if err := alertService.ValidateTemplate(tpl); err != nil {
+		writeTemplateError(c, err, "预览模板失败")
+		return
+	}
+	// 摘要与每日汇总模板使用示例数据
+	var data interface{}
+	if tpl.Kind == "" || tpl.Kind == alertService.TemplateKindAlert {
+		user := middleware.GetCurrentUser(c)
+		taskData, err := alertingService.PreviewData(req.TaskID, func(code string) bool {
+			return middleware.HasPermission(user, code)
+		})
+		if err != nil {
+			writeTemplateError(c, err, "查询任务失败")
+			return
+		}
+		data = taskData
+	} else {
+		data = alertService.SampleData(tpl.Kind)
+	}
+	c.JSON(http.StatusOK, gin.H{"data": data, "previews": alertService.Preview(tpl, req.ChannelTypes, data)})
+}
+
 // RegisterAlertTemplateRoutes 路由注册
 func RegisterAlertTemplateRoutes(r *gin.RouterGroup) {
 	r.GET("/alert/template", middleware.AuthMiddleware(), middleware.RequirePermission("notify:template:read"), ListAlertTemplates)
 	r.POST("/alert/template", middleware.AuthMiddleware(), middleware.RequirePermission("notify:template:create"), CreateAlertTemplate)
+	r.POST("/alert/template/preview", middleware.AuthMiddleware(), middleware.RequirePermission("notify:template:read"), PreviewAlertTemplate)
 	r.PUT("/alert/template/:id", middleware.AuthMiddleware(), middleware.RequirePermission("notify:template:update"), UpdateAlertTemplate)
 	r.DELETE("/alert/template/:id", middleware.AuthMiddleware(), middleware.RequirePermission("notify:template:delete"), DeleteAlertTemplate)
 }
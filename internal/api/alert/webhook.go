@@ -0,0 +1,141 @@
+package alert
+
+import (
+	"errors"
+	"net/http"
+	"octoops/internal/middleware"
+	webhookService "octoops/internal/service/webhook"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func writeWebhookError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	case errors.Is(err, webhookService.ErrInvalidSubscription):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, webhookService.ErrDeliveryPending):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message + ": " + err.Error()})
+	}
+}
+
+// ListWebhooks 获取所有 Webhook 订阅
+func ListWebhooks(c *gin.Context) {
+	subs, err := webhookService.ListSubscriptions()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询Webhook失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, subs)
+}
+
+// GetWebhook 获取单个 Webhook 订阅
+func GetWebhook(c *gin.Context) {
+	sub, err := webhookService.GetSubscription(c.Param("id"))
+	if err != nil {
+		writeWebhookError(c, err, "查询Webhook失败")
+		return
+	}
+	c.JSON(http.StatusOK, sub)
+}
+
+// ListWebhookEventTypes 可订阅的事件类型
+func ListWebhookEventTypes(c *gin.Context) {
+	c.JSON(http.StatusOK, webhookService.EventTypes())
+}
+
+// CreateWebhook 新增 Webhook 订阅
+func CreateWebhook(c *gin.Context) {
+	var req webhookService.SubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sub, err := webhookService.CreateSubscription(req)
+	if err != nil {
+		writeWebhookError(c, err, "创建Webhook失败")
+		return
+	}
+	c.JSON(http.StatusOK, sub)
+}
+
+// UpdateWebhook 更新 Webhook 订阅
+func UpdateWebhook(c *gin.Context) {
+	var req webhookService.SubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sub, err := webhookService.UpdateSubscription(c.Param("id"), req)
+	if err != nil {
+		writeWebhookError(c, err, "更新Webhook失败")
+		return
+	}
+	c.JSON(http.StatusOK, sub)
+}
+
+// DeleteWebhook 删除 Webhook 订阅
+func DeleteWebhook(c *gin.Context) {
+	if err := webhookService.DeleteSubscription(c.Param("id")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "删除失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// TestWebhook 投递一条 ping 测试事件
+func TestWebhook(c *gin.Context) {
+	delivery, err := webhookService.SendPing(c.Param("id"))
+	if err != nil {
+		writeWebhookError(c, err, "测试Webhook失败")
+		return
+	}
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+// ListWebhookDeliveries 投递记录，可按 subscription_id、status 过滤
+func ListWebhookDeliveries(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	subscriptionID, _ := strconv.ParseUint(c.Query("subscription_id"), 10, 64)
+	deliveries, total, err := webhookService.ListDeliveries(uint(subscriptionID), c.Query("status"), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询投递记录失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": deliveries, "total": total})
+}
+
+// RedeliverWebhook 按原内容重新投递
+func RedeliverWebhook(c *gin.Context) {
+	delivery, err := webhookService.Redeliver(c.Param("id"))
+	if err != nil {
+		writeWebhookError(c, err, "重新投递失败")
+		return
+	}
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+// RegisterWebhookRoutes 路由注册
+func RegisterWebhookRoutes(r *gin.RouterGroup) {
+	r.GET("/alert/webhook", middleware.AuthMiddleware(), middleware.RequirePermission("notify:webhook:read"), ListWebhooks)
+	r.GET("/alert/webhook/event-types", middleware.AuthMiddleware(), middleware.RequirePermission("notify:webhook:read"), ListWebhookEventTypes)
+	r.GET("/alert/webhook/deliveries", middleware.AuthMiddleware(), middleware.RequirePermission("notify:webhook:read"), ListWebhookDeliveries)
+	r.POST("/alert/webhook/deliveries/:id/redeliver", middleware.AuthMiddleware(), middleware.RequirePermission("notify:webhook:redeliver"), RedeliverWebhook)
+	r.GET("/alert/webhook/:id", middleware.AuthMiddleware(), middleware.RequirePermission("notify:webhook:read"), GetWebhook)
+	r.POST("/alert/webhook", middleware.AuthMiddleware(), middleware.RequirePermission("notify:webhook:create"), CreateWebhook)
+	r.PUT("/alert/webhook/:id", middleware.AuthMiddleware(), middleware.RequirePermission("notify:webhook:update"), UpdateWebhook)
+	r.DELETE("/alert/webhook/:id", middleware.AuthMiddleware(), middleware.RequirePermission("notify:webhook:delete"), DeleteWebhook)
+	r.POST("/alert/webhook/:id/test", middleware.AuthMiddleware(), middleware.RequirePermission("notify:webhook:test"), TestWebhook)
+}
@@ -0,0 +1,79 @@
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"octoops/internal/middleware"
+	"octoops/internal/scheduler"
+	alertService "octoops/internal/service/alert"
+	bundleService "octoops/internal/service/bundle"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportBundle 导出 ETL 任务、自定义任务及告警配置，format=yaml|json
+func ExportBundle(c *gin.Context) {
+	format := c.DefaultQuery("format", "yaml")
+	if format != "yaml" && format != "json" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format 仅支持 yaml 或 json"})
+		return
+	}
+	b, err := bundleService.Export()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "导出失败: " + err.Error()})
+		return
+	}
+	data, err := bundleService.Marshal(b, format)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "导出失败: " + err.Error()})
+		return
+	}
+	contentType := "application/x-yaml; charset=utf-8"
+	if format == "json" {
+		contentType = "application/json; charset=utf-8"
+	}
+	filename := fmt.Sprintf("octoops-bundle-%s.%s", time.Now().Format("20060102150405"), format)
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// ImportBundle 导入导出包，dry_run=true 时仅返回导入计划，conflict=skip|overwrite|rename
+func ImportBundle(c *gin.Context) {
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil || len(data) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "导入内容不能为空"})
+		return
+	}
+	b, err := bundleService.Parse(data)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	dryRun := c.Query("dry_run") == "true"
+	result, err := bundleService.Import(b, c.Query("conflict"), dryRun)
+	if err != nil {
+		if errors.Is(err, bundleService.ErrInvalidConflict) || errors.Is(err, alertService.ErrInvalidTemplate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "导入失败: " + err.Error()})
+		return
+	}
+	if !dryRun {
+		log.Printf("[Bundle] 导入完成: 新建 %d, 覆盖 %d, 跳过 %d", result.Created, result.Updated, result.Skipped)
+		// 调度器被手动停止时不重新加载，避免导入操作意外恢复调度
+		if scheduler.IsRunning() {
+			scheduler.ReloadTasks()
+		}
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func RegisterBundleRoutes(r *gin.RouterGroup) {
+	r.GET("/bundle/export", middleware.AuthMiddleware(), middleware.RequirePermission("task:bundle:export"), ExportBundle)
+	r.POST("/bundle/import", middleware.AuthMiddleware(), middleware.RequirePermission("task:bundle:import"), ImportBundle)
+}
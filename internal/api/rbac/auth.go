@@ -2,11 +2,13 @@ package rbac
 
 import (
 	"net/http"
+	"octoops/internal/event"
 	"octoops/internal/infra/postgres"
 	"octoops/internal/middleware"
 	"octoops/internal/model/rbac"
 	"octoops/internal/pkg/jwt"
 	"octoops/internal/utils"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -122,6 +124,13 @@ func login(c *gin.Context) {
 		return
 	}
 
+	event.PublishDurable(event.UserLoggedIn{
+		UserID:   user.ID,
+		Username: user.Username,
+		IP:       c.ClientIP(),
+		At:       time.Now(),
+	})
+
 	// 获取用户权限
 	permissions := middleware.GetUserPermissions(&user)
 
@@ -0,0 +1,99 @@
+package realtime
+
+import (
+	"io"
+	"net/http"
+	"octoops/internal/event"
+	"octoops/internal/middleware"
+	realtimeService "octoops/internal/service/realtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// 心跳间隔，避免代理因空闲断开连接
+	heartbeatInterval = 30 * time.Second
+	// 权限判断结果的缓存时间，角色变更最迟在该时间后生效
+	permissionCacheTTL = time.Minute
+)
+
+// permissionCache 缓存当前连接用户的权限判断结果，避免每个事件都查询数据库
+type permissionCache struct {
+	mu      sync.Mutex
+	check   func(code string) bool
+	results map[string]bool
+	expires time.Time
+}
+
+func (p *permissionCache) allowed(code string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if time.Now().After(p.expires) {
+		p.results = map[string]bool{}
+		p.expires = time.Now().Add(permissionCacheTTL)
+	}
+	ok, cached := p.results[code]
+	if !cached {
+		ok = p.check(code)
+		p.results[code] = ok
+	}
+	return ok
+}
+
+// StreamEvents 以 SSE 推送任务状态变化、调度运行及告警发送事件，按用户权限过滤；
+// types 参数可限定事件类型，多个以逗号分隔
+func StreamEvents(c *gin.Context) {
+	user := middleware.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户未认证"})
+		return
+	}
+	types := map[string]bool{}
+	for _, t := range strings.Split(c.Query("types"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types[t] = true
+		}
+	}
+
+	perms := &permissionCache{check: func(code string) bool {
+		return middleware.HasPermission(user, code)
+	}}
+	events, unsubscribe := realtimeService.Subscribe(perms.allowed)
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	c.SSEvent("ready", gin.H{"time": time.Now()})
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case <-heartbeat.C:
+			c.SSEvent("ping", gin.H{"time": time.Now()})
+		case ev := <-events:
+			if len(types) == 0 || types[ev.Type] {
+				c.SSEvent(ev.Type, ev)
+			}
+		}
+		return true
+	})
+}
+
+// GetEventMetrics 各类领域事件的发布与处理计数
+func GetEventMetrics(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": event.Stats()})
+}
+
+func RegisterRealtimeRoutes(r *gin.RouterGroup) {
+	r.GET("/events/stream", middleware.AuthMiddleware(), StreamEvents)
+	r.GET("/events/metrics", middleware.AuthMiddleware(), middleware.RequirePermission("task:scheduler:status"), GetEventMetrics)
+}
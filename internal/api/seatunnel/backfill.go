@@ -0,0 +1,117 @@
+package seatunnel
+
+import (
+	"errors"
+	"net/http"
+	"octoops/internal/middleware"
+	seatunnelService "octoops/internal/service/seatunnel"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateBackfill 为离线任务创建补数作业
+func CreateBackfill(c *gin.Context) {
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid task id"})
+		return
+	}
+	var req seatunnelService.BackfillRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	operator := ""
+	if user := middleware.GetCurrentUser(c); user != nil {
+		operator = user.Username
+	}
+	job, err := seatunnelService.CreateBackfill(uint(taskID), req, operator)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "创建补数作业失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// ListBackfills 补数作业列表
+func ListBackfills(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	taskID, _ := strconv.ParseUint(c.Query("task_id"), 10, 64)
+	jobs, total, err := seatunnelService.ListBackfills(uint(taskID), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询补数作业失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  jobs,
+		"total": total,
+	})
+}
+
+// GetBackfill 补数作业详情（含分片状态）
+func GetBackfill(c *gin.Context) {
+	job, slices, err := seatunnelService.GetBackfill(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询补数作业失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"job":    job,
+		"slices": slices,
+	})
+}
+
+func PauseBackfill(c *gin.Context) {
+	changeBackfillState(c, seatunnelService.PauseBackfill, "补数作业已暂停")
+}
+
+func ResumeBackfill(c *gin.Context) {
+	changeBackfillState(c, seatunnelService.ResumeBackfill, "补数作业已恢复")
+}
+
+func CancelBackfill(c *gin.Context) {
+	changeBackfillState(c, seatunnelService.CancelBackfill, "补数作业已取消")
+}
+
+func changeBackfillState(c *gin.Context, action func(uint) error, message string) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的ID"})
+		return
+	}
+	if err := action(uint(id)); err != nil {
+		if errors.Is(err, seatunnelService.ErrBackfillInvalidState) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
+
+func RegisterBackfillRoutes(r *gin.RouterGroup) {
+	r.POST("/seatunnel/batch/:id/backfill", middleware.AuthMiddleware(), middleware.RequirePermission("etl:batch:backfill"), CreateBackfill)
+	r.GET("/seatunnel/backfill", middleware.AuthMiddleware(), middleware.RequirePermission("etl:batch:read"), ListBackfills)
+	r.GET("/seatunnel/backfill/:id", middleware.AuthMiddleware(), middleware.RequirePermission("etl:batch:read"), GetBackfill)
+	r.POST("/seatunnel/backfill/:id/pause", middleware.AuthMiddleware(), middleware.RequirePermission("etl:batch:backfill"), PauseBackfill)
+	r.POST("/seatunnel/backfill/:id/resume", middleware.AuthMiddleware(), middleware.RequirePermission("etl:batch:backfill"), ResumeBackfill)
+	r.POST("/seatunnel/backfill/:id/cancel", middleware.AuthMiddleware(), middleware.RequirePermission("etl:batch:backfill"), CancelBackfill)
+}
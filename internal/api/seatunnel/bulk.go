@@ -0,0 +1,96 @@
+package seatunnel
+
+import (
+	"errors"
+	"net/http"
+	"octoops/internal/middleware"
+	bulkService "octoops/internal/service/bulk"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CreateBulkOperation 创建批量操作，异步执行并返回操作 ID，按单任务权限跳过无权操作的任务
+func CreateBulkOperation(c *gin.Context) {
+	var req bulkService.Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	user := middleware.GetCurrentUser(c)
+	operator := ""
+	if user != nil {
+		operator = user.Username
+	}
+	op, err := bulkService.Create(req, operator, func(code string) bool {
+		return middleware.HasPermission(user, code)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, bulkService.ErrInvalidRequest), errors.Is(err, bulkService.ErrNoTasks):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "创建批量操作失败: " + err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusAccepted, op)
+}
+
+// ListBulkOperations 批量操作列表
+func ListBulkOperations(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	ops, total, err := bulkService.List(page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询批量操作失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": ops, "total": total})
+}
+
+// GetBulkOperation 批量操作进度及各任务执行结果
+func GetBulkOperation(c *gin.Context) {
+	op, items, err := bulkService.Get(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询批量操作失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"operation": op, "items": items})
+}
+
+// CancelBulkOperation 取消批量操作
+func CancelBulkOperation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的ID"})
+		return
+	}
+	if err := bulkService.Cancel(uint(id)); err != nil {
+		if errors.Is(err, bulkService.ErrInvalidState) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "取消批量操作失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "canceled"})
+}
+
+func RegisterBulkRoutes(r *gin.RouterGroup) {
+	r.POST("/seatunnel/bulk", middleware.AuthMiddleware(), middleware.RequirePermission("etl:bulk:create"), CreateBulkOperation)
+	r.GET("/seatunnel/bulk", middleware.AuthMiddleware(), middleware.RequirePermission("etl:bulk:read"), ListBulkOperations)
+	r.GET("/seatunnel/bulk/:id", middleware.AuthMiddleware(), middleware.RequirePermission("etl:bulk:read"), GetBulkOperation)
+	r.POST("/seatunnel/bulk/:id/cancel", middleware.AuthMiddleware(), middleware.RequirePermission("etl:bulk:create"), CancelBulkOperation)
+}
@@ -0,0 +1,42 @@
+package seatunnel
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"octoops/internal/middleware"
+	seatunnelService "octoops/internal/service/seatunnel"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MigrateEncryptConfig 批量加密存量任务配置，仅处理当前用户有权限的任务类型
+func MigrateEncryptConfig(c *gin.Context) {
+	var req struct {
+		TaskIDs []uint `json:"task_ids"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user := middleware.GetCurrentUser(c)
+	var taskTypes []string
+	if middleware.HasPermission(user, "etl:stream:encrypt_config") {
+		taskTypes = append(taskTypes, "stream")
+	}
+	if middleware.HasPermission(user, "etl:batch:encrypt_config") {
+		taskTypes = append(taskTypes, "batch")
+	}
+
+	results, err := seatunnelService.EncryptStoredConfigs(req.TaskIDs, taskTypes)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询任务失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": results, "total": len(results)})
+}
+
+func RegisterConfigEncryptRoutes(r *gin.RouterGroup) {
+	r.POST("/seatunnel/tasks/encrypt-config/migrate", middleware.AuthMiddleware(), middleware.RequireAnyPermission("etl:stream:encrypt_config", "etl:batch:encrypt_config"), MigrateEncryptConfig)
+}
@@ -0,0 +1,118 @@
+package seatunnel
+
+import (
+	"errors"
+	"net/http"
+	"octoops/internal/middleware"
+	seatunnelService "octoops/internal/service/seatunnel"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ListDatasources 数据源列表（不返回敏感参数）
+func ListDatasources(c *gin.Context) {
+	list, err := seatunnelService.ListDatasources(c.Query("type"), c.Query("name"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询数据源失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// GetDatasource 数据源详情（不返回敏感参数）
+func GetDatasource(c *gin.Context) {
+	ds, err := seatunnelService.GetDatasourceByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return
+	}
+	c.JSON(http.StatusOK, seatunnelService.ToDatasourceView(ds))
+}
+
+// CreateDatasource 新增数据源
+func CreateDatasource(c *gin.Context) {
+	var req seatunnelService.DatasourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Name == "" || req.Type == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name 和 type 不能为空"})
+		return
+	}
+	ds, err := seatunnelService.CreateDatasource(req)
+	if err != nil {
+		if errors.Is(err, seatunnelService.ErrDatasourceNameInvalid) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, seatunnelService.ErrDatasourceNameExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "创建数据源失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, seatunnelService.ToDatasourceView(ds))
+}
+
+// UpdateDatasource 更新数据源
+func UpdateDatasource(c *gin.Context) {
+	var req seatunnelService.DatasourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ds, err := seatunnelService.UpdateDatasource(c.Param("id"), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		case errors.Is(err, seatunnelService.ErrDatasourceNameInvalid):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, seatunnelService.ErrDatasourceInUse), errors.Is(err, seatunnelService.ErrDatasourceNameExists):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "更新数据源失败: " + err.Error()})
+		}
+		return
+	}
+	ds, _ = seatunnelService.GetDatasourceByID(ds.ID)
+	c.JSON(http.StatusOK, seatunnelService.ToDatasourceView(ds))
+}
+
+// DeleteDatasource 删除数据源，被任务配置引用时不允许删除
+func DeleteDatasource(c *gin.Context) {
+	if err := seatunnelService.DeleteDatasource(c.Param("id")); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		case errors.Is(err, seatunnelService.ErrDatasourceInUse):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "删除失败: " + err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// TestDatasource 测试数据源连接
+func TestDatasource(c *gin.Context) {
+	ds, err := seatunnelService.GetDatasourceByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return
+	}
+	c.JSON(http.StatusOK, seatunnelService.TestDatasourceConnection(ds))
+}
+
+func RegisterDatasourceRoutes(r *gin.RouterGroup) {
+	r.GET("/seatunnel/datasource", middleware.AuthMiddleware(), middleware.RequirePermission("etl:datasource:read"), ListDatasources)
+	r.GET("/seatunnel/datasource/:id", middleware.AuthMiddleware(), middleware.RequirePermission("etl:datasource:read"), GetDatasource)
+	r.POST("/seatunnel/datasource", middleware.AuthMiddleware(), middleware.RequirePermission("etl:datasource:create"), CreateDatasource)
+	r.PUT("/seatunnel/datasource/:id", middleware.AuthMiddleware(), middleware.RequirePermission("etl:datasource:update"), UpdateDatasource)
+	r.DELETE("/seatunnel/datasource/:id", middleware.AuthMiddleware(), middleware.RequirePermission("etl:datasource:delete"), DeleteDatasource)
+	r.POST("/seatunnel/datasource/:id/test", middleware.AuthMiddleware(), middleware.RequirePermission("etl:datasource:test"), TestDatasource)
+}
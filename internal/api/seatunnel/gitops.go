@@ -0,0 +1,69 @@
+package seatunnel
+
+import (
+	"errors"
+	"net/http"
+	"octoops/internal/middleware"
+	"octoops/internal/scheduler"
+	gitopsService "octoops/internal/service/gitops"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// managedTaskWarning 托管任务在界面修改后会在下次同步时被定义文件覆盖
+const managedTaskWarning = "任务由 GitOps 目录管理，界面修改将在下次同步时被定义文件覆盖"
+
+// ReconcileGitOps 手动触发 GitOps 同步
+func ReconcileGitOps(c *gin.Context) {
+	report, changed, err := gitopsService.Reconcile("manual")
+	if err != nil {
+		switch {
+		case errors.Is(err, gitopsService.ErrGitOpsDisabled):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, gitopsService.ErrReconcileRunning):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "GitOps 同步失败: " + err.Error()})
+		}
+		return
+	}
+	for _, task := range changed {
+		scheduler.RefreshTask(task)
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// ListGitOpsReports 同步报告列表
+func ListGitOpsReports(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	reports, total, err := gitopsService.ListReports(page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询同步报告失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": reports, "total": total})
+}
+
+// GetGitOpsReport 同步报告详情
+func GetGitOpsReport(c *gin.Context) {
+	report, err := gitopsService.GetReport(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+func RegisterGitOpsRoutes(r *gin.RouterGroup) {
+	r.POST("/seatunnel/gitops/reconcile", middleware.AuthMiddleware(), middleware.RequirePermission("etl:gitops:reconcile"), ReconcileGitOps)
+	r.GET("/seatunnel/gitops/reports", middleware.AuthMiddleware(), middleware.RequirePermission("etl:gitops:read"), ListGitOpsReports)
+	r.GET("/seatunnel/gitops/reports/:id", middleware.AuthMiddleware(), middleware.RequirePermission("etl:gitops:read"), GetGitOpsReport)
+}
@@ -1,6 +1,7 @@
 package seatunnel
 
 import (
+	"errors"
 	"net/http"
 	seatunnelModel "octoops/internal/model/seatunnel"
 	"octoops/internal/scheduler"
@@ -87,17 +88,34 @@ func createTask(c *gin.Context, fixedTaskType string) {
 		}
 		task.TaskType = fixedTaskType
 	}
-	if err := seatunnelService.CreateTask(&task); err != nil {
+	// 托管标记仅由 GitOps 同步维护
+	task.Managed = false
+	task.ManagedSource = ""
+	if saveNewTask(c, &task) {
+		c.JSON(http.StatusOK, task)
+	}
+}
+
+// saveNewTask 保存新任务并加入调度，失败时已写入错误响应
+func saveNewTask(c *gin.Context, task *seatunnelModel.EtlTask) bool {
+	if err := seatunnelService.ValidateCluster(task.Cluster); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	if err := seatunnelService.PrepareTaskConfig(task); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "加密任务配置失败: " + err.Error()})
+		return false
+	}
+	if err := seatunnelService.CreateTask(task); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "创建任务失败: " + err.Error()})
-		return
+		return false
 	}
 
 	// 如果是批处理任务且状态为active且有cron表达式，添加到调度器
 	if task.TaskType == "batch" && task.Status == 1 && task.CronExpr != "" {
-		scheduler.AddTask(task)
+		scheduler.AddTask(*task)
 	}
-
-	c.JSON(http.StatusOK, task)
+	return true
 }
 
 func deleteTask(c *gin.Context, fixedTaskType string) {
@@ -112,6 +130,10 @@ func deleteTask(c *gin.Context, fixedTaskType string) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
 		return
 	}
+	if task.Managed && c.Query("force") != "true" {
+		c.JSON(http.StatusConflict, gin.H{"error": "任务由 GitOps 目录管理，请删除定义文件，或使用 force=true 强制删除"})
+		return
+	}
 
 	// 从调度器中移除任务
 	if task.TaskType == "batch" && task.Status == 1 {
@@ -153,6 +175,10 @@ func updateTaskWithScheduler(c *gin.Context, fixedTaskType string) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
 		return
 	}
+	if dbTask.Managed && c.Query("force") != "true" {
+		c.JSON(http.StatusConflict, gin.H{"error": "任务由 GitOps 目录管理，请修改定义文件，或使用 force=true 强制修改"})
+		return
+	}
 	oldStatus := dbTask.Status
 
 	var req map[string]interface{}
@@ -163,6 +189,22 @@ func updateTaskWithScheduler(c *gin.Context, fixedTaskType string) {
 	// 保证ID和JobID不变
 	req["id"] = dbTask.ID
 	req["task_type"] = dbTask.TaskType
+	delete(req, "managed")
+	delete(req, "managed_source")
+	if cluster, ok := req["cluster"].(string); ok {
+		if err := seatunnelService.ValidateCluster(cluster); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if err := seatunnelService.PrepareTaskConfigUpdate(dbTask, req); err != nil {
+		if errors.Is(err, seatunnelService.ErrEncryptedConfigEdit) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "加密任务配置失败: " + err.Error()})
+		return
+	}
 	if err := seatunnelService.UpdateTask(&dbTask, req); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "更新任务失败: " + err.Error()})
 		return
@@ -191,6 +233,9 @@ func updateTaskWithScheduler(c *gin.Context, fixedTaskType string) {
 		}
 	}
 
+	if dbTask.Managed {
+		req["warning"] = managedTaskWarning
+	}
 	c.JSON(http.StatusOK, req)
 }
 
@@ -0,0 +1,121 @@
+package seatunnel
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"octoops/internal/middleware"
+	seatunnel "octoops/internal/service/seatunnel"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SSE 推送的轮询间隔及最长连接时间，超时后客户端可重新订阅
+const (
+	operationEventInterval = time.Second
+	operationEventMaxAge   = 5 * time.Minute
+)
+
+func writeTaskOperationError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, seatunnel.ErrTaskRunning), errors.Is(err, seatunnel.ErrSavePointJobIDMissing), errors.Is(err, seatunnel.ErrJobIDEmpty):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, seatunnel.ErrOperationInProgress):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message + ": " + err.Error()})
+	}
+}
+
+// ListTaskOperations 提交/停止操作列表，可按 task_id 过滤
+func ListTaskOperations(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	taskID, _ := strconv.ParseUint(c.Query("task_id"), 10, 64)
+	ops, total, err := seatunnel.ListTaskOperations(uint(taskID), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询任务操作失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": ops, "total": total})
+}
+
+// GetTaskOperation 查询单个操作进度
+func GetTaskOperation(c *gin.Context) {
+	op, err := seatunnel.GetTaskOperation(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询任务操作失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, op)
+}
+
+// StreamTaskOperation 以 SSE 推送操作进度，状态变化时发送 operation 事件，进入终态后关闭连接
+func StreamTaskOperation(c *gin.Context) {
+	op, err := seatunnel.GetTaskOperation(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询任务操作失败: " + err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	ticker := time.NewTicker(operationEventInterval)
+	defer ticker.Stop()
+	deadline := time.After(operationEventMaxAge)
+	sent := false
+	lastUpdated := op.UpdatedAt
+
+	c.Stream(func(w io.Writer) bool {
+		if !sent {
+			sent = true
+			c.SSEvent("operation", op)
+			return !seatunnel.IsOperationFinished(op.Status)
+		}
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case <-deadline:
+			return false
+		case <-ticker.C:
+		}
+		latest, err := seatunnel.GetTaskOperation(op.ID)
+		if err != nil {
+			c.SSEvent("error", gin.H{"error": err.Error()})
+			return false
+		}
+		if latest.UpdatedAt.Equal(lastUpdated) {
+			return true
+		}
+		lastUpdated = latest.UpdatedAt
+		c.SSEvent("operation", latest)
+		return !seatunnel.IsOperationFinished(latest.Status)
+	})
+}
+
+func RegisterTaskOperationRoutes(r *gin.RouterGroup) {
+	read := middleware.RequireAnyPermission("etl:stream:read", "etl:batch:read")
+	r.GET("/seatunnel/operations", middleware.AuthMiddleware(), read, ListTaskOperations)
+	r.GET("/seatunnel/operations/:id", middleware.AuthMiddleware(), read, GetTaskOperation)
+	r.GET("/seatunnel/operations/:id/events", middleware.AuthMiddleware(), read, StreamTaskOperation)
+}
@@ -1,40 +1,18 @@
 package seatunnel
 
 import (
+	"errors"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
-	"octoops/internal/config"
 	"octoops/internal/infra/postgres"
 	"octoops/internal/middleware"
 	seatunnelModel "octoops/internal/model/seatunnel"
 	seatunnel "octoops/internal/service/seatunnel"
-	"strings"
-	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-func waitForTaskStatus(taskID uint, attempts int, interval time.Duration, shouldStop func(string) bool) string {
-	jobStatus := "UNKNOWN"
-	for i := 0; i < attempts; i++ {
-		status, syncErr := seatunnel.SyncJobStatusByTaskID(taskID)
-		if syncErr != nil {
-			log.Printf("[ETL] 同步作业状态失败: taskID=%d, error=%v", taskID, syncErr)
-		} else {
-			jobStatus = status
-			if shouldStop(status) {
-				return jobStatus
-			}
-		}
-		if i < attempts-1 {
-			time.Sleep(interval)
-		}
-	}
-	return jobStatus
-}
-
 func requireTaskActionPermission(c *gin.Context, taskType, action string) bool {
 	user := middleware.GetCurrentUser(c)
 	if user == nil {
@@ -71,45 +49,41 @@ func SubmitJob(c *gin.Context) {
 	if !requireTaskActionPermission(c, task.TaskType, "submit") {
 		return
 	}
-	if task.TaskType == "stream" && task.JobStatus == "RUNNING" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "实时数据集成运行中，不允许重复提交作业，请先停止当前作业"})
-		return
-	}
-
 	// 只有流式任务支持 SavePoint
-	var isStartWithSavePoint bool
-	if task.TaskType == "stream" {
-		isStartWithSavePoint = c.Query("isStartWithSavePoint") == "true"
-		// SavePoint 启动时必须有 job_id
-		if isStartWithSavePoint && (task.JobID == nil || *task.JobID == "") {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "使用 SavePoint 启动时必须有已存在的 job_id，请先正常启动任务"})
+	isStartWithSavePoint := task.TaskType == "stream" && c.Query("isStartWithSavePoint") == "true"
+
+	// 默认同步提交，等待作业进入明确状态后返回
+	if c.Query("async") != "true" {
+		jobStatus, _, err := seatunnel.StartTask(task, isStartWithSavePoint)
+		if err != nil {
+			if errors.Is(err, seatunnel.ErrTaskRunning) || errors.Is(err, seatunnel.ErrSavePointJobIDMissing) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "提交作业失败"})
 			return
 		}
+		c.JSON(http.StatusOK, gin.H{
+			"message":    "作业提交成功",
+			"job_status": jobStatus,
+		})
+		return
 	}
 
-	respBody, err := seatunnel.SubmitJobInternal(taskID, isStartWithSavePoint)
+	// async=true 时异步提交，返回操作 ID，客户端轮询操作或订阅 SSE 获取进度
+	operator := ""
+	if user := middleware.GetCurrentUser(c); user != nil {
+		operator = user.Username
+	}
+	op, err := seatunnel.SubmitTaskAsync(task, isStartWithSavePoint, operator)
 	if err != nil {
-		log.Printf("[ETL] 提交作业失败: taskID=%d, type=%s, error=%v", taskID, task.TaskType, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "提交作业失败"})
+		writeTaskOperationError(c, err, "提交作业失败")
 		return
 	}
-
-	// 更新最后运行时间
-	postgres.DB.Model(&task).Update("last_run_time", time.Now())
-
-	// 从响应中提取 jobId 并更新到数据库
-	seatunnel.UpdateJobIdFromResponse(taskID, respBody)
-
-	// 提交成功后等待作业进入明确状态，前端只需刷新一次列表
-	jobStatus := waitForTaskStatus(taskID, 15, time.Second, func(status string) bool {
-		return status == "RUNNING" || status == "FAILED" || status == "FINISHED" || status == "CANCEL"
-	})
-
-	log.Printf("[ETL] 提交作业成功: taskID=%d, type=%s, isStartWithSavePoint=%v, result=%s", taskID, task.TaskType, isStartWithSavePoint, string(respBody))
-
-	c.JSON(http.StatusOK, gin.H{
-		"message":    "作业提交成功",
-		"job_status": jobStatus,
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":      "作业提交中",
+		"operation_id": op.ID,
+		"operation":    op,
 	})
 }
 
@@ -137,33 +111,39 @@ func StopJob(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "isStopWithSavePoint must be true or false"})
 		return
 	}
-	body := fmt.Sprintf(`{"jobId": "%s", "isStopWithSavePoint": %s}`, *task.JobID, isStopWithSavePoint)
-	url := config.SeatunnelBaseURL + "/stop-job"
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Post(url, "application/json", strings.NewReader(body))
-	if err != nil {
-		log.Printf("[ETL] 停止作业失败: taskID=%d, jobId=%s, error=%v", task.ID, *task.JobID, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "无法连接到 Seatunnel 服务，请检查服务是否已启动且网络正常"})
+	// 默认同步停止，等待状态退出 RUNNING 后返回
+	if c.Query("async") != "true" {
+		jobStatus, respBody, statusCode, err := seatunnel.StopTask(task, isStopWithSavePoint == "true")
+		if err != nil {
+			if statusCode == 0 {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "无法连接到 Seatunnel 服务，请检查服务是否已启动且网络正常"})
+				return
+			}
+			c.Data(statusCode, "application/json; charset=utf-8", respBody)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"message":    "作业停止成功",
+			"job_status": jobStatus,
+			"result":     string(respBody),
+		})
 		return
 	}
-	defer resp.Body.Close()
-	respBody, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("[ETL] 停止作业失败: taskID=%d, jobId=%s, statusCode=%d, response=%s", task.ID, *task.JobID, resp.StatusCode, string(respBody))
-		c.Data(resp.StatusCode, resp.Header.Get("Content-Type"), respBody)
+
+	// async=true 时异步停止，返回操作 ID
+	operator := ""
+	if user := middleware.GetCurrentUser(c); user != nil {
+		operator = user.Username
+	}
+	op, err := seatunnel.StopTaskAsync(task, isStopWithSavePoint == "true", operator)
+	if err != nil {
+		writeTaskOperationError(c, err, "停止作业失败")
 		return
 	}
-
-	// 停止成功后等待状态退出 RUNNING，前端只需刷新一次列表
-	jobStatus := waitForTaskStatus(task.ID, 15, time.Second, func(status string) bool {
-		return status != "" && status != "RUNNING" && status != "UNKNOWN"
-	})
-
-	log.Printf("[ETL] 停止作业成功: taskID=%d, jobId=%s, jobStatus=%s", task.ID, *task.JobID, jobStatus)
-	c.JSON(http.StatusOK, gin.H{
-		"message":    "作业停止成功",
-		"job_status": jobStatus,
-		"result":     string(respBody),
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":      "作业停止中",
+		"operation_id": op.ID,
+		"operation":    op,
 	})
 }
 
@@ -0,0 +1,176 @@
+package seatunnel
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"octoops/internal/middleware"
+	seatunnelService "octoops/internal/service/seatunnel"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// canCreateTaskType 校验当前用户是否有对应任务类型的创建权限
+func canCreateTaskType(c *gin.Context, taskType string) bool {
+	if middleware.HasPermission(middleware.GetCurrentUser(c), "etl:"+taskType+":create") {
+		return true
+	}
+	c.JSON(http.StatusForbidden, gin.H{"code": 403, "message": "权限不足"})
+	return false
+}
+
+func writeTemplateError(c *gin.Context, action string, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	case errors.Is(err, seatunnelService.ErrTemplateInvalid), errors.Is(err, seatunnelService.ErrUnknownCluster):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, seatunnelService.ErrTemplateInUse):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": action + "失败: " + err.Error()})
+	}
+}
+
+// ListTemplates 任务模板列表
+func ListTemplates(c *gin.Context) {
+	list, err := seatunnelService.ListTemplates(c.Query("task_type"), c.Query("name"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询模板失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// GetTemplate 任务模板详情
+func GetTemplate(c *gin.Context) {
+	tpl, err := seatunnelService.GetTemplateByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return
+	}
+	c.JSON(http.StatusOK, seatunnelService.ToTemplateView(tpl))
+}
+
+// CreateTemplate 新增任务模板
+func CreateTemplate(c *gin.Context) {
+	var req seatunnelService.TemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	tpl, err := seatunnelService.CreateTemplate(req)
+	if err != nil {
+		writeTemplateError(c, "创建模板", err)
+		return
+	}
+	c.JSON(http.StatusOK, seatunnelService.ToTemplateView(tpl))
+}
+
+// UpdateTemplate 更新任务模板，已派生的任务需通过下发接口同步
+func UpdateTemplate(c *gin.Context) {
+	var req seatunnelService.TemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	tpl, err := seatunnelService.UpdateTemplate(c.Param("id"), req)
+	if err != nil {
+		writeTemplateError(c, "更新模板", err)
+		return
+	}
+	c.JSON(http.StatusOK, seatunnelService.ToTemplateView(tpl))
+}
+
+// DeleteTemplate 删除任务模板，存在派生任务时不允许删除
+func DeleteTemplate(c *gin.Context) {
+	if err := seatunnelService.DeleteTemplate(c.Param("id")); err != nil {
+		writeTemplateError(c, "删除模板", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// CreateTaskFromTemplate 根据模板创建任务
+func CreateTaskFromTemplate(c *gin.Context) {
+	tpl, err := seatunnelService.GetTemplateByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return
+	}
+	if !canCreateTaskType(c, tpl.TaskType) {
+		return
+	}
+	var req seatunnelService.TemplateTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	task, err := seatunnelService.NewTaskFromTemplate(tpl, req)
+	if err != nil {
+		writeTemplateError(c, "创建任务", err)
+		return
+	}
+	if saveNewTask(c, &task) {
+		c.JSON(http.StatusOK, task)
+	}
+}
+
+// PropagateTemplate 将模板变更下发到派生任务，dry_run=true 时仅返回差异预览
+// 仅下发配置及配置格式，cron、告警组与集群属于任务级设置，需在任务中单独修改
+func PropagateTemplate(c *gin.Context) {
+	var req struct {
+		TaskIDs []uint `json:"task_ids"`
+		DryRun  bool   `json:"dry_run"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	dryRun := req.DryRun || c.Query("dry_run") == "true"
+	results, err := seatunnelService.PropagateTemplate(c.Param("id"), req.TaskIDs, dryRun)
+	if err != nil {
+		writeTemplateError(c, "下发模板", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"dry_run": dryRun, "data": results, "total": len(results),
+		"message": "仅下发配置，cron、告警组与集群不随模板下发"})
+}
+
+// CloneTask 复制已有任务，新任务默认禁用
+func CloneTask(c *gin.Context) {
+	src, err := seatunnelService.GetTaskByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	}
+	if !canCreateTaskType(c, src.TaskType) {
+		return
+	}
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	task, err := seatunnelService.CloneTask(src.ID, req.Name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "复制任务失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, task)
+}
+
+func RegisterTemplateRoutes(r *gin.RouterGroup) {
+	r.GET("/seatunnel/template", middleware.AuthMiddleware(), middleware.RequirePermission("etl:template:read"), ListTemplates)
+	r.GET("/seatunnel/template/:id", middleware.AuthMiddleware(), middleware.RequirePermission("etl:template:read"), GetTemplate)
+	r.POST("/seatunnel/template", middleware.AuthMiddleware(), middleware.RequirePermission("etl:template:create"), CreateTemplate)
+	r.PUT("/seatunnel/template/:id", middleware.AuthMiddleware(), middleware.RequirePermission("etl:template:update"), UpdateTemplate)
+	r.DELETE("/seatunnel/template/:id", middleware.AuthMiddleware(), middleware.RequirePermission("etl:template:delete"), DeleteTemplate)
+	r.POST("/seatunnel/template/:id/tasks", middleware.AuthMiddleware(), middleware.RequirePermission("etl:template:read"), CreateTaskFromTemplate)
+	r.POST("/seatunnel/template/:id/propagate", middleware.AuthMiddleware(), middleware.RequirePermission("etl:template:propagate"), PropagateTemplate)
+
+	r.POST("/seatunnel/tasks/:id/clone", middleware.AuthMiddleware(), middleware.RequireAnyPermission("etl:stream:create", "etl:batch:create"), CloneTask)
+}
@@ -6,8 +6,11 @@ import (
 	"net/http"
 	"octoops/internal/infra/postgres"
 	"octoops/internal/middleware"
+	alertModel "octoops/internal/model/alert"
 	taskModel "octoops/internal/model/task"
 	"octoops/internal/scheduler"
+	alertService "octoops/internal/service/alert"
+	triggerService "octoops/internal/service/trigger"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -41,6 +44,10 @@ func CreateCustomTask(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if err := scheduler.ValidateRetryConfig(task.MaxRetries, task.RetryInterval); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	postgres.DB.Create(&task)
 	scheduler.RegisterCustomTask(
 		task.ID,
@@ -65,6 +72,10 @@ func UpdateCustomTask(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if err := validateRetryUpdate(task, req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	postgres.DB.Model(&task).Updates(req)
 	var uid uint
 	if _, err := fmt.Sscanf(id, "%d", &uid); err != nil {
@@ -88,6 +99,23 @@ func UpdateCustomTask(c *gin.Context) {
 	c.JSON(http.StatusOK, task)
 }
 
+// validateRetryUpdate 校验更新后的重试配置，未提交的字段沿用任务当前值
+func validateRetryUpdate(task taskModel.CustomTask, req map[string]interface{}) error {
+	maxRetries, retryInterval := task.MaxRetries, task.RetryInterval
+	for key, target := range map[string]*int{"max_retries": &maxRetries, "retry_interval": &retryInterval} {
+		v, ok := req[key]
+		if !ok {
+			continue
+		}
+		n, isNumber := v.(float64)
+		if !isNumber || n != float64(int(n)) {
+			return fmt.Errorf("%s 必须为整数", key)
+		}
+		*target = int(n)
+	}
+	return scheduler.ValidateRetryConfig(maxRetries, retryInterval)
+}
+
 func DeleteCustomTask(c *gin.Context) {
 	id := c.Param("id")
 	var uid uint
@@ -98,6 +126,12 @@ func DeleteCustomTask(c *gin.Context) {
 	}
 	postgres.DB.Delete(&taskModel.CustomTask{}, id)
 	scheduler.DisableCustomTask(uid)
+	if err := alertService.DeleteTaskGroups(alertModel.TaskSourceCustom, uid); err != nil {
+		log.Printf("删除自定义任务告警组关联失败: id=%d, err=%v", uid, err)
+	}
+	if err := triggerService.DeleteTargetTriggers(triggerService.TargetCustom, uid); err != nil {
+		log.Printf("删除自定义任务触发器失败: id=%d, err=%v", uid, err)
+	}
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
 }
 
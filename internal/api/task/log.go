@@ -23,6 +23,9 @@ func ListTaskLogs(c *gin.Context) {
 	if status := c.Query("status"); status != "" {
 		query = query.Where("status = ?", status)
 	}
+	if triggerSource := c.Query("trigger_source"); triggerSource != "" {
+		query = query.Where("trigger_source = ?", triggerSource)
+	}
 	if startTime := c.Query("start_time"); startTime != "" {
 		query = query.Where("created_at >= ?", startTime)
 	}
@@ -0,0 +1,143 @@
+package task
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"octoops/internal/middleware"
+	triggerService "octoops/internal/service/trigger"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// 触发请求体上限
+const maxHookBodyBytes = 1 << 20
+
+func writeTriggerError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	case errors.Is(err, triggerService.ErrInvalidTrigger), errors.Is(err, triggerService.ErrUnsupportedTask):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message + ": " + err.Error()})
+	}
+}
+
+// ListTriggers 触发器列表，可按 target_type、target_id 过滤
+func ListTriggers(c *gin.Context) {
+	targetID, _ := strconv.ParseUint(c.Query("target_id"), 10, 64)
+	triggers, err := triggerService.ListTriggers(c.Query("target_type"), uint(targetID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "查询触发器失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": triggers, "total": len(triggers)})
+}
+
+// GetTrigger 触发器详情
+func GetTrigger(c *gin.Context) {
+	t, err := triggerService.GetTrigger(c.Param("id"))
+	if err != nil {
+		writeTriggerError(c, err, "查询触发器失败")
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// CreateTrigger 创建触发器，响应中的 token 仅返回一次
+func CreateTrigger(c *gin.Context) {
+	var req triggerService.TriggerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	operator := ""
+	if user := middleware.GetCurrentUser(c); user != nil {
+		operator = user.Username
+	}
+	t, err := triggerService.CreateTrigger(req, operator)
+	if err != nil {
+		writeTriggerError(c, err, "创建触发器失败")
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// UpdateTrigger 更新触发器
+func UpdateTrigger(c *gin.Context) {
+	var req triggerService.TriggerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	t, err := triggerService.UpdateTrigger(c.Param("id"), req)
+	if err != nil {
+		writeTriggerError(c, err, "更新触发器失败")
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// RotateTriggerToken 重置 token
+func RotateTriggerToken(c *gin.Context) {
+	t, err := triggerService.RotateToken(c.Param("id"))
+	if err != nil {
+		writeTriggerError(c, err, "重置token失败")
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// DeleteTrigger 删除触发器
+func DeleteTrigger(c *gin.Context) {
+	if err := triggerService.DeleteTrigger(c.Param("id")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "删除失败: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// FireTrigger 上游系统调用的触发入口，凭 token 鉴权，无需登录
+func FireTrigger(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxHookBodyBytes))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "读取请求体失败"})
+		return
+	}
+	result, err := triggerService.Fire(c.Param("token"), body, c.GetHeader("X-OctoOps-Timestamp"), c.GetHeader("X-OctoOps-Signature"))
+	if err != nil {
+		switch {
+		case errors.Is(err, triggerService.ErrTriggerNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, triggerService.ErrRateLimited):
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		case errors.Is(err, triggerService.ErrInvalidSignature):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case errors.Is(err, triggerService.ErrInvalidTrigger), errors.Is(err, triggerService.ErrUnsupportedTask):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, triggerService.ErrTaskDisabled):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "触发任务失败: " + err.Error()})
+		}
+		return
+	}
+	if result.Async {
+		c.JSON(http.StatusAccepted, result)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func RegisterTaskTriggerRoutes(r *gin.RouterGroup) {
+	r.GET("/task/trigger", middleware.AuthMiddleware(), middleware.RequirePermission("task:trigger:read"), ListTriggers)
+	r.GET("/task/trigger/:id", middleware.AuthMiddleware(), middleware.RequirePermission("task:trigger:read"), GetTrigger)
+	r.POST("/task/trigger", middleware.AuthMiddleware(), middleware.RequirePermission("task:trigger:create"), CreateTrigger)
+	r.PUT("/task/trigger/:id", middleware.AuthMiddleware(), middleware.RequirePermission("task:trigger:update"), UpdateTrigger)
+	r.POST("/task/trigger/:id/rotate", middleware.AuthMiddleware(), middleware.RequirePermission("task:trigger:update"), RotateTriggerToken)
+	r.DELETE("/task/trigger/:id", middleware.AuthMiddleware(), middleware.RequirePermission("task:trigger:delete"), DeleteTrigger)
+	r.POST("/hooks/:token", FireTrigger)
+}
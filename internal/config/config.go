@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -38,7 +39,8 @@ type MailConfig struct {
 }
 
 type SeatunnelConfig struct {
-	BaseURL string `yaml:"base_url"`
+	BaseURL  string            `yaml:"base_url"`
+	Clusters map[string]string `yaml:"clusters"` // 集群名称 -> API 地址，任务未指定集群时使用 base_url
 }
 
 type AliyunConfig struct {
@@ -50,7 +52,8 @@ type AuthConfig struct {
 }
 
 type ServerConfig struct {
-	Port int `yaml:"port"`
+	Port        int    `yaml:"port"`
+	ExternalURL string `yaml:"external_url"` // 前端访问地址，用于告警消息中的链接
 }
 
 type RedisConfig struct {
@@ -60,12 +63,18 @@ type RedisConfig struct {
 	Prefix   string `yaml:"prefix"`
 }
 
+// GitOpsConfig 任务定义同步目录，目录内容由外部工具（如 git pull）保持更新
+type GitOpsConfig struct {
+	Dir string `yaml:"dir"`
+}
+
 type OctoopsConfig struct {
 	Mail   MailConfig   `yaml:"mail"`
 	Aliyun AliyunConfig `yaml:"aliyun"`
 	Auth   AuthConfig   `yaml:"auth"`
 	Server ServerConfig `yaml:"server"`
 	Redis  RedisConfig  `yaml:"redis"`
+	GitOps GitOpsConfig `yaml:"gitops"`
 	// 预留字段，后续可扩展
 }
 
@@ -76,13 +85,16 @@ type Config struct {
 }
 
 var (
-	SeatunnelBaseURL string
-	PostgresDSN      string
-	mailConfig       MailConfig
-	aliyunAesKey     string
-	jwtSecret        string
-	serverPort       int
-	redisConfig      RedisConfig
+	SeatunnelBaseURL  string
+	seatunnelClusters map[string]string
+	PostgresDSN       string
+	mailConfig        MailConfig
+	aliyunAesKey      string
+	jwtSecret         string
+	serverPort        int
+	externalURL       string
+	redisConfig       RedisConfig
+	gitopsConfig      GitOpsConfig
 )
 
 func overrideStringField(envVar string, field *string) {
@@ -149,11 +161,14 @@ func InitConfig() error {
 	overrideStringField("OCTOOPS_AUTH_JWT_SECRET", &cfg.Octoops.Auth.JWTSecret)
 	// Octoops.Server
 	overrideIntField("OCTOOPS_SERVER_PORT", &cfg.Octoops.Server.Port)
+	overrideStringField("OCTOOPS_SERVER_EXTERNAL_URL", &cfg.Octoops.Server.ExternalURL)
 	// Octoops.Redis
 	overrideStringField("OCTOOPS_REDIS_ADDR", &cfg.Octoops.Redis.Addr)
 	overrideStringField("OCTOOPS_REDIS_PASSWORD", &cfg.Octoops.Redis.Password)
 	overrideIntField("OCTOOPS_REDIS_DB", &cfg.Octoops.Redis.DB)
 	overrideStringField("OCTOOPS_REDIS_PREFIX", &cfg.Octoops.Redis.Prefix)
+	// Octoops.GitOps
+	overrideStringField("OCTOOPS_GITOPS_DIR", &cfg.Octoops.GitOps.Dir)
 
 	// 校验必填项
 	if cfg.Seatunnel.BaseURL == "" {
@@ -164,12 +179,15 @@ func InitConfig() error {
 	}
 
 	SeatunnelBaseURL = cfg.Seatunnel.BaseURL
+	seatunnelClusters = cfg.Seatunnel.Clusters
 	PostgresDSN = cfg.Postgres.DSN()
 	mailConfig = cfg.Octoops.Mail
 	aliyunAesKey = cfg.Octoops.Aliyun.AesKey
 	jwtSecret = cfg.Octoops.Auth.JWTSecret
 	serverPort = cfg.Octoops.Server.Port
+	externalURL = strings.TrimRight(cfg.Octoops.Server.ExternalURL, "/")
 	redisConfig = cfg.Octoops.Redis
+	gitopsConfig = cfg.Octoops.GitOps
 	if redisConfig.Prefix == "" {
 		redisConfig.Prefix = "octoops:"
 	}
@@ -201,6 +219,24 @@ func GetServerPort() int {
 	return serverPort
 }
 
+// GetExternalURL 前端访问地址，未配置时为空
+func GetExternalURL() string {
+	return externalURL
+}
+
 func GetRedisConfig() RedisConfig {
 	return redisConfig
 }
+
+func GetGitOpsConfig() GitOpsConfig {
+	return gitopsConfig
+}
+
+// SeatunnelClusterURL 返回集群对应的 API 地址，cluster 为空时返回默认地址
+func SeatunnelClusterURL(cluster string) (string, bool) {
+	if cluster == "" {
+		return SeatunnelBaseURL, true
+	}
+	url, ok := seatunnelClusters[cluster]
+	return url, ok
+}
@@ -0,0 +1,141 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+)
+
+// Event 领域事件，EventName 用于订阅和持久化时标识事件类型
+type Event interface {
+	EventName() string
+}
+
+// Handler 事件处理函数；持久化事件处理返回错误时会按退避策略重试
+type Handler func(Event) error
+
+// Counter 单类事件的发布与处理计数
+type Counter struct {
+	Published int64 `json:"published"`
+	Handled   int64 `json:"handled"`
+	Failed    int64 `json:"failed"`
+}
+
+type subscription struct {
+	key     string
+	handler Handler
+}
+
+var (
+	mu       sync.RWMutex
+	handlers = map[string][]subscription{}
+	decoders = map[string]func([]byte) (Event, error){}
+
+	statsMu sync.Mutex
+	stats   = map[string]*Counter{}
+)
+
+// register 登记事件类型，用于从发件箱反序列化
+func register[T Event]() {
+	var zero T
+	decoders[zero.EventName()] = func(data []byte) (Event, error) {
+		var ev T
+		if err := json.Unmarshal(data, &ev); err != nil {
+			return nil, err
+		}
+		return ev, nil
+	}
+}
+
+// Subscribe 订阅指定名称的事件，name 为 "*" 时订阅全部事件；
+// key 标识订阅者，持久化事件重试时只重新调用失败的订阅者
+func Subscribe(name, key string, handler Handler) {
+	mu.Lock()
+	defer mu.Unlock()
+	handlers[name] = append(handlers[name], subscription{key: key, handler: handler})
+}
+
+// On 按事件类型订阅，处理函数直接接收具体事件
+func On[T Event](key string, handler func(T) error) {
+	var zero T
+	Subscribe(zero.EventName(), key, func(ev Event) error {
+		typed, ok := ev.(T)
+		if !ok {
+			return nil
+		}
+		return handler(typed)
+	})
+}
+
+// Publish 在当前进程内同步分发事件，处理函数的错误仅记录日志
+func Publish(ev Event) {
+	count(ev.EventName(), func(c *Counter) { c.Published++ })
+	dispatch(ev, nil)
+}
+
+// Names 返回已登记的事件名称
+func Names() []string {
+	names := make([]string, 0, len(decoders))
+	for name := range decoders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Stats 返回各类事件的发布与处理计数
+func Stats() map[string]Counter {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	result := make(map[string]Counter, len(stats))
+	for name, c := range stats {
+		result[name] = *c
+	}
+	return result
+}
+
+// dispatch 依次调用订阅者，only 非空时只调用其中的订阅者；
+// 单个订阅者失败或 panic 不影响其他订阅者，返回失败的订阅者及错误
+func dispatch(ev Event, only map[string]bool) map[string]error {
+	name := ev.EventName()
+	mu.RLock()
+	subs := append(append([]subscription{}, handlers[name]...), handlers["*"]...)
+	mu.RUnlock()
+
+	failed := map[string]error{}
+	for _, sub := range subs {
+		if only != nil && !only[sub.key] {
+			continue
+		}
+		if err := safeHandle(sub.handler, ev); err != nil {
+			count(name, func(c *Counter) { c.Failed++ })
+			log.Printf("[Event] 订阅者处理失败: name=%s, subscriber=%s, error=%v", name, sub.key, err)
+			failed[sub.key] = err
+			continue
+		}
+		count(name, func(c *Counter) { c.Handled++ })
+	}
+	return failed
+}
+
+func safeHandle(handler Handler, ev Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handler(ev)
+}
+
+func count(name string, fn func(*Counter)) {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	c, ok := stats[name]
+	if !ok {
+		c = &Counter{}
+		stats[name] = c
+	}
+	fn(c)
+}
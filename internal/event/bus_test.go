@@ -0,0 +1,69 @@
+package event
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type testEvent struct {
+	Value string `json:"value"`
+}
+
+func (testEvent) EventName() string { return "test.event" }
+
+func TestDispatchOnlyRetriesFailedSubscribers(t *testing.T) {
+	calls := map[string]int{}
+	On("test.ok", func(ev testEvent) error {
+		calls["ok"]++
+		return nil
+	})
+	On("test.fail", func(ev testEvent) error {
+		calls["fail"]++
+		return errors.New("boom")
+	})
+	On("test.panic", func(ev testEvent) error {
+		calls["panic"]++
+		panic("unexpected")
+	})
+
+	failed := dispatch(testEvent{Value: "x"}, nil)
+	if len(failed) != 2 || failed["test.fail"] == nil || failed["test.panic"] == nil {
+		t.Fatalf("failed subscribers = %v", failed)
+	}
+
+	// 重试时只调用上次失败的订阅者
+	dispatch(testEvent{Value: "x"}, map[string]bool{"test.fail": true})
+	if calls["ok"] != 1 || calls["fail"] != 2 || calls["panic"] != 1 {
+		t.Fatalf("calls = %v", calls)
+	}
+}
+
+func TestDecodeRegisteredEvent(t *testing.T) {
+	register[testEvent]()
+	ev, err := decoders["test.event"]([]byte(`{"value":"hello"}`))
+	if err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if got, ok := ev.(testEvent); !ok || got.Value != "hello" {
+		t.Fatalf("decoded = %#v", ev)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 5 * time.Second},
+		{2, 10 * time.Second},
+		{5, 80 * time.Second},
+		{20, 30 * time.Minute},
+		{100, 30 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package event
+
+import "time"
+
+// 事件名称
+const (
+	NameJobStatusChanged = "job.status_changed"
+	NameTaskRunStarted   = "task.run_started"
+	NameTaskRunFinished  = "task.run_finished"
+	NameSGRuleChanged    = "aliyun.sg_rule_changed"
+	NameUserLoggedIn     = "user.logged_in"
+)
+
+// 任务来源
+const (
+	SourceEtl    = "etl"
+	SourceCustom = "custom"
+)
+
+// JobStatusChanged ETL 作业状态发生变化
+type JobStatusChanged struct {
+	TaskID    uint      `json:"task_id"`
+	TaskName  string    `json:"task_name"`
+	TaskType  string    `json:"task_type"`
+	OldStatus string    `json:"old_status"`
+	NewStatus string    `json:"new_status"`
+	At        time.Time `json:"at"`
+}
+
+func (JobStatusChanged) EventName() string { return NameJobStatusChanged }
+
+// 任务触发来源
+const (
+	TriggerSchedule = "schedule"
+	TriggerWebhook  = "webhook"
+)
+
+// TaskRunStarted 任务开始执行（定时调度或外部触发）
+type TaskRunStarted struct {
+	Source   string    `json:"source"`  // etl/custom
+	Trigger  string    `json:"trigger"` // schedule/webhook
+	TaskID   uint      `json:"task_id"`
+	TaskName string    `json:"task_name"`
+	TaskType string    `json:"task_type"`
+	At       time.Time `json:"at"`
+}
+
+func (TaskRunStarted) EventName() string { return NameTaskRunStarted }
+
+// TaskRunFinished 任务执行结束
+type TaskRunFinished struct {
+	Source   string                 `json:"source"`  // etl/custom
+	Trigger  string                 `json:"trigger"` // schedule/webhook
+	TaskID   uint                   `json:"task_id"`
+	TaskName string                 `json:"task_name"`
+	TaskType string                 `json:"task_type"`
+	Status   string                 `json:"status"` // success/failed
+	Result   string                 `json:"result"`
+	Error    string                 `json:"error,omitempty"`    // 失败原因
+	Details  map[string]interface{} `json:"details,omitempty"`  // 自定义任务的结构化执行详情
+	Attempts int                    `json:"attempts,omitempty"` // 自定义任务的执行次数，含重试
+	At       time.Time              `json:"at"`
+}
+
+func (TaskRunFinished) EventName() string { return NameTaskRunFinished }
+
+// SGRuleChanged 阿里云安全组授权 IP 变更
+type SGRuleChanged struct {
+	ConfigID        uint      `json:"config_id"`
+	ConfigName      string    `json:"config_name"`
+	RegionID        string    `json:"region_id"`
+	SecurityGroupID string    `json:"security_group_id"`
+	OldIP           string    `json:"old_ip"`
+	NewIP           string    `json:"new_ip"`
+	Ports           []int     `json:"ports"`   // 为新 IP 授权的端口
+	Revoked         []string  `json:"revoked"` // 撤销的旧 IP 端口范围
+	At              time.Time `json:"at"`
+}
+
+func (SGRuleChanged) EventName() string { return NameSGRuleChanged }
+
+// UserLoggedIn 用户登录成功
+type UserLoggedIn struct {
+	UserID   uint      `json:"user_id"`
+	Username string    `json:"username"`
+	IP       string    `json:"ip"`
+	At       time.Time `json:"at"`
+}
+
+func (UserLoggedIn) EventName() string { return NameUserLoggedIn }
+
+func init() {
+	register[JobStatusChanged]()
+	register[TaskRunStarted]()
+	register[TaskRunFinished]()
+	register[SGRuleChanged]()
+	register[UserLoggedIn]()
+}
@@ -0,0 +1,208 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"octoops/internal/infra/postgres"
+	eventModel "octoops/internal/model/event"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	outboxPollInterval = 2 * time.Second
+	outboxBatchSize    = 50
+	outboxMaxAttempts  = 10
+	// 单条事件认领后的租约时间，订阅者处理超过该时间时事件可能被其他实例重复投递
+	outboxLease = 5 * time.Minute
+	// 已投递事件的保留时间
+	outboxRetention = 7 * 24 * time.Hour
+)
+
+var (
+	wake      = make(chan struct{}, 1)
+	startOnce sync.Once
+)
+
+// PublishDurable 将事件写入发件箱，由分发器异步投递；订阅者失败时按退避重试，
+// 同一事件可能被投递多次，订阅者需自行保证幂等
+func PublishDurable(ev Event) {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		log.Printf("[Event] 序列化事件失败: name=%s, error=%v", ev.EventName(), err)
+		return
+	}
+	row := eventModel.EventOutbox{
+		Name:        ev.EventName(),
+		Payload:     string(payload),
+		Status:      "pending",
+		AvailableAt: time.Now(),
+	}
+	if err := postgres.DB.Create(&row).Error; err != nil {
+		// 落库失败时退化为进程内分发，避免丢失副作用
+		log.Printf("[Event] 写入发件箱失败，改为同步分发: name=%s, error=%v", ev.EventName(), err)
+		Publish(ev)
+		return
+	}
+	count(ev.EventName(), func(c *Counter) { c.Published++ })
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+}
+
+// StartDispatcher 启动发件箱分发器，多副本部署时通过 SKIP LOCKED 保证同一事件只被一个实例处理
+func StartDispatcher() {
+	startOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(outboxPollInterval)
+			defer ticker.Stop()
+			lastCleanup := time.Time{}
+			for {
+				for dispatchOutboxBatch() {
+				}
+				if time.Since(lastCleanup) > time.Hour {
+					cleanupOutbox()
+					lastCleanup = time.Now()
+				}
+				select {
+				case <-ticker.C:
+				case <-wake:
+				}
+			}
+		}()
+	})
+}
+
+// dispatchOutboxBatch 逐条认领并投递到期事件，返回是否处理满一批（可能还有剩余）
+// 订阅者在事务外执行，每条事件的结果单独提交，失败或回滚不会影响同批其他事件
+func dispatchOutboxBatch() bool {
+	for i := 0; i < outboxBatchSize; i++ {
+		row, err := claimOutboxRow()
+		if err != nil {
+			log.Printf("[Event] 认领发件箱事件失败: %v", err)
+			return false
+		}
+		if row == nil {
+			return false
+		}
+		updates := deliverOutboxRow(*row)
+		updates["lease_until"] = nil
+		// 租约过期后被其他实例重新认领时 attempts 已变化，放弃回写本次结果
+		result := postgres.DB.Model(&eventModel.EventOutbox{}).
+			Where("id = ? AND attempts = ?", row.ID, row.Attempts).Updates(updates)
+		if result.Error != nil {
+			log.Printf("[Event] 回写发件箱事件失败: id=%d, error=%v", row.ID, result.Error)
+		} else if result.RowsAffected == 0 {
+			log.Printf("[Event] 事件租约已过期并被重新认领，忽略本次结果: id=%d, name=%s", row.ID, row.Name)
+		}
+	}
+	return true
+}
+
+// claimOutboxRow 在短事务中认领一条到期事件：递增 attempts 并设置租约，没有可投递事件时返回 nil
+func claimOutboxRow() (*eventModel.EventOutbox, error) {
+	var row eventModel.EventOutbox
+	found := false
+	err := postgres.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND available_at <= ? AND (lease_until IS NULL OR lease_until <= ?)", "pending", now, now).
+			Order("id").Limit(1).Find(&row)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		found = true
+		row.Attempts++
+		leaseUntil := now.Add(outboxLease)
+		row.LeaseUntil = &leaseUntil
+		return tx.Model(&eventModel.EventOutbox{}).Where("id = ?", row.ID).Updates(map[string]interface{}{
+			"attempts":    row.Attempts,
+			"lease_until": leaseUntil,
+		}).Error
+	})
+	if err != nil || !found {
+		return nil, err
+	}
+	return &row, nil
+}
+
+// deliverOutboxRow 投递单条已认领的事件并返回需要回写的字段，attempts 已在认领时递增
+func deliverOutboxRow(row eventModel.EventOutbox) map[string]interface{} {
+	attempts := row.Attempts
+	decode, ok := decoders[row.Name]
+	if !ok {
+		return map[string]interface{}{"status": "failed", "attempts": attempts, "last_error": "未知事件类型"}
+	}
+	ev, err := decode([]byte(row.Payload))
+	if err != nil {
+		return map[string]interface{}{"status": "failed", "attempts": attempts, "last_error": "解析事件失败: " + err.Error()}
+	}
+
+	var only map[string]bool
+	if row.Pending != "" {
+		only = map[string]bool{}
+		for _, key := range strings.Split(row.Pending, ",") {
+			only[key] = true
+		}
+	}
+	failed := dispatch(ev, only)
+	if len(failed) == 0 {
+		return map[string]interface{}{"status": "done", "attempts": attempts, "pending": "", "last_error": "", "processed_at": time.Now()}
+	}
+
+	keys := make([]string, 0, len(failed))
+	messages := make([]string, 0, len(failed))
+	for key, err := range failed {
+		keys = append(keys, key)
+		messages = append(messages, fmt.Sprintf("%s: %v", key, err))
+	}
+	sort.Strings(keys)
+	sort.Strings(messages)
+	lastError := strings.Join(messages, "; ")
+	if runes := []rune(lastError); len(runes) > 500 {
+		lastError = string(runes[:500])
+	}
+	updates := map[string]interface{}{
+		"attempts":   attempts,
+		"pending":    strings.Join(keys, ","),
+		"last_error": lastError,
+	}
+	if attempts >= outboxMaxAttempts {
+		updates["status"] = "failed"
+		updates["processed_at"] = time.Now()
+		log.Printf("[Event] 事件重试次数耗尽: id=%d, name=%s, error=%s", row.ID, row.Name, lastError)
+	} else {
+		updates["available_at"] = time.Now().Add(backoff(attempts))
+	}
+	return updates
+}
+
+// backoff 指数退避，从 5 秒起翻倍，最长 30 分钟
+func backoff(attempts int) time.Duration {
+	if attempts > 10 {
+		return 30 * time.Minute
+	}
+	d := 5 * time.Second << (attempts - 1)
+	if d > 30*time.Minute {
+		return 30 * time.Minute
+	}
+	return d
+}
+
+func cleanupOutbox() {
+	result := postgres.DB.Where("status = ? AND processed_at < ?", "done", time.Now().Add(-outboxRetention)).Delete(&eventModel.EventOutbox{})
+	if result.Error != nil {
+		log.Printf("[Event] 清理发件箱失败: %v", result.Error)
+		return
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("[Event] 清理已投递事件 %d 条", result.RowsAffected)
+	}
+}
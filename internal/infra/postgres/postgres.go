@@ -4,6 +4,7 @@ import (
 	"fmt"
 	alertModel "octoops/internal/model/alert"
 	aliyunModel "octoops/internal/model/aliyun"
+	eventModel "octoops/internal/model/event"
 	rbacModel "octoops/internal/model/rbac"
 	seatunnelModel "octoops/internal/model/seatunnel"
 	taskModel "octoops/internal/model/task"
@@ -39,12 +40,38 @@ func Migrate() error {
 	}
 	if err := DB.AutoMigrate(
 		&seatunnelModel.EtlTask{},
+		&seatunnelModel.BackfillJob{},
+		&seatunnelModel.BackfillSlice{},
+		&seatunnelModel.Datasource{},
+		&seatunnelModel.GitOpsReport{},
+		&seatunnelModel.EtlTaskTemplate{},
+		&seatunnelModel.BulkOperation{},
+		&seatunnelModel.BulkOperationItem{},
+		&seatunnelModel.TaskOperation{},
+		&eventModel.EventOutbox{},
 		&aliyunModel.SGConfig{},
 		&alertModel.AlertChannel{},
 		&alertModel.AlertGroup{},
 		&alertModel.AlertGroupMember{},
 		&alertModel.AlertTemplate{},
+		&alertModel.WebhookSubscription{},
+		&alertModel.WebhookDelivery{},
+		&alertModel.AlertRule{},
+		&alertModel.AlertEvent{},
+		&alertModel.AlertTaskState{},
+		&alertModel.AlertTaskFailure{},
+		&alertModel.AlertSilence{},
+		&alertModel.AlertEscalation{},
+		&alertModel.AlertDelivery{},
+		&alertModel.AlertDigest{},
+		&alertModel.AlertDigestItem{},
+		&alertModel.AlertTaskDailyStat{},
+		&alertModel.AlertOnCallSchedule{},
+		&alertModel.AlertOnCallOverride{},
+		&alertModel.AlertUserContact{},
+		&alertModel.AlertTaskGroup{},
 		&taskModel.CustomTask{},
+		&taskModel.TaskTrigger{},
 		&taskModel.TaskLog{},
 		&rbacModel.User{},
 		&rbacModel.Role{},
@@ -54,5 +81,45 @@ func Migrate() error {
 	); err != nil {
 		return fmt.Errorf("数据库自动迁移失败: %w", err)
 	}
+	// 任务告警组关联增加来源后，唯一索引改为 (source, task_id, group_id)
+	if DB.Migrator().HasIndex(&alertModel.AlertTaskGroup{}, "idx_alert_task_group") {
+		if err := DB.Migrator().DropIndex(&alertModel.AlertTaskGroup{}, "idx_alert_task_group"); err != nil {
+			return fmt.Errorf("删除旧索引失败: %w", err)
+		}
+	}
+	if err := migrateTaskAlertGroups(); err != nil {
+		return fmt.Errorf("迁移任务告警组关联失败: %w", err)
+	}
+	if err := migrateActiveTaskOperationIndex(); err != nil {
+		return fmt.Errorf("创建任务操作唯一索引失败: %w", err)
+	}
+	// 早期版本将重试耗尽的告警发送记录标记为 failed，统一为死信（dead）以便手动重试
+	if err := DB.Model(&alertModel.AlertDelivery{}).Where("status = ?", "failed").Update("status", "dead").Error; err != nil {
+		return fmt.Errorf("迁移告警发送记录状态失败: %w", err)
+	}
 	return nil
 }
+
+// migrateActiveTaskOperationIndex 同一任务同时只允许一个进行中的提交/停止操作，
+// 创建部分唯一索引前将历史遗留的重复进行中操作（保留最新一条）标记为失败
+func migrateActiveTaskOperationIndex() error {
+	if err := DB.Exec(`UPDATE task_operations SET status = 'failed', message = '存在重复的进行中操作，已标记失败', finished_at = NOW()
+WHERE status IN ('pending', 'running') AND id NOT IN (
+	SELECT MAX(id) FROM task_operations WHERE status IN ('pending', 'running') GROUP BY task_id
+)`).Error; err != nil {
+		return err
+	}
+	return DB.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_task_operation_active ON task_operations (task_id) WHERE status IN ('pending', 'running')`).Error
+}
+
+// migrateTaskAlertGroups 将 etl_tasks.alert_group 中逗号分隔的告警组 ID 转为关联记录，已有关联保持不变，
+// 不存在或已删除的告警组被忽略。关联变更时会同步回写 alert_group，重复执行不会恢复已删除的关联
+func migrateTaskAlertGroups() error {
+	return DB.Exec(`INSERT INTO alert_task_groups (source, task_id, group_id, notify_on, created_at, updated_at)
+SELECT DISTINCT 'etl', t.id, g.id, ?, NOW(), NOW()
+FROM etl_tasks t
+CROSS JOIN LATERAL unnest(string_to_array(t.alert_group, ',')) AS s(gid)
+JOIN alert_groups g ON g.id::text = btrim(s.gid) AND g.deleted_at IS NULL
+WHERE t.alert_group <> '' AND t.deleted_at IS NULL
+ON CONFLICT (source, task_id, group_id) DO NOTHING`, alertModel.DefaultNotifyOn).Error
+}
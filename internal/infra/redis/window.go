@@ -0,0 +1,53 @@
+package redis
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+type localWindow struct {
+	count     int64
+	expiresAt time.Time
+}
+
+var (
+	localMu      sync.Mutex
+	localWindows = map[string]*localWindow{}
+)
+
+// CountInWindow 对 key 计数并返回当前窗口内的次数，首次计数时设置 window 过期时间；
+// Redis 未初始化或异常时退化为进程内计数，多副本部署时限额按实例计算
+func CountInWindow(key string, window time.Duration) int64 {
+	if client != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		count, err := client.Incr(ctx, key).Result()
+		if err == nil {
+			if count == 1 {
+				client.Expire(ctx, key, window)
+			}
+			return count
+		}
+		log.Printf("[Redis] 计数失败，改用进程内计数: key=%s, error=%v", key, err)
+	}
+	return countLocal(key, window, time.Now())
+}
+
+func countLocal(key string, window time.Duration, now time.Time) int64 {
+	localMu.Lock()
+	defer localMu.Unlock()
+	for k, w := range localWindows {
+		if !now.Before(w.expiresAt) {
+			delete(localWindows, k)
+		}
+	}
+	w, ok := localWindows[key]
+	if !ok {
+		w = &localWindow{expiresAt: now.Add(window)}
+		localWindows[key] = w
+	}
+	w.count++
+	return w.count
+}
@@ -11,9 +11,12 @@ type AlertChannel struct {
 	Name           string         `gorm:"column:name;size:255" json:"name"`                       // 渠道名称
 	Type           string         `gorm:"column:type;size:64" json:"type"`                        // 渠道类型
 	Target         string         `gorm:"column:target;size:255" json:"target"`                   // 渠道目标
-	DingtalkSecret string         `gorm:"column:dingtalk_secret;size:255" json:"dingtalk_secret"` // 钉钉加签密钥
+	DingtalkSecret string         `gorm:"column:dingtalk_secret;size:255" json:"dingtalk_secret"` // 机器人加签密钥（钉钉、飞书）
+	Config         string         `gorm:"column:config" json:"config"`                            // 渠道类型相关配置，JSON，如通用 Webhook 的请求体模板
 	Status         int            `json:"status"`
 	TemplateID     uint           `json:"template_id"`
+	MaxAttempts    int            `json:"max_attempts"`   // 发送失败的最大尝试次数，0 表示默认 5 次
+	RetryInterval  int            `json:"retry_interval"` // 首次重试间隔（秒），之后翻倍，0 表示默认 30 秒
 	CreatedAt      time.Time      `json:"created_at"`
 	UpdatedAt      time.Time      `json:"updated_at"`
 	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
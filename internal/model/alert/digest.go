@@ -0,0 +1,36 @@
+package alert
+
+import "time"
+
+// AlertDigest 合并发送的告警摘要或每日汇总
+type AlertDigest struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	GroupID   uint      `gorm:"index" json:"group_id"`
+	Kind      string    `gorm:"size:16" json:"kind"`       // digest/summary
+	GroupKey  string    `gorm:"size:255" json:"group_key"` // 摘要为分组值（集群或状态），每日汇总为日期
+	DedupKey  string    `gorm:"size:128;uniqueIndex:idx_alert_digest_dedup,where:dedup_key <> ''" json:"-"`
+	Count     int       `json:"count"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AlertDigestItem 等待合并进摘要的告警，DigestID 为空表示尚未发送
+type AlertDigestItem struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	GroupID   uint      `gorm:"index:idx_alert_digest_item_pending,priority:1" json:"group_id"`
+	DigestID  *uint     `gorm:"index:idx_alert_digest_item_pending,priority:2" json:"digest_id"`
+	EventID   uint      `gorm:"index" json:"event_id"`
+	GroupKey  string    `gorm:"size:255" json:"group_key"`
+	Payload   string    `gorm:"type:text" json:"-"` // 告警模板数据快照，JSON
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AlertTaskDailyStat 任务每日运行统计，用于每日汇总
+type AlertTaskDailyStat struct {
+	Date      string    `gorm:"primaryKey;size:10" json:"date"` // YYYY-MM-DD
+	TaskID    uint      `gorm:"primaryKey;autoIncrement:false" json:"task_id"`
+	Runs      int       `json:"runs"`      // 提交次数
+	Succeeded int       `json:"succeeded"` // 作业完成次数
+	Failed    int       `json:"failed"`    // 作业失败及提交失败次数
+	Canceled  int       `json:"canceled"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
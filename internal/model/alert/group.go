@@ -7,15 +7,28 @@ import (
 )
 
 type AlertGroup struct {
-	ID          uint           `gorm:"primaryKey" json:"id"`
-	Name        string         `gorm:"size:255" json:"name"`
-	Description string         `gorm:"size:512" json:"description"`
-	Status      int            `json:"status"` // 0=禁用, 1=启用
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	Name        string `gorm:"size:255" json:"name"`
+	Description string `gorm:"size:512" json:"description"`
+	Status      int    `json:"status"` // 0=禁用, 1=启用
+	// 告警摘要：窗口内的告警按 DigestGroupBy 分组后合并为一条消息发送，DigestWindow 为 0 时逐条发送
+	DigestWindow      int            `json:"digest_window"`                  // 摘要窗口（分钟）
+	DigestGroupBy     string         `gorm:"size:16" json:"digest_group_by"` // cluster/status，为空时窗口内全部告警合并
+	DigestTemplateID  uint           `json:"digest_template_id"`             // 摘要模板，0 使用内置模板
+	SummaryTime       string         `gorm:"size:8" json:"summary_time"`     // 每日汇总发送时间 HH:MM，为空时不发送
+	SummaryTemplateID uint           `json:"summary_template_id"`            // 每日汇总模板，0 使用内置模板
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
+// 告警组成员类型，其余取值（email/robot/other）表示渠道成员
+const (
+	MemberTypeUser   = "user"   // ChannelID 为用户 ID
+	MemberTypeOnCall = "oncall" // ChannelID 为值班表 ID，通知时发送给当前值班人
+)
+
+// AlertGroupMember 告警组成员，ChannelType 为成员类型，ChannelID 为对应的渠道、用户或值班表 ID
 type AlertGroupMember struct {
 	ID          uint           `gorm:"primaryKey" json:"id"`
 	GroupID     uint           `gorm:"uniqueIndex:idx_alert_group_member_unique,where:deleted_at IS NULL" json:"group_id"`
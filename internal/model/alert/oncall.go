@@ -0,0 +1,43 @@
+package alert
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AlertOnCallSchedule 值班表，参与人按顺序轮换，每 RotationDays 天在 HandoffTime 交接
+type AlertOnCallSchedule struct {
+	ID           uint           `gorm:"primaryKey" json:"id"`
+	Name         string         `gorm:"size:255" json:"name"`
+	Description  string         `gorm:"size:512" json:"description"`
+	Timezone     string         `gorm:"size:64" json:"timezone"`       // IANA 时区，如 Asia/Shanghai
+	HandoffTime  string         `gorm:"size:8" json:"handoff_time"`    // 交接时间 HH:MM，按 Timezone 计算
+	RotationDays int            `json:"rotation_days"`                 // 每班天数
+	StartDate    string         `gorm:"size:10" json:"start_date"`     // 轮换起始日期 YYYY-MM-DD，当天交接时间起由第一位参与人值班
+	Participants string         `gorm:"size:1024" json:"participants"` // 参与人用户 ID，逗号分隔，按轮换顺序
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// AlertOnCallOverride 临时替班，时间段内由 UserID 值班，优先于轮换
+type AlertOnCallOverride struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	ScheduleID uint      `gorm:"index" json:"schedule_id"`
+	UserID     uint      `json:"user_id"`
+	StartsAt   time.Time `gorm:"index" json:"starts_at"`
+	EndsAt     time.Time `gorm:"index" json:"ends_at"`
+	Comment    string    `gorm:"size:512" json:"comment"`
+	CreatedBy  string    `gorm:"size:64" json:"created_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// AlertUserContact 用户的告警接收方式：通过 ChannelID 渠道发送，Target 为邮箱、手机号或 IM 账号
+type AlertUserContact struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"index" json:"user_id"`
+	ChannelID uint      `json:"channel_id"`
+	Target    string    `gorm:"size:255" json:"target"` // 邮件渠道为空时使用用户邮箱；机器人渠道为群内 @ 的手机号或用户 ID
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,121 @@
+package alert
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AlertRule 告警规则，TaskID 为空时对全部（或指定类型的）ETL 任务生效
+type AlertRule struct {
+	ID              uint           `gorm:"primaryKey" json:"id"`
+	Name            string         `gorm:"size:255" json:"name"`
+	Description     string         `gorm:"size:512" json:"description"`
+	TaskID          *uint          `gorm:"index" json:"task_id"`
+	TaskType        string         `gorm:"size:64" json:"task_type"`      // stream/batch，为空表示不限
+	ConditionType   string         `gorm:"size:64" json:"condition_type"` // status_transition/consecutive_failures/duration_exceeded/deadline_missed/scheduler_stopped
+	FromStatus      string         `gorm:"size:64" json:"from_status"`    // status_transition：原状态，为空表示任意
+	ToStatus        string         `gorm:"size:64" json:"to_status"`      // status_transition：新状态
+	Threshold       int            `json:"threshold"`                     // consecutive_failures：连续失败次数
+	DurationMinutes int            `json:"duration_minutes"`              // duration_exceeded：运行时长阈值（分钟）
+	Deadline        string         `gorm:"size:8" json:"deadline"`        // deadline_missed：每日截止时间 HH:MM
+	Severity        string         `gorm:"size:16" json:"severity"`       // info/warning/critical
+	AlertGroups     string         `gorm:"size:255" json:"alert_groups"`  // 告警组 ID，逗号分隔；为空时使用任务自身的告警组
+	RepeatInterval  int            `json:"repeat_interval"`               // 重复通知间隔（分钟），间隔内同一任务的重复告警只记录不通知；0 表示不抑制
+	EscalationID    *uint          `json:"escalation_id"`                 // 升级策略，为空表示不升级
+	Status          int            `json:"status"`                        // 1=启用, 0=禁用
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// AlertEvent 规则触发产生的告警记录，同一规则下 DedupKey 唯一，避免重复通知。
+// 告警状态依次为 firing（告警中）、acknowledged（已确认）、resolved（已恢复）
+type AlertEvent struct {
+	ID               uint       `gorm:"primaryKey" json:"id"`
+	RuleID           uint       `gorm:"uniqueIndex:idx_alert_event_dedup,priority:1" json:"rule_id"`
+	RuleName         string     `gorm:"size:255" json:"rule_name"`
+	ConditionType    string     `gorm:"size:64" json:"condition_type"`
+	Severity         string     `gorm:"size:16;index" json:"severity"`
+	Source           string     `gorm:"size:16;default:etl" json:"source"` // 任务来源 etl/custom，custom 时 TaskID 为自定义任务 ID
+	TaskID           *uint      `gorm:"index" json:"task_id"`
+	TaskName         string     `gorm:"size:255" json:"task_name"`
+	TaskType         string     `gorm:"size:64" json:"task_type"`
+	Status           string     `gorm:"size:64" json:"status"` // 触发时的作业状态
+	Reason           string     `gorm:"size:1024" json:"reason"`
+	DedupKey         string     `gorm:"size:255;uniqueIndex:idx_alert_event_dedup,priority:2" json:"dedup_key"`
+	Fingerprint      string     `gorm:"size:255;index" json:"fingerprint"`    // 规则 + 任务，用于重复通知抑制
+	AlertGroups      string     `gorm:"size:255" json:"alert_groups"`         // 实际通知的告警组
+	Suppressed       string     `gorm:"size:16;default:''" json:"suppressed"` // 未通知的原因：deduplicated/silenced，为空表示已通知
+	SilenceID        *uint      `json:"silence_id"`
+	EscalationID     *uint      `json:"escalation_id"`
+	EscalationLevel  int        `json:"escalation_level"` // 已执行的升级步骤数
+	NextEscalationAt *time.Time `gorm:"index" json:"next_escalation_at"`
+	AcknowledgedAt   *time.Time `json:"acknowledged_at"`
+	AcknowledgedBy   string     `gorm:"size:64" json:"acknowledged_by"`
+	State            string     `gorm:"size:16;default:firing;index" json:"state"` // firing/acknowledged/resolved
+	ResolvedAt       *time.Time `json:"resolved_at"`
+	ResolvedBy       string     `gorm:"size:64" json:"resolved_by"` // 手动恢复的操作人，自动恢复为 auto
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// AlertDelivery 告警通过单个渠道发送的记录，由发送队列异步投递，失败时按渠道配置退避重试
+type AlertDelivery struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	EventID     uint       `gorm:"index" json:"event_id"`  // 摘要与每日汇总为 0
+	DigestID    *uint      `gorm:"index" json:"digest_id"` // 摘要与每日汇总对应的 AlertDigest
+	ChannelID   uint       `gorm:"index" json:"channel_id"`
+	ChannelName string     `gorm:"size:255" json:"channel_name"`
+	ChannelType string     `gorm:"size:32" json:"channel_type"`
+	Recipient   string     `gorm:"type:text" json:"recipient"`                                        // 发送给告警组内用户时的接收人，逗号分隔；为空时发送到渠道配置的目标
+	Kind        string     `gorm:"size:16" json:"kind"`                                               // firing/escalation/resolved/digest/summary/notice
+	TemplateID  uint       `json:"template_id"`                                                       // 摘要与每日汇总使用的模板，0 为内置模板；单条告警使用渠道模板
+	Status      string     `gorm:"size:16;index:idx_alert_delivery_pending,priority:1" json:"status"` // pending/succeeded/dead，dead 为重试耗尽
+	Payload     string     `gorm:"type:text" json:"-"`                                                // 入队时的模板数据快照，JSON
+	Attempts    int        `json:"attempts"`
+	Error       string     `gorm:"size:1024" json:"error"`
+	NextRetryAt time.Time  `gorm:"index:idx_alert_delivery_pending,priority:2" json:"next_retry_at"`
+	DeliveredAt *time.Time `json:"delivered_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// AlertSilence 告警静默，时间窗口内满足全部匹配条件的告警只记录不通知
+type AlertSilence struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	Comment   string         `gorm:"size:512" json:"comment"`
+	Matchers  string         `json:"matchers"` // 匹配条件，JSON 数组：[{"name":"task_name","value":"...","regex":false}]
+	StartsAt  time.Time      `gorm:"index" json:"starts_at"`
+	EndsAt    time.Time      `gorm:"index" json:"ends_at"`
+	CreatedBy string         `gorm:"size:64" json:"created_by"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// AlertEscalation 升级策略，告警在各步骤的等待时间内未被确认时依次通知对应告警组
+type AlertEscalation struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	Name        string         `gorm:"size:255" json:"name"`
+	Description string         `gorm:"size:512" json:"description"`
+	Steps       string         `json:"steps"` // 升级步骤，JSON 数组：[{"after_minutes":15,"alert_groups":"2"}]，after_minutes 从告警触发时起算
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// AlertTaskState 告警评估所需的任务状态，如连续失败次数
+type AlertTaskState struct {
+	TaskID              uint      `gorm:"primaryKey;autoIncrement:false" json:"task_id"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// AlertTaskFailure 已计入连续失败次数的失败事件，事件重复投递时按 FailureKey 去重
+type AlertTaskFailure struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	TaskID     uint      `gorm:"index" json:"task_id"`
+	FailureKey string    `gorm:"size:128;uniqueIndex" json:"failure_key"`
+	Count      int       `json:"count"` // 计入后的连续失败次数
+	CreatedAt  time.Time `json:"created_at"`
+}
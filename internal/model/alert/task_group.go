@@ -0,0 +1,32 @@
+package alert
+
+import "time"
+
+// 任务关联告警组的通知事件
+const (
+	NotifyOnFail    = "fail"    // 任务失败类告警：状态变为失败/取消、连续失败、超时、截止时间未完成，自定义任务执行失败
+	NotifyOnRecover = "recover" // 告警恢复
+	NotifyOnFinish  = "finish"  // 离线作业完成（需配置 FINISHED 状态变化规则）
+	NotifyOnChange  = "change"  // 自定义任务产生的变更，如安全组授权 IP 变化
+
+	DefaultNotifyOn = "fail,recover,finish,change"
+)
+
+// 告警关联的任务来源
+const (
+	TaskSourceETL    = "etl"    // TaskID 为 ETL 任务 ID
+	TaskSourceCustom = "custom" // TaskID 为自定义任务 ID
+)
+
+// AlertTaskGroup 任务与告警组的关联，未指定告警组的规则按关联发送给任务的告警组。
+// ETL 任务的 EtlTask.AlertGroup 保留为关联告警组 ID 的逗号分隔镜像，兼容原有接口
+type AlertTaskGroup struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Source      string    `gorm:"size:16;default:etl;uniqueIndex:idx_alert_task_group_source,priority:1" json:"source"` // etl/custom
+	TaskID      uint      `gorm:"uniqueIndex:idx_alert_task_group_source,priority:2" json:"task_id"`
+	GroupID     uint      `gorm:"uniqueIndex:idx_alert_task_group_source,priority:3;index" json:"group_id"`
+	MinSeverity string    `gorm:"size:16" json:"min_severity"` // 最低告警级别 info/warning/critical，为空不限
+	NotifyOn    string    `gorm:"size:64" json:"notify_on"`    // 逗号分隔的通知事件 fail/recover/finish/change，默认全部
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
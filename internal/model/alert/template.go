@@ -6,11 +6,35 @@ import (
 	"gorm.io/gorm"
 )
 
+// AlertTemplate 告警模板，Variants 按渠道类型覆盖默认的标题与内容
 type AlertTemplate struct {
-	ID        uint           `gorm:"primaryKey" json:"id"`
-	Name      string         `gorm:"size:255" json:"name"`
-	Content   string         `gorm:"size:2048" json:"content"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID        uint                       `gorm:"primaryKey" json:"id"`
+	Name      string                     `gorm:"size:255" json:"name"`
+	Kind      string                     `gorm:"size:16;default:alert" json:"kind"` // alert 单条告警，digest 告警摘要，summary 每日汇总
+	Title     string                     `gorm:"size:512" json:"title"`             // 标题/邮件主题模板，为空时使用默认标题
+	Content   string                     `gorm:"type:text" json:"content"`          // 默认内容模板
+	Variants  map[string]TemplateVariant `gorm:"type:text;serializer:json" json:"variants"`
+	CreatedAt time.Time                  `json:"created_at"`
+	UpdatedAt time.Time                  `json:"updated_at"`
+	DeletedAt gorm.DeletedAt             `gorm:"index" json:"-"`
+}
+
+// TemplateVariant 某一渠道类型的模板变体，为空的字段沿用模板默认值
+type TemplateVariant struct {
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+// Resolve 返回渠道类型实际使用的标题与内容模板
+func (t AlertTemplate) Resolve(channelType string) (title, content string) {
+	title, content = t.Title, t.Content
+	if v, ok := t.Variants[channelType]; ok {
+		if v.Title != "" {
+			title = v.Title
+		}
+		if v.Content != "" {
+			content = v.Content
+		}
+	}
+	return title, content
 }
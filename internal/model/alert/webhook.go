@@ -0,0 +1,40 @@
+package alert
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WebhookSubscription 出站 Webhook 订阅，平台事件按类型和筛选条件推送到外部 URL
+type WebhookSubscription struct {
+	ID         uint           `gorm:"primaryKey" json:"id"`
+	Name       string         `gorm:"size:255" json:"name"`
+	URL        string         `gorm:"size:1024" json:"url"`
+	EventTypes string         `gorm:"size:1024" json:"event_types"` // 订阅的事件名称，逗号分隔，* 表示全部
+	Filter     string         `json:"filter"`                       // 筛选条件，JSON 对象：task_ids/task_types/statuses
+	Secret     string         `gorm:"size:512" json:"-"`            // HMAC 签名密钥，AES 加密存储
+	Headers    string         `json:"headers"`                      // 自定义请求头，JSON 对象
+	Status     int            `json:"status"`                       // 1 启用，0 禁用
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// WebhookDelivery Webhook 投递记录，失败时按退避重试
+type WebhookDelivery struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	SubscriptionID uint       `gorm:"index" json:"subscription_id"`
+	EventName      string     `gorm:"size:64" json:"event_name"`
+	Payload        string     `json:"payload"`
+	Status         string     `gorm:"size:16;index:idx_webhook_delivery_pending,priority:1" json:"status"` // pending/succeeded/failed
+	Attempts       int        `json:"attempts"`
+	ResponseCode   int        `json:"response_code"`
+	ResponseBody   string     `gorm:"size:2048" json:"response_body"`
+	Error          string     `gorm:"size:1024" json:"error"`
+	NextRetryAt    time.Time  `gorm:"index:idx_webhook_delivery_pending,priority:2" json:"next_retry_at"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	RedeliveryOf   *uint      `json:"redelivery_of"` // 手动重投时指向原投递记录
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
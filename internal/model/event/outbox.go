@@ -0,0 +1,18 @@
+package event
+
+import "time"
+
+// EventOutbox 持久化事件发件箱，事件先落库再由分发器投递，服务崩溃后不丢失
+type EventOutbox struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	Name        string     `gorm:"size:64;index" json:"name"`
+	Payload     string     `json:"payload"`                                                         // 事件内容，JSON 对象
+	Pending     string     `json:"pending"`                                                         // 待重试的订阅者，逗号分隔，为空表示全部订阅者
+	Status      string     `gorm:"size:16;index:idx_event_outbox_pending,priority:1" json:"status"` // pending/done/failed
+	Attempts    int        `json:"attempts"`                                                        // 认领时递增，回写结果时据此判断认领是否仍然有效
+	LastError   string     `gorm:"size:1024" json:"last_error"`
+	AvailableAt time.Time  `gorm:"index:idx_event_outbox_pending,priority:2" json:"available_at"` // 下次可投递时间，用于失败退避
+	LeaseUntil  *time.Time `json:"lease_until"`                                                   // 认领租约到期时间，分发实例崩溃后由其他实例接手
+	ProcessedAt *time.Time `json:"processed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+)
+
+// BackfillJob 离线任务补数作业，按日期区间拆分为多个分片依次提交
+type BackfillJob struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	TaskID      uint       `gorm:"index" json:"task_id"`
+	TaskName    string     `gorm:"size:255" json:"task_name"`
+	StartDate   string     `gorm:"size:32" json:"start_date"`   // 起始业务日期（含），格式 2006-01-02
+	EndDate     string     `gorm:"size:32" json:"end_date"`     // 结束业务日期（含），格式 2006-01-02
+	Granularity string     `gorm:"size:16" json:"granularity"`  // 分片粒度：day、month
+	VarName     string     `gorm:"size:64" json:"var_name"`     // 注入配置的变量名，默认 biz_date
+	DateFormat  string     `gorm:"size:64" json:"date_format"`  // 变量日期格式，如 yyyy-MM-dd
+	Concurrency int        `json:"concurrency"`                 // 同时运行的分片数
+	Status      string     `gorm:"size:32;index" json:"status"` // pending/running/paused/canceled/finished
+	Total       int        `json:"total"`
+	Succeeded   int        `json:"succeeded"`
+	Failed      int        `json:"failed"`
+	CreatedBy   string     `gorm:"size:128" json:"created_by"`
+	FinishedAt  *time.Time `json:"finished_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// BackfillSlice 补数分片，对应一次作业提交
+type BackfillSlice struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	BackfillID uint       `gorm:"index" json:"backfill_id"`
+	BizDate    string     `gorm:"size:32" json:"biz_date"`
+	Status     string     `gorm:"size:32;index" json:"status"` // pending/submitted/running/finished/failed/canceled
+	JobID      string     `gorm:"size:128" json:"job_id"`
+	JobStatus  string     `gorm:"size:64" json:"job_status"`
+	Message    string     `gorm:"size:2048" json:"message"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+// BulkOperation ETL 任务批量操作，按并发度异步执行
+type BulkOperation struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	Action      string     `gorm:"size:32" json:"action"`       // start/stop/restart/enable/disable/delete
+	Status      string     `gorm:"size:32;index" json:"status"` // pending/running/canceled/finished
+	Options     string     `json:"options"`                     // 操作参数与筛选条件，JSON 对象
+	Concurrency int        `json:"concurrency"`
+	Total       int        `json:"total"`
+	Succeeded   int        `json:"succeeded"`
+	Failed      int        `json:"failed"`
+	Skipped     int        `json:"skipped"`
+	CreatedBy   string     `gorm:"size:128" json:"created_by"`
+	Runner      string     `gorm:"size:128" json:"runner"` // 执行实例标识，租约续期时校验
+	LeaseUntil  *time.Time `json:"lease_until"`            // 执行实例的租约到期时间，实例崩溃后由其他实例接管
+	FinishedAt  *time.Time `json:"finished_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// BulkOperationItem 批量操作中单个任务的执行结果
+type BulkOperationItem struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	OperationID uint       `gorm:"index" json:"operation_id"`
+	TaskID      uint       `gorm:"index" json:"task_id"`
+	TaskName    string     `gorm:"size:255" json:"task_name"`
+	TaskType    string     `gorm:"size:64" json:"task_type"`
+	Status      string     `gorm:"size:32;index" json:"status"` // pending/running/succeeded/failed/skipped/canceled
+	JobStatus   string     `gorm:"size:64" json:"job_status"`
+	Message     string     `gorm:"size:1024" json:"message"`
+	StartedAt   *time.Time `json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Datasource 数据源目录，ETL 配置通过 ${datasource.<名称>.<键>} 引用
+type Datasource struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	Name        string         `gorm:"size:128;uniqueIndex:idx_datasource_name,where:deleted_at IS NULL" json:"name"`
+	Type        string         `gorm:"size:64" json:"type"` // jdbc/kafka/elasticsearch/其他
+	Description string         `gorm:"size:512" json:"description"`
+	Options     string         `json:"options"` // 非敏感连接参数，JSON 对象
+	Secrets     string         `json:"-"`       // 敏感参数，JSON 对象，值为 AES 加密后的密文
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// GitOpsReport GitOps 同步报告，记录每次同步发现的漂移
+type GitOpsReport struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	Dir        string     `gorm:"size:512" json:"dir"`
+	Trigger    string     `gorm:"size:32" json:"trigger"`      // schedule/manual
+	Status     string     `gorm:"size:32;index" json:"status"` // success/partial/failed
+	Created    int        `json:"created"`
+	Updated    int        `json:"updated"`
+	Disabled   int        `json:"disabled"`
+	Unchanged  int        `json:"unchanged"`
+	Errors     int        `json:"errors"`
+	Message    string     `gorm:"size:1024" json:"message"`
+	Details    string     `json:"details"` // 漂移明细，JSON 数组
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
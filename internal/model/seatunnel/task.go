@@ -7,20 +7,29 @@ import (
 )
 
 type EtlTask struct {
-	ID           uint           `gorm:"primaryKey" json:"id"`
-	Name         string         `gorm:"size:255" json:"name"`
-	Description  string         `gorm:"size:512" json:"description"`
-	TaskType     string         `gorm:"size:64" json:"task_type"`
-	CronExpr     string         `gorm:"size:128" json:"cron_expr"`
-	Config       string         `json:"config"`
-	ConfigFormat string         `gorm:"size:32" json:"config_format"`
-	JobID        *string        `gorm:"size:128;uniqueIndex" json:"job_id"`
-	JobStatus    string         `gorm:"size:64" json:"job_status"`
-	AlertGroup   string         `gorm:"size:255" json:"alert_group"`
-	Status       int            `json:"status"`
-	LastRunTime  *time.Time     `json:"last_run_time"`
-	FinishTime   *time.Time     `json:"finish_time"`
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+	ID              uint           `gorm:"primaryKey" json:"id"`
+	Name            string         `gorm:"size:255" json:"name"`
+	Description     string         `gorm:"size:512" json:"description"`
+	TaskType        string         `gorm:"size:64" json:"task_type"`
+	CronExpr        string         `gorm:"size:128" json:"cron_expr"`
+	Config          string         `json:"config"`
+	ConfigFormat    string         `gorm:"size:32" json:"config_format"`
+	EncryptConfig   bool           `gorm:"default:false" json:"encrypt_config"`   // 是否通过 SeaTunnel encrypt-config 加密配置
+	ShadeIdentifier string         `gorm:"size:64" json:"shade_identifier"`       // 配置加密插件标识，默认 base64
+	ConfigEncrypted bool           `gorm:"default:false" json:"config_encrypted"` // 存储的配置是否已加密
+	JobID           *string        `gorm:"size:128;uniqueIndex" json:"job_id"`
+	JobStatus       string         `gorm:"size:64" json:"job_status"`
+	AlertGroup      string         `gorm:"size:255" json:"alert_group"`       // 关联告警组 ID，逗号分隔；以 alert_task_groups 关联表为准，写入时同步
+	Cluster         string         `gorm:"size:64" json:"cluster"`            // SeaTunnel 集群名称，为空使用默认集群
+	TemplateID      *uint          `gorm:"index" json:"template_id"`          // 来源模板
+	TemplateParams  string         `json:"template_params"`                   // 模板参数，JSON 对象
+	TemplateVersion int            `gorm:"default:0" json:"template_version"` // 最近一次渲染使用的模板版本
+	Managed         bool           `gorm:"default:false" json:"managed"`      // 是否由 GitOps 目录管理
+	ManagedSource   string         `gorm:"size:512" json:"managed_source"`    // GitOps 定义文件相对路径
+	Status          int            `json:"status"`
+	LastRunTime     *time.Time     `json:"last_run_time"`
+	FinishTime      *time.Time     `json:"finish_time"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
 }
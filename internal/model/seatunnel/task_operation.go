@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// TaskOperation 单个任务的异步提交/停止操作，后台跟踪作业状态直至进入明确状态
+type TaskOperation struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	TaskID     uint       `gorm:"index" json:"task_id"`
+	TaskName   string     `gorm:"size:255" json:"task_name"`
+	TaskType   string     `gorm:"size:64" json:"task_type"`
+	Action     string     `gorm:"size:32" json:"action"`       // submit/stop
+	Status     string     `gorm:"size:32;index" json:"status"` // pending/running/succeeded/failed/timeout
+	SavePoint  bool       `json:"save_point"`
+	JobID      string     `gorm:"size:64" json:"job_id"`
+	JobStatus  string     `gorm:"size:64" json:"job_status"`
+	Message    string     `gorm:"size:1024" json:"message"`
+	Result     string     `json:"result"` // SeaTunnel 响应
+	CreatedBy  string     `gorm:"size:128" json:"created_by"`
+	FinishedAt *time.Time `json:"finished_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
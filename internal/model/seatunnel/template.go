@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EtlTaskTemplate ETL 任务模板，配置中通过 {{ .参数名 }} 引用模板参数
+type EtlTaskTemplate struct {
+	ID           uint           `gorm:"primaryKey" json:"id"`
+	Name         string         `gorm:"size:128;uniqueIndex:idx_etl_task_template_name,where:deleted_at IS NULL" json:"name"`
+	Description  string         `gorm:"size:512" json:"description"`
+	TaskType     string         `gorm:"size:64" json:"task_type"`
+	Config       string         `json:"config"`
+	ConfigFormat string         `gorm:"size:32" json:"config_format"`
+	Params       string         `json:"params"`                      // 参数定义，JSON 数组
+	CronExpr     string         `gorm:"size:128" json:"cron_expr"`   // 默认 cron 表达式
+	AlertGroup   string         `gorm:"size:255" json:"alert_group"` // 默认告警组
+	Cluster      string         `gorm:"size:64" json:"cluster"`      // 默认集群
+	Version      int            `gorm:"default:1" json:"version"`    // 每次修改配置或参数时递增
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+}
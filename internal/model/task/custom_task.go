@@ -3,14 +3,17 @@ package task
 import "time"
 
 type CustomTask struct {
-	ID          uint       `gorm:"primaryKey" json:"id"`
-	Name        string     `gorm:"size:255" json:"name"`
-	CustomType  string     `gorm:"size:64" json:"custom_type"`
-	CronExpr    string     `gorm:"size:128" json:"cron_expr"`
-	Description string     `gorm:"size:512" json:"description"`
-	Status      int        `json:"status"` // 1=启用, 0=禁用
-	LastRunTime *time.Time `json:"last_run_time"`
-	LastResult  string     `gorm:"size:1024" json:"last_result"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	Name          string     `gorm:"size:255" json:"name"`
+	CustomType    string     `gorm:"size:64" json:"custom_type"`
+	CronExpr      string     `gorm:"size:128" json:"cron_expr"`
+	Description   string     `gorm:"size:512" json:"description"`
+	Status        int        `json:"status"` // 1=启用, 0=禁用
+	LastRunTime   *time.Time `json:"last_run_time"`
+	LastStatus    string     `gorm:"size:16" json:"last_status"` // 最近一次执行状态：success、failed
+	LastResult    string     `gorm:"size:1024" json:"last_result"`
+	MaxRetries    int        `json:"max_retries"`    // 执行失败后的重试次数，0 表示不重试
+	RetryInterval int        `json:"retry_interval"` // 重试间隔（秒），为 0 时使用默认间隔
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
 }
@@ -5,9 +5,13 @@ import (
 )
 
 type TaskLog struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	TaskName  string    `gorm:"size:255" json:"task_name"` // 任务名称
-	Status    string    `gorm:"size:64" json:"status"`     // 状态：success、failed
-	Result    string    `gorm:"size:2048" json:"result"`   // 返回内容
-	CreatedAt time.Time `json:"created_at"`
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	TaskName      string    `gorm:"size:255" json:"task_name"`           // 任务名称
+	Status        string    `gorm:"size:64" json:"status"`               // 状态：success、failed
+	Result        string    `gorm:"size:2048" json:"result"`             // 返回内容
+	Error         string    `gorm:"size:1024" json:"error"`              // 失败原因
+	Details       string    `gorm:"type:text" json:"details"`            // 结构化执行详情（JSON）
+	Attempts      int       `json:"attempts"`                            // 执行次数，含重试
+	TriggerSource string    `gorm:"size:32;index" json:"trigger_source"` // 触发来源：schedule、webhook、backfill
+	CreatedAt     time.Time `json:"created_at"`
 }
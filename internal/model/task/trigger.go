@@ -0,0 +1,25 @@
+package task
+
+import "time"
+
+const (
+	TriggerTargetEtl    = "etl"
+	TriggerTargetCustom = "custom"
+)
+
+// TaskTrigger 入站触发器，上游系统通过 POST /api/hooks/:token 触发离线 ETL 任务或自定义任务
+type TaskTrigger struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	Name            string     `gorm:"size:255" json:"name"`
+	TargetType      string     `gorm:"size:32;index:idx_task_trigger_target" json:"target_type"` // etl/custom
+	TargetID        uint       `gorm:"index:idx_task_trigger_target" json:"target_id"`
+	TokenHash       string     `gorm:"size:64;uniqueIndex" json:"-"` // token 的 SHA-256，不保存明文
+	TokenPrefix     string     `gorm:"size:16" json:"token_prefix"`  // token 前几位，便于辨认
+	Secret          string     `gorm:"size:512" json:"-"`            // HMAC 签名密钥，AES 加密存储；为空时不校验签名
+	RateLimit       int        `gorm:"default:6" json:"rate_limit"`  // 每分钟最多触发次数
+	Status          int        `json:"status"`                       // 1=启用, 0=禁用
+	CreatedBy       string     `gorm:"size:128" json:"created_by"`
+	LastTriggeredAt *time.Time `json:"last_triggered_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
@@ -1,7 +1,9 @@
 package scheduler
 
 import (
+	"fmt"
 	"log"
+	"octoops/internal/event"
 	"octoops/internal/infra/postgres"
 	taskModel "octoops/internal/model/task"
 	aliyunService "octoops/internal/service/aliyun"
@@ -11,7 +13,30 @@ import (
 	"github.com/robfig/cron/v3"
 )
 
-func RegisterCustomTask(id uint, name, typ, spec string, status int, job func() string) {
+// 自定义任务重试的默认间隔与上限
+const (
+	defaultRetryInterval = 30 * time.Second
+	// MaxCustomTaskRetries 重试次数上限
+	MaxCustomTaskRetries = 5
+	// MaxCustomTaskRetryInterval 重试间隔上限（秒），重试在调度协程内等待，间隔过长会拖后下一次调度
+	MaxCustomTaskRetryInterval = 600
+)
+
+// ValidateRetryConfig 校验自定义任务的重试次数与重试间隔（秒）
+func ValidateRetryConfig(maxRetries, retryInterval int) error {
+	if maxRetries < 0 || maxRetries > MaxCustomTaskRetries {
+		return fmt.Errorf("max_retries 取值范围为 0-%d", MaxCustomTaskRetries)
+	}
+	if retryInterval < 0 || retryInterval > MaxCustomTaskRetryInterval {
+		return fmt.Errorf("retry_interval 取值范围为 0-%d 秒", MaxCustomTaskRetryInterval)
+	}
+	return nil
+}
+
+// retrySleep 重试前等待，测试中可替换
+var retrySleep = time.Sleep
+
+func RegisterCustomTask(id uint, name, typ, spec string, status int, job func() JobResult) {
 	task := &CustomTask{
 		ID:     id,
 		Name:   name,
@@ -40,27 +65,23 @@ func addCustomTaskToCron(task *CustomTask) {
 		mapsMu.Lock()
 		task.LastRun = time.Now()
 		mapsMu.Unlock()
-		result := task.Job()
+		// 重试配置以数据库为准，任务已删除时按不重试执行
+		var row taskModel.CustomTask
+		if err := postgres.DB.First(&row, task.ID).Error; err != nil {
+			row = taskModel.CustomTask{ID: task.ID, Name: task.Name, CustomType: task.Type}
+		}
+		result := RunCustomJob(row, event.TriggerSchedule, task.Job)
 		mapsMu.Lock()
-		task.LastResult = result
+		task.LastResult = result.Message
 		if currentEntryID != 0 {
 			entry := cronScheduler.Entry(currentEntryID)
 			task.NextRun = computeNextRunFromEntry(entry, time.Now())
 		}
 		mapsMu.Unlock()
-
-		postgres.DB.Model(&taskModel.CustomTask{}).Where("id = ?", task.ID).Updates(map[string]interface{}{
-			"last_run_time": task.LastRun,
-			"last_result":   task.LastResult,
-		})
-
-		postgres.DB.Create(&taskModel.TaskLog{
-			TaskName: task.Name,
-			Result:   result,
-			Status:   "success",
-		})
 	}
-	entryID, err := cronScheduler.AddFunc(task.Spec, jobFunc)
+	// 失败重试在本次执行内等待，上一次执行（含重试）未结束时跳过本次调度，避免同一任务并发执行
+	job := cron.NewChain(cron.SkipIfStillRunning(cron.VerbosePrintfLogger(log.Default()))).Then(cron.FuncJob(jobFunc))
+	entryID, err := cronScheduler.AddJob(task.Spec, job)
 	if err == nil {
 		mapsMu.Lock()
 		task.EntryID = entryID
@@ -105,20 +126,94 @@ func loadCustomTasksFromDB() {
 	}
 }
 
-func GetJobFuncByType(customType string) func() string {
+// RunCustomJob 执行自定义任务：失败时按任务的重试配置重试，写入最近一次执行状态与结果，
+// 并发布执行结束事件（执行日志与告警由订阅者处理）
+func RunCustomJob(task taskModel.CustomTask, trigger string, job func() JobResult) JobResult {
+	start := time.Now()
+	event.Publish(event.TaskRunStarted{Source: event.SourceCustom, Trigger: trigger, TaskID: task.ID, TaskName: task.Name, TaskType: task.CustomType, At: start})
+	result, attempts := runWithRetry(job, task.MaxRetries, time.Duration(task.RetryInterval)*time.Second, task.Name)
+	postgres.DB.Model(&taskModel.CustomTask{}).Where("id = ?", task.ID).Updates(map[string]interface{}{
+		"last_run_time": start,
+		"last_status":   result.Status,
+		"last_result":   truncateRunes(result.Message, 1024),
+	})
+	event.PublishDurable(event.TaskRunFinished{
+		Source:   event.SourceCustom,
+		Trigger:  trigger,
+		TaskID:   task.ID,
+		TaskName: task.Name,
+		TaskType: task.CustomType,
+		Status:   result.Status,
+		Result:   result.Message,
+		Error:    result.ErrorMessage(),
+		Details:  result.Details,
+		Attempts: attempts,
+		At:       time.Now(),
+	})
+	return result
+}
+
+// runWithRetry 执行任务，失败时最多重试 retries 次，返回最后一次的结果和执行次数；
+// 接口已校验取值范围，此处按上限截断校验前保存的配置
+func runWithRetry(job func() JobResult, retries int, interval time.Duration, name string) (JobResult, int) {
+	if retries > MaxCustomTaskRetries {
+		retries = MaxCustomTaskRetries
+	}
+	if interval <= 0 {
+		interval = defaultRetryInterval
+	}
+	if interval > MaxCustomTaskRetryInterval*time.Second {
+		interval = MaxCustomTaskRetryInterval * time.Second
+	}
+	for attempt := 1; ; attempt++ {
+		result := safeRun(job)
+		if !result.Failed() || attempt > retries {
+			return result, attempt
+		}
+		log.Printf("[Scheduler][自定义任务] 执行失败，%s 后重试(%d/%d): name=%s, result=%s", interval, attempt, retries, name, result.Message)
+		retrySleep(interval)
+	}
+}
+
+// safeRun 执行任务，panic 视为执行失败
+func safeRun(job func() JobResult) (result JobResult) {
+	defer func() {
+		if r := recover(); r != nil {
+			err := fmt.Errorf("%v", r)
+			result = jobFailed("自定义任务执行异常: "+err.Error(), err)
+		}
+	}()
+	result = job()
+	if result.Status == "" {
+		result.Status = JobStatusSuccess
+	}
+	return result
+}
+
+func GetJobFuncByType(customType string) func() JobResult {
 	switch customType {
 	case "ecs_sg_sync":
-		return func() string {
-			return aliyunService.SyncECSSecurityGroups()
+		return func() JobResult {
+			report, err := aliyunService.SyncECSSecurityGroups()
+			result := jobSuccess(fmt.Sprintf("ECS安全组同步完成: 共 %d 个配置", report.Total))
+			if err != nil {
+				result = jobFailed("ECS安全组同步失败: "+err.Error(), err)
+			}
+			result.Details = map[string]interface{}{"total": report.Total, "succeeded": report.Succeeded, "failed": report.Failed}
+			return result
 		}
 	case "job_status_sync":
-		return func() string {
+		return func() JobResult {
 			seatunnelService.SyncAllJobStatus()
-			return "作业状态同步完成"
+			return jobSuccess("作业状态同步完成")
+		}
+	case "gitops_reconcile":
+		return func() JobResult {
+			return ReconcileGitOps("schedule")
 		}
 	default:
-		return func() string {
-			return "自定义任务执行完成"
+		return func() JobResult {
+			return jobSuccess("自定义任务执行完成")
 		}
 	}
 }
@@ -3,6 +3,7 @@ package scheduler
 import (
 	"fmt"
 	"log"
+	"octoops/internal/event"
 	"octoops/internal/infra/postgres"
 	seatunnelModel "octoops/internal/model/seatunnel"
 	seatunnelService "octoops/internal/service/seatunnel"
@@ -75,6 +76,16 @@ func RemoveTask(taskID uint) {
 	}
 }
 
+// RefreshTask 按任务最新定义刷新调度，非启用的批处理任务会被移出调度器
+func RefreshTask(task seatunnelModel.EtlTask) {
+	RemoveTask(task.ID)
+	if task.TaskType == "batch" && task.Status == 1 && task.CronExpr != "" {
+		if err := AddTask(task); err != nil {
+			log.Printf("[Scheduler] failed to refresh task name=%s id=%d: %v", task.Name, task.ID, err)
+		}
+	}
+}
+
 func executeTask(task seatunnelModel.EtlTask) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -85,19 +96,34 @@ func executeTask(task seatunnelModel.EtlTask) {
 
 	now := time.Now()
 	postgres.DB.Model(&task).Update("last_run_time", now)
+	event.Publish(event.TaskRunStarted{Source: event.SourceEtl, Trigger: event.TriggerSchedule, TaskID: task.ID, TaskName: task.Name, TaskType: task.TaskType, At: now})
 
-	respBody, err := seatunnelService.SubmitJobInternal(task.ID, false)
+	respBody, err := seatunnelService.SubmitJobInternal(task.ID, false, nil)
 	if err != nil {
 		log.Printf("执行定时任务失败: ID=%d, 名称=%s, 错误=%v", task.ID, task.Name, err)
-		seatunnelService.WriteTaskLogWithStatus(task, []byte(err.Error()), "failed")
+		publishEtlRunFinished(task, "failed", err.Error())
 		return
 	}
 
 	seatunnelService.UpdateJobIdFromResponse(task.ID, respBody)
-	seatunnelService.WriteTaskLog(task, respBody)
+	publishEtlRunFinished(task, "success", string(respBody))
 	log.Printf("定时任务执行成功: ID=%d, 名称=%s", task.ID, task.Name)
 }
 
+// publishEtlRunFinished 发布定时 ETL 任务执行结束事件，执行日志由订阅者写入
+func publishEtlRunFinished(task seatunnelModel.EtlTask, status, result string) {
+	event.PublishDurable(event.TaskRunFinished{
+		Source:   event.SourceEtl,
+		Trigger:  event.TriggerSchedule,
+		TaskID:   task.ID,
+		TaskName: task.Name,
+		TaskType: task.TaskType,
+		Status:   status,
+		Result:   result,
+		At:       time.Now(),
+	})
+}
+
 func GetTaskNextRunTime(taskID uint) *time.Time {
 	mapsMu.RLock()
 	entryID, exists := taskEntryMap[taskID]
@@ -0,0 +1,39 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"octoops/internal/event"
+	"octoops/internal/infra/postgres"
+	taskModel "octoops/internal/model/task"
+	"sync"
+)
+
+var registerEventsOnce sync.Once
+
+// RegisterEventHandlers 注册调度器相关订阅者：自定义任务执行日志（含失败原因、执行详情与执行次数）
+func RegisterEventHandlers() {
+	registerEventsOnce.Do(func() {
+		event.On("scheduler.custom_task_log", func(ev event.TaskRunFinished) error {
+			if ev.Source != event.SourceCustom {
+				return nil
+			}
+			var details string
+			if len(ev.Details) > 0 {
+				b, err := json.Marshal(ev.Details)
+				if err != nil {
+					return err
+				}
+				details = string(b)
+			}
+			return postgres.DB.Create(&taskModel.TaskLog{
+				TaskName:      ev.TaskName,
+				Result:        truncateRunes(ev.Result, 2048),
+				Status:        ev.Status,
+				Error:         truncateRunes(ev.Error, 1024),
+				Details:       details,
+				Attempts:      ev.Attempts,
+				TriggerSource: ev.Trigger,
+			}).Error
+		})
+	})
+}
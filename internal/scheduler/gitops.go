@@ -0,0 +1,32 @@
+package scheduler
+
+import (
+	"errors"
+	gitopsService "octoops/internal/service/gitops"
+)
+
+// ReconcileGitOps 执行一次 GitOps 同步并刷新变更任务的调度，返回执行结果
+func ReconcileGitOps(trigger string) JobResult {
+	report, changed, err := gitopsService.Reconcile(trigger)
+	if err != nil {
+		return jobFailed("GitOps 同步失败: "+err.Error(), err)
+	}
+	for _, task := range changed {
+		RefreshTask(task)
+	}
+	message := "GitOps 同步" + report.Status + ": " + report.Message
+	result := jobSuccess(message)
+	if report.Status != "success" {
+		// 读取目录失败或部分任务同步失败
+		result = jobFailed(message, errors.New(report.Message))
+	}
+	result.Details = map[string]interface{}{
+		"report_id": report.ID,
+		"created":   report.Created,
+		"updated":   report.Updated,
+		"disabled":  report.Disabled,
+		"unchanged": report.Unchanged,
+		"errors":    report.Errors,
+	}
+	return result
+}
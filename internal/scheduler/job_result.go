@@ -0,0 +1,50 @@
+package scheduler
+
+import "fmt"
+
+// 自定义任务执行状态，与 event.TaskRunFinished.Status 一致
+const (
+	JobStatusSuccess = "success"
+	JobStatusFailed  = "failed"
+)
+
+// JobResult 自定义任务单次执行的结果
+type JobResult struct {
+	Status  string                 // success/failed
+	Message string                 // 结果摘要，写入 LastResult 与执行日志
+	Details map[string]interface{} // 结构化执行详情，以 JSON 写入执行日志
+	Err     error                  // 失败原因
+}
+
+// Failed 执行是否失败
+func (r JobResult) Failed() bool {
+	return r.Status == JobStatusFailed
+}
+
+// ErrorMessage 失败原因，成功时为空
+func (r JobResult) ErrorMessage() string {
+	if r.Err == nil {
+		return ""
+	}
+	return r.Err.Error()
+}
+
+func jobSuccess(message string) JobResult {
+	return JobResult{Status: JobStatusSuccess, Message: message}
+}
+
+func jobFailed(message string, err error) JobResult {
+	if err == nil {
+		err = fmt.Errorf("%s", message)
+	}
+	return JobResult{Status: JobStatusFailed, Message: message, Err: err}
+}
+
+// truncateRunes 按字符截断，避免超出数据库字段长度
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-3]) + "..."
+}
@@ -0,0 +1,82 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRunWithRetry(t *testing.T) {
+	var slept []time.Duration
+	retrySleep = func(d time.Duration) { slept = append(slept, d) }
+	defer func() { retrySleep = time.Sleep }()
+
+	calls := 0
+	flaky := func() JobResult {
+		calls++
+		if calls < 3 {
+			return jobFailed("同步失败", errors.New("timeout"))
+		}
+		return jobSuccess("同步完成")
+	}
+	result, attempts := runWithRetry(flaky, 5, 0, "flaky")
+	if result.Failed() || attempts != 3 {
+		t.Fatalf("runWithRetry() = %+v, %d, want success after 3 attempts", result, attempts)
+	}
+	if len(slept) != 2 || slept[0] != defaultRetryInterval {
+		t.Errorf("slept = %v, want 2 x %s", slept, defaultRetryInterval)
+	}
+
+	slept = nil
+	result, attempts = runWithRetry(func() JobResult { panic("boom") }, 1, time.Second, "panic")
+	if !result.Failed() || attempts != 2 || result.ErrorMessage() != "boom" {
+		t.Errorf("runWithRetry(panic) = %+v, %d, want failed after 2 attempts", result, attempts)
+	}
+
+	result, attempts = runWithRetry(func() JobResult { return JobResult{Message: "ok"} }, 3, time.Second, "default")
+	if result.Status != JobStatusSuccess || attempts != 1 {
+		t.Errorf("runWithRetry(empty status) = %+v, %d, want success", result, attempts)
+	}
+}
+
+func TestRunWithRetryCapsInterval(t *testing.T) {
+	var slept []time.Duration
+	retrySleep = func(d time.Duration) { slept = append(slept, d) }
+	defer func() { retrySleep = time.Sleep }()
+
+	_, attempts := runWithRetry(func() JobResult { return jobFailed("失败", errors.New("x")) }, 1, 2*time.Hour, "long")
+	if attempts != 2 || len(slept) != 1 || slept[0] != MaxCustomTaskRetryInterval*time.Second {
+		t.Errorf("attempts = %d, slept = %v, want interval capped to %ds", attempts, slept, MaxCustomTaskRetryInterval)
+	}
+}
+
+func TestValidateRetryConfig(t *testing.T) {
+	tests := []struct {
+		name              string
+		retries, interval int
+		wantErr           bool
+	}{
+		{"defaults", 0, 0, false},
+		{"max", MaxCustomTaskRetries, MaxCustomTaskRetryInterval, false},
+		{"too many retries", MaxCustomTaskRetries + 1, 30, true},
+		{"negative retries", -1, 30, true},
+		{"interval too long", 3, MaxCustomTaskRetryInterval + 1, true},
+		{"negative interval", 3, -1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateRetryConfig(tt.retries, tt.interval); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateRetryConfig(%d, %d) error = %v, wantErr %v", tt.retries, tt.interval, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTruncateRunes(t *testing.T) {
+	if got := truncateRunes("安全组同步失败", 5); got != "安全..." {
+		t.Errorf("truncateRunes() = %q", got)
+	}
+	if got := truncateRunes("ok", 5); got != "ok" {
+		t.Errorf("truncateRunes() = %q", got)
+	}
+}
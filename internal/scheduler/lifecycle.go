@@ -127,3 +127,8 @@ func StopScheduler() {
 		log.Println("[Scheduler] stop requested but scheduler is nil")
 	}
 }
+
+// IsRunning 调度器是否运行中
+func IsRunning() bool {
+	return schedulerRunning.Load()
+}
@@ -18,16 +18,16 @@ var etlTasksMap = map[uint]*seatunnelModel.EtlTask{}
 var mapsMu sync.RWMutex
 
 type CustomTask struct {
-	ID         uint          `json:"id"`
-	Name       string        `json:"name"`
-	Type       string        `json:"type"`
-	Spec       string        `json:"spec"`
-	Status     int           `json:"status"`
-	LastRun    time.Time     `json:"last_run"`
-	NextRun    time.Time     `json:"next_run"`
-	LastResult string        `json:"last_result"`
-	EntryID    cron.EntryID  `json:"entry_id"`
-	Job        func() string `json:"-"`
+	ID         uint             `json:"id"`
+	Name       string           `json:"name"`
+	Type       string           `json:"type"`
+	Spec       string           `json:"spec"`
+	Status     int              `json:"status"`
+	LastRun    time.Time        `json:"last_run"`
+	NextRun    time.Time        `json:"next_run"`
+	LastResult string           `json:"last_result"`
+	EntryID    cron.EntryID     `json:"entry_id"`
+	Job        func() JobResult `json:"-"`
 }
 
 func computeNextRunFromEntry(entry cron.Entry, now time.Time) time.Time {
@@ -0,0 +1,101 @@
+package alert
+
+import (
+	"fmt"
+	"log"
+	"octoops/internal/infra/postgres"
+	alertModel "octoops/internal/model/alert"
+	"octoops/internal/utils"
+	"strings"
+)
+
+// MaskedSecret 接口返回时替换渠道密钥的占位值，更新时提交该值表示保留原密钥
+const MaskedSecret = "******"
+
+// 加密后的密钥以 utils.EncryptAES 的版本前缀开头
+const encryptedSecretPrefix = "gcm:"
+
+// 便于测试替换
+var (
+	encryptSecret = utils.EncryptAES
+	decryptSecret = utils.DecryptAES
+)
+
+// secretEncrypted 短信/语音渠道的密钥为服务商 AccessKeySecret 或 Bearer Token，加密存储且不在接口中返回
+func secretEncrypted(channelType string) bool {
+	return channelType == "sms" || channelType == "voice"
+}
+
+// EncryptChannelSecret 返回渠道密钥的存储值：短信/语音渠道加密，其余渠道及已加密的值原样返回
+func EncryptChannelSecret(channelType, secret string) (string, error) {
+	if !secretEncrypted(channelType) || secret == "" || strings.HasPrefix(secret, encryptedSecretPrefix) {
+		return secret, nil
+	}
+	encrypted, err := encryptSecret(secret)
+	if err != nil {
+		return "", fmt.Errorf("加密渠道密钥失败: %w", err)
+	}
+	return encrypted, nil
+}
+
+// channelSecret 发送时还原渠道密钥明文，升级前保存的明文密钥原样使用
+func channelSecret(channel *alertModel.AlertChannel) (string, error) {
+	if !strings.HasPrefix(channel.DingtalkSecret, encryptedSecretPrefix) {
+		return channel.DingtalkSecret, nil
+	}
+	secret, err := decryptSecret(channel.DingtalkSecret)
+	if err != nil {
+		return "", fmt.Errorf("解密渠道密钥失败: %w", err)
+	}
+	return secret, nil
+}
+
+// MaskChannel 接口返回前隐藏短信/语音渠道的密钥
+func MaskChannel(channel alertModel.AlertChannel) alertModel.AlertChannel {
+	if secretEncrypted(channel.Type) && channel.DingtalkSecret != "" {
+		channel.DingtalkSecret = MaskedSecret
+	}
+	return channel
+}
+
+// prepareChannelSecretUpdate 处理更新请求中的密钥：提交占位值时保留原密钥，否则按渠道类型加密
+func prepareChannelSecretUpdate(channel alertModel.AlertChannel, updates map[string]interface{}) error {
+	secret, ok := updates["dingtalk_secret"].(string)
+	if !ok {
+		return nil
+	}
+	if secret == MaskedSecret {
+		delete(updates, "dingtalk_secret")
+		return nil
+	}
+	channelType := channel.Type
+	if t, ok := updates["type"].(string); ok && t != "" {
+		channelType = t
+	}
+	encrypted, err := EncryptChannelSecret(channelType, secret)
+	if err != nil {
+		return err
+	}
+	updates["dingtalk_secret"] = encrypted
+	return nil
+}
+
+// EncryptChannelSecrets 加密升级前以明文保存的短信/语音渠道密钥，启动时执行，重复执行无副作用
+func EncryptChannelSecrets() {
+	var channels []alertModel.AlertChannel
+	if err := postgres.DB.Where("type IN ? AND dingtalk_secret <> '' AND dingtalk_secret NOT LIKE ?", []string{"sms", "voice"}, encryptedSecretPrefix+"%").
+		Find(&channels).Error; err != nil {
+		log.Printf("[ALERT] 查询明文渠道密钥失败: %v", err)
+		return
+	}
+	for _, ch := range channels {
+		encrypted, err := EncryptChannelSecret(ch.Type, ch.DingtalkSecret)
+		if err != nil {
+			log.Printf("[ALERT] 加密渠道密钥失败: channel=%d, error=%v", ch.ID, err)
+			continue
+		}
+		if err := postgres.DB.Model(&alertModel.AlertChannel{}).Where("id = ?", ch.ID).Update("dingtalk_secret", encrypted).Error; err != nil {
+			log.Printf("[ALERT] 保存加密渠道密钥失败: channel=%d, error=%v", ch.ID, err)
+		}
+	}
+}
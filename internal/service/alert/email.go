@@ -3,7 +3,6 @@ package alert
 import (
 	"bytes"
 	"strings"
-	"text/template"
 
 	"octoops/internal/model/alert"
 	"octoops/internal/utils"
@@ -11,45 +10,26 @@ import (
 	"github.com/yuin/goldmark"
 )
 
-// 邮件测试发送
-func SendTestEmail(alert *alert.AlertChannel) error {
-	// 直接调用 utils.SendMail
-	return utils.SendMail(utils.MailOptions{
-		To:      alert.Target,
-		Subject: "OctoOps 测试通知",
-		Body:    "这是一条测试邮件通知。",
-	})
-}
-
-// 邮件模板发送
-func SendEmailWithTemplate(alert *alert.AlertChannel, tplContent string, data map[string]interface{}) error {
-	content := tplContent
-	if data != nil {
-		// 先渲染 Go 模板变量
-		tpl, err := template.New("mail").Parse(tplContent)
-		if err != nil {
-			return err
-		}
-		var buf bytes.Buffer
-		if err := tpl.Execute(&buf, data); err != nil {
-			return err
-		}
-		content = buf.String()
-	}
+// 邮件渠道：统一模板模式下始终按 Markdown 转 HTML 发送
+type emailNotifier struct{}
 
-	// 统一模板模式：邮件渠道始终按 Markdown 转 HTML 发送
-	body, err := markdownToHTML(content)
+func (emailNotifier) Send(channel *alert.AlertChannel, msg Message) error {
+	body, err := markdownToHTML(msg.Content)
 	if err != nil {
 		return err
 	}
-
 	return utils.SendMail(utils.MailOptions{
-		To:      alert.Target,
-		Subject: "OctoOps 告警通知",
+		To:      channel.Target,
+		Subject: msg.Title,
 		Body:    body,
 	})
 }
 
+func (emailNotifier) Preview(msg Message) (string, string, error) {
+	body, err := markdownToHTML(msg.Content)
+	return "html", body, err
+}
+
 func markdownToHTML(markdown string) (string, error) {
 	var htmlBuf bytes.Buffer
 	md := goldmark.New()
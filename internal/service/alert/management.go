@@ -5,11 +5,17 @@ import (
 	"fmt"
 	"octoops/internal/infra/postgres"
 	alertModel "octoops/internal/model/alert"
+	rbacModel "octoops/internal/model/rbac"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
 
-var ErrAlertGroupMemberExists = errors.New("告警组成员已存在")
+var (
+	ErrAlertGroupMemberExists = errors.New("告警组成员已存在")
+	ErrInvalidGroup           = errors.New("告警组参数无效")
+)
 
 func ListChannels() ([]alertModel.AlertChannel, error) {
 	var channels []alertModel.AlertChannel
@@ -18,6 +24,11 @@ func ListChannels() ([]alertModel.AlertChannel, error) {
 }
 
 func CreateChannel(channel *alertModel.AlertChannel) error {
+	secret, err := EncryptChannelSecret(channel.Type, channel.DingtalkSecret)
+	if err != nil {
+		return err
+	}
+	channel.DingtalkSecret = secret
 	return postgres.DB.Create(channel).Error
 }
 
@@ -32,6 +43,9 @@ func UpdateChannel(id string, updates map[string]interface{}) (alertModel.AlertC
 	if err != nil {
 		return alertModel.AlertChannel{}, err
 	}
+	if err := prepareChannelSecretUpdate(channel, updates); err != nil {
+		return alertModel.AlertChannel{}, err
+	}
 	if err := postgres.DB.Model(&channel).Updates(updates).Error; err != nil {
 		return alertModel.AlertChannel{}, err
 	}
@@ -49,6 +63,9 @@ func ListAlertGroups() ([]alertModel.AlertGroup, error) {
 }
 
 func CreateAlertGroup(group *alertModel.AlertGroup) error {
+	if err := validateGroup(group); err != nil {
+		return err
+	}
 	return postgres.DB.Create(group).Error
 }
 
@@ -63,10 +80,18 @@ func UpdateAlertGroup(id string, req alertModel.AlertGroup) (alertModel.AlertGro
 	if err != nil {
 		return alertModel.AlertGroup{}, err
 	}
+	if err := validateGroup(&req); err != nil {
+		return alertModel.AlertGroup{}, err
+	}
 	updates := map[string]interface{}{
-		"name":        req.Name,
-		"description": req.Description,
-		"status":      req.Status,
+		"name":                req.Name,
+		"description":         req.Description,
+		"status":              req.Status,
+		"digest_window":       req.DigestWindow,
+		"digest_group_by":     req.DigestGroupBy,
+		"digest_template_id":  req.DigestTemplateID,
+		"summary_time":        req.SummaryTime,
+		"summary_template_id": req.SummaryTemplateID,
 	}
 	if err := postgres.DB.Model(&group).Updates(updates).Error; err != nil {
 		return alertModel.AlertGroup{}, err
@@ -74,8 +99,35 @@ func UpdateAlertGroup(id string, req alertModel.AlertGroup) (alertModel.AlertGro
 	return group, nil
 }
 
-func DeleteAlertGroup(id string) error {
-	return postgres.DB.Delete(&alertModel.AlertGroup{}, id).Error
+// validateGroup 校验摘要与每日汇总配置
+func validateGroup(group *alertModel.AlertGroup) error {
+	if group.DigestWindow < 0 {
+		return fmt.Errorf("%w: digest_window 不能小于 0", ErrInvalidGroup)
+	}
+	switch group.DigestGroupBy {
+	case "", "cluster", "status":
+	default:
+		return fmt.Errorf("%w: digest_group_by 取值为 cluster/status", ErrInvalidGroup)
+	}
+	if group.SummaryTime != "" {
+		if _, err := time.Parse("15:04", group.SummaryTime); err != nil {
+			return fmt.Errorf("%w: summary_time 格式应为 HH:MM", ErrInvalidGroup)
+		}
+	}
+	check := func(id uint, kind string) error {
+		if id == 0 {
+			return nil
+		}
+		var tpl alertModel.AlertTemplate
+		if err := postgres.DB.First(&tpl, id).Error; err != nil || tpl.Kind != kind {
+			return fmt.Errorf("%w: 模板 %d 不存在或不是 %s 类型", ErrInvalidGroup, id, kind)
+		}
+		return nil
+	}
+	if err := check(group.DigestTemplateID, TemplateKindDigest); err != nil {
+		return err
+	}
+	return check(group.SummaryTemplateID, TemplateKindSummary)
 }
 
 func ListAlertGroupMembers(groupID string) ([]alertModel.AlertGroupMember, error) {
@@ -86,6 +138,17 @@ func ListAlertGroupMembers(groupID string) ([]alertModel.AlertGroupMember, error
 
 func CreateAlertGroupMember(groupID uint, member *alertModel.AlertGroupMember) error {
 	member.GroupID = groupID
+	switch member.ChannelType {
+	case alertModel.MemberTypeUser:
+		var user rbacModel.User
+		if err := postgres.DB.Select("id").First(&user, member.ChannelID).Error; err != nil {
+			return fmt.Errorf("%w: 用户 %d 不存在", ErrInvalidGroup, member.ChannelID)
+		}
+	case alertModel.MemberTypeOnCall:
+		if _, err := GetSchedule(member.ChannelID); err != nil {
+			return fmt.Errorf("%w: 值班表 %d 不存在", ErrInvalidGroup, member.ChannelID)
+		}
+	}
 
 	var existing alertModel.AlertGroupMember
 	if err := postgres.DB.Where(
@@ -120,6 +183,12 @@ func ListAlertTemplates() ([]alertModel.AlertTemplate, error) {
 }
 
 func CreateAlertTemplate(tpl *alertModel.AlertTemplate) error {
+	if tpl.Kind == "" {
+		tpl.Kind = TemplateKindAlert
+	}
+	if err := ValidateTemplate(*tpl); err != nil {
+		return err
+	}
 	return postgres.DB.Create(tpl).Error
 }
 
@@ -134,18 +203,57 @@ func UpdateAlertTemplate(id string, req alertModel.AlertTemplate) (alertModel.Al
 	if err != nil {
 		return alertModel.AlertTemplate{}, err
 	}
-	updates := map[string]interface{}{
-		"name":    req.Name,
-		"content": req.Content,
+	if req.Kind == "" {
+		req.Kind = tpl.Kind
 	}
-	if err := postgres.DB.Model(&tpl).Updates(updates).Error; err != nil {
+	if err := ValidateTemplate(req); err != nil {
 		return alertModel.AlertTemplate{}, err
 	}
-	return tpl, nil
+	err = postgres.DB.Transaction(func(tx *gorm.DB) error {
+		// 告警组按类型引用摘要/汇总模板，被引用时不允许修改类型
+		if req.Kind != tpl.Kind {
+			if err := checkTemplateUnused(tx, tpl); err != nil {
+				return err
+			}
+		}
+		return tx.Model(&tpl).Select("name", "kind", "title", "content", "variants").Updates(req).Error
+	})
+	if err != nil {
+		return alertModel.AlertTemplate{}, err
+	}
+	return GetAlertTemplateByID(id)
 }
 
+// DeleteAlertTemplate 删除告警模板；仍被告警组引用为摘要或汇总模板时返回 ErrTemplateInUse
 func DeleteAlertTemplate(id string) error {
-	return postgres.DB.Delete(&alertModel.AlertTemplate{}, id).Error
+	tpl, err := GetAlertTemplateByID(id)
+	if err != nil {
+		return err
+	}
+	return postgres.DB.Transaction(func(tx *gorm.DB) error {
+		if err := checkTemplateUnused(tx, tpl); err != nil {
+			return err
+		}
+		return tx.Delete(&tpl).Error
+	})
+}
+
+// checkTemplateUnused 检查模板是否被告警组引用为摘要或每日汇总模板
+func checkTemplateUnused(tx *gorm.DB, tpl alertModel.AlertTemplate) error {
+	var groups []alertModel.AlertGroup
+	if err := tx.Select("id", "name").
+		Where("digest_template_id = ? OR summary_template_id = ?", tpl.ID, tpl.ID).
+		Find(&groups).Error; err != nil {
+		return err
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(groups))
+	for _, g := range groups {
+		names = append(names, g.Name)
+	}
+	return fmt.Errorf("%w: 模板 %s 被告警组 %s 引用，请先修改告警组配置", ErrTemplateInUse, tpl.Name, strings.Join(names, ", "))
 }
 
 func ParseUint(s string) (uint, error) {
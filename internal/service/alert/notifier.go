@@ -0,0 +1,245 @@
+package alert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	alertModel "octoops/internal/model/alert"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// Message 渲染后的告警消息，Content 为 Markdown 文本；Data 为模板数据（*TemplateData、*DigestData 或 *SummaryData），
+// 供需要自定义消息体的渠道使用
+type Message struct {
+	Title    string
+	Content  string
+	Data     interface{}
+	Mentions []string // 需要 @ 提醒的接收人（手机号或 IM 用户 ID），仅群机器人渠道使用
+}
+
+// 未配置标题模板时使用的默认标题
+const defaultTitle = "OctoOps 告警通知"
+
+// Notifier 告警渠道发送器，每种渠道类型注册一个实现
+type Notifier interface {
+	Send(channel *alertModel.AlertChannel, msg Message) error
+}
+
+// Mentioner 群机器人类渠道实现该接口：发送给指定接收人时在群消息中 @ 接收人，而不是替换渠道目标
+type Mentioner interface {
+	SupportsMention() bool
+}
+
+var (
+	notifiersMu sync.RWMutex
+	notifiers   = map[string]Notifier{}
+)
+
+// RegisterNotifier 注册渠道类型的发送器，同名类型会被覆盖
+func RegisterNotifier(channelType string, n Notifier) {
+	notifiersMu.Lock()
+	defer notifiersMu.Unlock()
+	notifiers[channelType] = n
+}
+
+func GetNotifier(channelType string) (Notifier, bool) {
+	notifiersMu.RLock()
+	defer notifiersMu.RUnlock()
+	n, ok := notifiers[channelType]
+	return n, ok
+}
+
+// NotifierTypes 已注册的渠道类型
+func NotifierTypes() []string {
+	notifiersMu.RLock()
+	defer notifiersMu.RUnlock()
+	types := make([]string, 0, len(notifiers))
+	for t := range notifiers {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func init() {
+	RegisterNotifier("email", emailNotifier{})
+	RegisterNotifier("dingtalk", dingtalkNotifier{})
+	RegisterNotifier("wecom", wecomNotifier{})
+	// 前端及已有渠道记录使用 wechat 表示企业微信机器人
+	RegisterNotifier("wechat", wecomNotifier{})
+	RegisterNotifier("feishu", feishuNotifier{})
+	RegisterNotifier("slack", slackNotifier{})
+	RegisterNotifier("teams", teamsNotifier{})
+	RegisterNotifier("webhook", webhookNotifier{})
+	RegisterNotifier("sms", smsNotifier{kind: "sms"})
+	RegisterNotifier("voice", smsNotifier{kind: "voice"})
+}
+
+// Notify 按渠道类型选择模板变体，渲染标题与内容后通过对应的发送器发送
+func Notify(channel *alertModel.AlertChannel, tpl alertModel.AlertTemplate, data interface{}) error {
+	msg, err := renderMessage(tpl, channel.Type, data)
+	if err != nil {
+		return err
+	}
+	return send(channel, msg)
+}
+
+// NotifyRecipients 发送给指定接收人：群机器人渠道在消息中 @ 接收人，其余渠道以接收人替换渠道目标（邮箱、手机号等）；
+// recipients 为空时与 Notify 相同
+func NotifyRecipients(channel *alertModel.AlertChannel, tpl alertModel.AlertTemplate, data interface{}, recipients []string) error {
+	msg, err := renderMessage(tpl, channel.Type, data)
+	if err != nil {
+		return err
+	}
+	return sendTo(channel, msg, recipients)
+}
+
+// SupportsMention 渠道类型是否以 @ 方式通知接收人
+func SupportsMention(channelType string) bool {
+	n, ok := GetNotifier(channelType)
+	if !ok {
+		return false
+	}
+	m, ok := n.(Mentioner)
+	return ok && m.SupportsMention()
+}
+
+// 渠道目标为逗号分隔地址的渠道类型，发送给指定接收人时以接收人替换渠道目标
+var addressChannelTypes = map[string]bool{"email": true, "sms": true, "voice": true}
+
+// SupportsRecipients 渠道类型能否发送给指定接收人：群机器人 @ 接收人，邮件/短信/语音替换收件地址；
+// 通用 Webhook 等目标为 URL 的渠道不支持
+func SupportsRecipients(channelType string) bool {
+	return addressChannelTypes[channelType] || SupportsMention(channelType)
+}
+
+func sendTo(channel *alertModel.AlertChannel, msg Message, recipients []string) error {
+	if len(recipients) == 0 {
+		return send(channel, msg)
+	}
+	if SupportsMention(channel.Type) {
+		msg.Mentions = recipients
+		return send(channel, msg)
+	}
+	if !addressChannelTypes[channel.Type] {
+		return fmt.Errorf("%s 渠道不支持指定接收人", channel.Type)
+	}
+	target := *channel
+	target.Target = strings.Join(recipients, ",")
+	return send(&target, msg)
+}
+
+// renderMessage 渲染渠道类型对应的标题与内容
+func renderMessage(tpl alertModel.AlertTemplate, channelType string, data interface{}) (Message, error) {
+	titleTpl, contentTpl := tpl.Resolve(channelType)
+	if contentTpl == "" {
+		return Message{}, fmt.Errorf("模板 %s 没有可用于 %s 渠道的内容", tpl.Name, channelType)
+	}
+	title := defaultTitle
+	if titleTpl != "" {
+		rendered, err := renderTemplate(titleTpl, data)
+		if err != nil {
+			return Message{}, fmt.Errorf("标题模板渲染失败: %v", err)
+		}
+		title = rendered
+	}
+	content, err := renderTemplate(contentTpl, data)
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{Title: title, Content: content, Data: data}, nil
+}
+
+// SendTest 发送测试通知，内容按原文发送；未指定内容时发送固定的测试文本
+func SendTest(channel *alertModel.AlertChannel, content string) error {
+	if content == "" {
+		content = "这是一条测试通知。"
+	}
+	return send(channel, Message{Title: "OctoOps 测试通知", Content: content})
+}
+
+func send(channel *alertModel.AlertChannel, msg Message) error {
+	n, ok := GetNotifier(channel.Type)
+	if !ok {
+		return fmt.Errorf("未知渠道类型: %s", channel.Type)
+	}
+	return n.Send(channel, msg)
+}
+
+// renderTemplate 以 TemplateFuncs 渲染模板，data 为空时按原文返回
+func renderTemplate(tplContent string, data interface{}) (string, error) {
+	if v := reflect.ValueOf(data); !v.IsValid() || (v.Kind() == reflect.Ptr && v.IsNil()) {
+		return tplContent, nil
+	}
+	tpl, err := template.New("msg").Funcs(TemplateFuncs).Parse(tplContent)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// postJSON 以 JSON 发送请求，非 2xx 状态码视为失败，返回响应体
+func postJSON(url string, payload interface{}, headers map[string]string) ([]byte, error) {
+	var body []byte
+	switch p := payload.(type) {
+	case []byte:
+		body = p
+	default:
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("JSON序列化失败: %v", err)
+		}
+		body = data
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return respBody, fmt.Errorf("发送失败，状态码: %d, 响应: %s", resp.StatusCode, string(respBody))
+	}
+	return respBody, nil
+}
+
+// checkErrCode 校验机器人接口返回的业务错误码，兼容 errcode/errmsg 与 code/msg 两种格式
+func checkErrCode(respBody []byte) error {
+	var result struct {
+		ErrCode *int   `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+		Code    *int   `json:"code"`
+		Msg     string `json:"msg"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil
+	}
+	if result.ErrCode != nil && *result.ErrCode != 0 {
+		return fmt.Errorf("发送失败，错误码: %d, 错误信息: %s", *result.ErrCode, result.ErrMsg)
+	}
+	if result.Code != nil && *result.Code != 0 {
+		return fmt.Errorf("发送失败，错误码: %d, 错误信息: %s", *result.Code, result.Msg)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package alert
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	alertModel "octoops/internal/model/alert"
+)
+
+func TestFeishuSign(t *testing.T) {
+	// python3: base64(hmac.new(b"1700000000\nsecret", b"", sha256).digest())
+	got := feishuSign("secret", 1700000000)
+	want := "fiWS2+gh28DOydAv7hzONH/mDn9+b1Y4Y5ivXWXy8vA="
+	if got != want {
+		t.Fatalf("feishuSign() = %s, want %s", got, want)
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var gotBody map[string]interface{}
+	var gotHeader string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(data, &gotBody)
+		gotHeader = r.Header.Get("X-Token")
+	}))
+	defer srv.Close()
+
+	channel := &alertModel.AlertChannel{
+		Type:   "webhook",
+		Target: srv.URL,
+		Config: `{"body_template":"{\"text\":{{ json .Content }},\"job\":{{ json .Data.JobName }}}","headers":{"X-Token":"t"}}`,
+	}
+	tpl := alertModel.AlertTemplate{Content: "作业 {{ .JobName }} \"失败\""}
+	err := Notify(channel, tpl, &TemplateData{JobName: "ods_orders"})
+	if err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if gotBody["text"] != `作业 ods_orders "失败"` || gotBody["job"] != "ods_orders" {
+		t.Errorf("body = %v", gotBody)
+	}
+	if gotHeader != "t" {
+		t.Errorf("X-Token = %q, want t", gotHeader)
+	}
+}
+
+func TestRobotErrCode(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"errcode":310000,"errmsg":"sign not match"}`))
+	}))
+	defer srv.Close()
+
+	for _, typ := range []string{"dingtalk", "wecom", "wechat"} {
+		if err := SendTest(&alertModel.AlertChannel{Type: typ, Target: srv.URL}, ""); err == nil {
+			t.Errorf("%s: expected error for non-zero errcode", typ)
+		}
+	}
+}
+
+func TestNotifyUnknownType(t *testing.T) {
+	if err := SendTest(&alertModel.AlertChannel{Type: "pager"}, ""); err == nil {
+		t.Fatal("expected error for unknown channel type")
+	}
+}
+
+func TestTemplateVariantsAndFuncs(t *testing.T) {
+	var got map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(data, &got)
+	}))
+	defer srv.Close()
+
+	tpl := alertModel.AlertTemplate{
+		Title:   "[{{ upper .Severity }}] {{ .JobName }}",
+		Content: "default",
+		Variants: map[string]alertModel.TemplateVariant{
+			"webhook": {Content: "{{ default \"默认集群\" .Cluster }} {{ truncate 3 .ErrorMessage }} {{ formatTime .FiredAt \"15:04\" }}"},
+		},
+	}
+	data := &TemplateData{Severity: "critical", JobName: "ods", ErrorMessage: "timeout", FiredAt: time.Date(2024, 1, 2, 3, 4, 0, 0, time.UTC)}
+	if err := Notify(&alertModel.AlertChannel{Type: "webhook", Target: srv.URL}, tpl, data); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if got["title"] != "[CRITICAL] ods" {
+		t.Errorf("title = %v", got["title"])
+	}
+	if got["content"] != "默认集群 tim… 03:04" {
+		t.Errorf("content = %v", got["content"])
+	}
+}
@@ -0,0 +1,272 @@
+package alert
+
+import (
+	"errors"
+	"fmt"
+	"octoops/internal/infra/postgres"
+	alertModel "octoops/internal/model/alert"
+	rbacModel "octoops/internal/model/rbac"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrInvalidSchedule = errors.New("值班表参数无效")
+
+// OnCallShift 某一时刻的值班人及该班次的起止时间
+type OnCallShift struct {
+	ScheduleID uint      `json:"schedule_id"`
+	UserID     uint      `json:"user_id"`
+	Username   string    `json:"username,omitempty"`
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	Override   bool      `json:"override"` // 是否为临时替班
+}
+
+func ListSchedules() ([]alertModel.AlertOnCallSchedule, error) {
+	var schedules []alertModel.AlertOnCallSchedule
+	err := postgres.DB.Order("id desc").Find(&schedules).Error
+	return schedules, err
+}
+
+func GetSchedule(id interface{}) (alertModel.AlertOnCallSchedule, error) {
+	var schedule alertModel.AlertOnCallSchedule
+	err := postgres.DB.First(&schedule, id).Error
+	return schedule, err
+}
+
+func CreateSchedule(schedule *alertModel.AlertOnCallSchedule) error {
+	schedule.ID = 0
+	if err := validateSchedule(schedule); err != nil {
+		return err
+	}
+	return postgres.DB.Create(schedule).Error
+}
+
+func UpdateSchedule(id interface{}, req alertModel.AlertOnCallSchedule) (alertModel.AlertOnCallSchedule, error) {
+	schedule, err := GetSchedule(id)
+	if err != nil {
+		return schedule, err
+	}
+	req.ID = schedule.ID
+	req.CreatedAt = schedule.CreatedAt
+	if err := validateSchedule(&req); err != nil {
+		return schedule, err
+	}
+	if err := postgres.DB.Save(&req).Error; err != nil {
+		return schedule, err
+	}
+	return req, nil
+}
+
+// DeleteSchedule 删除值班表及其替班记录，并移除引用该值班表的告警组成员
+func DeleteSchedule(id interface{}) error {
+	schedule, err := GetSchedule(id)
+	if err != nil {
+		return err
+	}
+	return postgres.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("channel_type = ? AND channel_id = ?", alertModel.MemberTypeOnCall, schedule.ID).
+			Delete(&alertModel.AlertGroupMember{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("schedule_id = ?", schedule.ID).Delete(&alertModel.AlertOnCallOverride{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&schedule).Error
+	})
+}
+
+// validateSchedule 校验时区、交接时间与参与人并规范化字段
+func validateSchedule(s *alertModel.AlertOnCallSchedule) error {
+	s.Name = strings.TrimSpace(s.Name)
+	if s.Name == "" {
+		return fmt.Errorf("%w: name 不能为空", ErrInvalidSchedule)
+	}
+	if s.Timezone == "" {
+		s.Timezone = "Asia/Shanghai"
+	}
+	if _, err := time.LoadLocation(s.Timezone); err != nil {
+		return fmt.Errorf("%w: 无效的时区 %s", ErrInvalidSchedule, s.Timezone)
+	}
+	if s.HandoffTime == "" {
+		s.HandoffTime = "09:00"
+	}
+	if _, err := time.Parse("15:04", s.HandoffTime); err != nil {
+		return fmt.Errorf("%w: handoff_time 格式应为 HH:MM", ErrInvalidSchedule)
+	}
+	if s.RotationDays == 0 {
+		s.RotationDays = 7
+	}
+	if s.RotationDays < 1 {
+		return fmt.Errorf("%w: rotation_days 不能小于 1", ErrInvalidSchedule)
+	}
+	if _, err := time.Parse("2006-01-02", s.StartDate); err != nil {
+		return fmt.Errorf("%w: start_date 格式应为 YYYY-MM-DD", ErrInvalidSchedule)
+	}
+	ids := parseIDs(s.Participants)
+	if len(ids) == 0 {
+		return fmt.Errorf("%w: participants 至少包含一位用户", ErrInvalidSchedule)
+	}
+	var count int64
+	if err := postgres.DB.Model(&rbacModel.User{}).Where("id IN ?", ids).Count(&count).Error; err != nil {
+		return err
+	}
+	distinct := make(map[uint]bool, len(ids))
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		distinct[id] = true
+		parts = append(parts, strconv.FormatUint(uint64(id), 10))
+	}
+	if int(count) != len(distinct) {
+		return fmt.Errorf("%w: participants 包含不存在的用户", ErrInvalidSchedule)
+	}
+	s.Participants = strings.Join(parts, ",")
+	return nil
+}
+
+// parseIDs 解析逗号分隔的 ID 列表，忽略无效项
+func parseIDs(raw string) []uint {
+	var ids []uint
+	for _, part := range strings.Split(raw, ",") {
+		if id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 64); err == nil && id > 0 {
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids
+}
+
+// ShiftAt 计算 at 时刻的值班人：时间段覆盖 at 的替班优先（多条时以列表中靠后的为准），否则按轮换顺序
+func ShiftAt(s alertModel.AlertOnCallSchedule, overrides []alertModel.AlertOnCallOverride, at time.Time) (OnCallShift, error) {
+	shift := OnCallShift{ScheduleID: s.ID}
+	for _, o := range overrides {
+		if !at.Before(o.StartsAt) && at.Before(o.EndsAt) {
+			shift.UserID, shift.Start, shift.End, shift.Override = o.UserID, o.StartsAt, o.EndsAt, true
+		}
+	}
+	if shift.Override {
+		return shift, nil
+	}
+	participants := parseIDs(s.Participants)
+	if len(participants) == 0 {
+		return shift, fmt.Errorf("%w: 值班表 %s 没有参与人", ErrInvalidSchedule, s.Name)
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return shift, fmt.Errorf("%w: 无效的时区 %s", ErrInvalidSchedule, s.Timezone)
+	}
+	handoff, err := time.Parse("15:04", s.HandoffTime)
+	if err != nil {
+		return shift, fmt.Errorf("%w: handoff_time 格式应为 HH:MM", ErrInvalidSchedule)
+	}
+	startDate, err := time.Parse("2006-01-02", s.StartDate)
+	if err != nil {
+		return shift, fmt.Errorf("%w: start_date 格式应为 YYYY-MM-DD", ErrInvalidSchedule)
+	}
+	rotation := s.RotationDays
+	if rotation < 1 {
+		rotation = 1
+	}
+
+	// 按日历日计算，夏令时切换当天班次时长可能不是整 24 小时
+	local := at.In(loc)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	if local.Hour()*60+local.Minute() < handoff.Hour()*60+handoff.Minute() {
+		day = day.AddDate(0, 0, -1)
+	}
+	days := int(day.Sub(startDate).Hours() / 24)
+	index := days / rotation
+	if days < 0 && days%rotation != 0 {
+		index--
+	}
+	shiftDay := startDate.AddDate(0, 0, index*rotation)
+	shift.Start = time.Date(shiftDay.Year(), shiftDay.Month(), shiftDay.Day(), handoff.Hour(), handoff.Minute(), 0, 0, loc)
+	shift.End = time.Date(shiftDay.Year(), shiftDay.Month(), shiftDay.Day()+rotation, handoff.Hour(), handoff.Minute(), 0, 0, loc)
+	n := len(participants)
+	shift.UserID = participants[((index%n)+n)%n]
+	return shift, nil
+}
+
+// CurrentShift 值班表在 at 时刻的值班人
+func CurrentShift(scheduleID uint, at time.Time) (OnCallShift, error) {
+	schedule, err := GetSchedule(scheduleID)
+	if err != nil {
+		return OnCallShift{}, err
+	}
+	var overrides []alertModel.AlertOnCallOverride
+	if err := postgres.DB.Where("schedule_id = ? AND starts_at <= ? AND ends_at > ?", scheduleID, at, at).
+		Order("id").Find(&overrides).Error; err != nil {
+		return OnCallShift{}, err
+	}
+	shift, err := ShiftAt(schedule, overrides, at)
+	if err != nil {
+		return shift, err
+	}
+	var user rbacModel.User
+	if postgres.DB.Select("id", "username").First(&user, shift.UserID).Error == nil {
+		shift.Username = user.Username
+	}
+	return shift, nil
+}
+
+func ListOverrides(scheduleID uint) ([]alertModel.AlertOnCallOverride, error) {
+	var overrides []alertModel.AlertOnCallOverride
+	err := postgres.DB.Where("schedule_id = ?", scheduleID).Order("starts_at desc").Find(&overrides).Error
+	return overrides, err
+}
+
+func CreateOverride(override *alertModel.AlertOnCallOverride) error {
+	override.ID = 0
+	if _, err := GetSchedule(override.ScheduleID); err != nil {
+		return err
+	}
+	if !override.EndsAt.After(override.StartsAt) {
+		return fmt.Errorf("%w: ends_at 必须晚于 starts_at", ErrInvalidSchedule)
+	}
+	var user rbacModel.User
+	if err := postgres.DB.Select("id").First(&user, override.UserID).Error; err != nil {
+		return fmt.Errorf("%w: 用户 %d 不存在", ErrInvalidSchedule, override.UserID)
+	}
+	return postgres.DB.Create(override).Error
+}
+
+func DeleteOverride(scheduleID uint, id interface{}) error {
+	return postgres.DB.Where("schedule_id = ?", scheduleID).Delete(&alertModel.AlertOnCallOverride{}, id).Error
+}
+
+// ListContacts 用户的告警接收方式，userID 为 0 时返回全部
+func ListContacts(userID uint) ([]alertModel.AlertUserContact, error) {
+	var contacts []alertModel.AlertUserContact
+	db := postgres.DB.Order("id")
+	if userID > 0 {
+		db = db.Where("user_id = ?", userID)
+	}
+	err := db.Find(&contacts).Error
+	return contacts, err
+}
+
+func CreateContact(contact *alertModel.AlertUserContact) error {
+	contact.ID = 0
+	contact.Target = strings.TrimSpace(contact.Target)
+	var user rbacModel.User
+	if err := postgres.DB.Select("id", "email").First(&user, contact.UserID).Error; err != nil {
+		return fmt.Errorf("%w: 用户 %d 不存在", ErrInvalidSchedule, contact.UserID)
+	}
+	channel, err := GetChannelByID(strconv.FormatUint(uint64(contact.ChannelID), 10))
+	if err != nil {
+		return fmt.Errorf("%w: 渠道 %d 不存在", ErrInvalidSchedule, contact.ChannelID)
+	}
+	if !SupportsRecipients(channel.Type) {
+		return fmt.Errorf("%w: %s 渠道不支持发送给用户", ErrInvalidSchedule, channel.Type)
+	}
+	if contact.Target == "" && (channel.Type != "email" || user.Email == "") {
+		return fmt.Errorf("%w: target 不能为空", ErrInvalidSchedule)
+	}
+	return postgres.DB.Create(contact).Error
+}
+
+func DeleteContact(id interface{}) error {
+	return postgres.DB.Delete(&alertModel.AlertUserContact{}, id).Error
+}
@@ -0,0 +1,128 @@
+package alert
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	alertModel "octoops/internal/model/alert"
+)
+
+func TestShiftAt(t *testing.T) {
+	schedule := alertModel.AlertOnCallSchedule{
+		Timezone:     "Asia/Shanghai",
+		HandoffTime:  "09:30",
+		RotationDays: 1,
+		StartDate:    "2024-03-01",
+		Participants: "11,12,13",
+	}
+	loc, _ := time.LoadLocation("Asia/Shanghai")
+	cases := []struct {
+		name string
+		at   time.Time
+		user uint
+	}{
+		{"首班交接后", time.Date(2024, 3, 1, 9, 30, 0, 0, loc), 11},
+		{"次日交接前仍为上一班", time.Date(2024, 3, 2, 9, 29, 0, 0, loc), 11},
+		{"次日交接后", time.Date(2024, 3, 2, 9, 30, 0, 0, loc), 12},
+		{"按时区换算 UTC 时间", time.Date(2024, 3, 3, 1, 30, 0, 0, time.UTC), 13},
+		{"轮换一圈后回到第一位", time.Date(2024, 3, 4, 12, 0, 0, 0, loc), 11},
+		{"起始日期之前倒序轮换", time.Date(2024, 2, 29, 12, 0, 0, 0, loc), 13},
+	}
+	for _, c := range cases {
+		shift, err := ShiftAt(schedule, nil, c.at)
+		if err != nil {
+			t.Fatalf("%s: ShiftAt() error = %v", c.name, err)
+		}
+		if shift.UserID != c.user {
+			t.Errorf("%s: user = %d, want %d", c.name, shift.UserID, c.user)
+		}
+	}
+
+	shift, _ := ShiftAt(schedule, nil, time.Date(2024, 3, 2, 8, 0, 0, 0, loc))
+	if want := time.Date(2024, 3, 1, 9, 30, 0, 0, loc); !shift.Start.Equal(want) {
+		t.Errorf("start = %v, want %v", shift.Start, want)
+	}
+	if want := time.Date(2024, 3, 2, 9, 30, 0, 0, loc); !shift.End.Equal(want) {
+		t.Errorf("end = %v, want %v", shift.End, want)
+	}
+}
+
+func TestShiftAtWeeklyRotationAndOverride(t *testing.T) {
+	schedule := alertModel.AlertOnCallSchedule{
+		Timezone:     "America/New_York",
+		HandoffTime:  "10:00",
+		RotationDays: 7,
+		StartDate:    "2024-03-04",
+		Participants: "1,2",
+	}
+	loc, _ := time.LoadLocation("America/New_York")
+	// 3 月 10 日夏令时切换，按日历日计算仍在 3 月 11 日 10:00 交接
+	at := time.Date(2024, 3, 11, 9, 59, 0, 0, loc)
+	if shift, _ := ShiftAt(schedule, nil, at); shift.UserID != 1 {
+		t.Errorf("before handoff user = %d, want 1", shift.UserID)
+	}
+	shift, _ := ShiftAt(schedule, nil, at.Add(time.Minute))
+	if shift.UserID != 2 || !shift.End.Equal(time.Date(2024, 3, 18, 10, 0, 0, 0, loc)) {
+		t.Errorf("after handoff shift = %+v", shift)
+	}
+
+	overrides := []alertModel.AlertOnCallOverride{
+		{UserID: 5, StartsAt: at, EndsAt: at.Add(2 * time.Hour)},
+		{UserID: 6, StartsAt: at.Add(time.Hour), EndsAt: at.Add(3 * time.Hour)},
+	}
+	if shift, _ := ShiftAt(schedule, overrides, at.Add(30*time.Minute)); shift.UserID != 5 || !shift.Override {
+		t.Errorf("override shift = %+v, want user 5", shift)
+	}
+	if shift, _ := ShiftAt(schedule, overrides, at.Add(90*time.Minute)); shift.UserID != 6 {
+		t.Errorf("overlapping override user = %d, want 6", shift.UserID)
+	}
+	if shift, _ := ShiftAt(schedule, overrides, at.Add(3*time.Hour)); shift.UserID != 2 || shift.Override {
+		t.Errorf("after override shift = %+v, want rotation user 2", shift)
+	}
+}
+
+func TestNotifyRecipients(t *testing.T) {
+	var got map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		got = nil
+		_ = json.Unmarshal(data, &got)
+		_, _ = w.Write([]byte(`{"errcode":0}`))
+	}))
+	defer srv.Close()
+
+	tpl := alertModel.AlertTemplate{Content: "作业 {{ .JobName }} 失败"}
+	data := &TemplateData{JobName: "ods_orders"}
+	err := NotifyRecipients(&alertModel.AlertChannel{Type: "dingtalk", Target: srv.URL}, tpl, data, []string{"13800000000", "manager01"})
+	if err != nil {
+		t.Fatalf("dingtalk error = %v", err)
+	}
+	at, _ := got["at"].(map[string]interface{})
+	text := got["markdown"].(map[string]interface{})["text"].(string)
+	if at == nil || len(at["atMobiles"].([]interface{})) != 1 || len(at["atUserIds"].([]interface{})) != 1 {
+		t.Errorf("dingtalk at = %v", got["at"])
+	}
+	if !strings.Contains(text, "@13800000000") || !strings.Contains(text, "@manager01") {
+		t.Errorf("dingtalk text = %q", text)
+	}
+
+	if err := NotifyRecipients(&alertModel.AlertChannel{Type: "wecom", Target: srv.URL}, tpl, data, []string{"zhangsan"}); err != nil {
+		t.Fatalf("wecom error = %v", err)
+	}
+	if content := got["markdown"].(map[string]interface{})["content"].(string); !strings.HasSuffix(content, "<@zhangsan>") {
+		t.Errorf("wecom content = %q", content)
+	}
+
+	err = NotifyRecipients(&alertModel.AlertChannel{Type: "webhook", Target: srv.URL}, tpl, data, []string{"a@example.com"})
+	if err == nil {
+		t.Error("webhook with recipients: expected error")
+	}
+	if !SupportsRecipients("email") || !SupportsRecipients("feishu") || SupportsRecipients("teams") {
+		t.Error("SupportsRecipients() mismatch")
+	}
+}
@@ -1,20 +1,41 @@
 package alert
 
 import (
-	"bytes"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"net/url"
 	alertModel "octoops/internal/model/alert"
-	"text/template"
+	"strings"
 	"time"
 )
 
+// isMobile 纯数字的接收人视为手机号，其余视为 IM 用户 ID
+func isMobile(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// appendMentions 在内容末尾追加 @ 文本，format 为单个接收人的 @ 格式
+func appendMentions(content string, mentions []string, format func(string) string) string {
+	if len(mentions) == 0 {
+		return content
+	}
+	parts := make([]string, 0, len(mentions))
+	for _, m := range mentions {
+		parts = append(parts, format(m))
+	}
+	return content + "\n\n" + strings.Join(parts, " ")
+}
+
 // 钉钉加签
 func dingtalkSign(secret string) (string, string) {
 	timestamp := fmt.Sprintf("%d", time.Now().UnixNano()/1e6)
@@ -25,19 +46,20 @@ func dingtalkSign(secret string) (string, string) {
 	return timestamp, url.QueryEscape(sign)
 }
 
-// 钉钉机器人测试发送
-func SendTestRobot(alert *alertModel.AlertChannel) error {
-	msg := map[string]interface{}{
-		"msgtype": "text",
-		"text":    map[string]string{"content": "这是一条测试机器人通知。"},
-	}
-	data, err := json.Marshal(msg)
-	if err != nil {
-		return fmt.Errorf("JSON序列化失败: %v", err)
-	}
-	webhook := alert.Target
-	if alert.Type == "dingtalk" && alert.DingtalkSecret != "" {
-		timestamp, sign := dingtalkSign(alert.DingtalkSecret)
+// 飞书加签：以 timestamp + "\n" + secret 为密钥对空串做 HMAC-SHA256
+func feishuSign(secret string, timestamp int64) string {
+	stringToSign := fmt.Sprintf("%d\n%s", timestamp, secret)
+	mac := hmac.New(sha256.New, []byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// 钉钉机器人，markdown 消息
+type dingtalkNotifier struct{}
+
+func (dingtalkNotifier) Send(channel *alertModel.AlertChannel, msg Message) error {
+	webhook := channel.Target
+	if channel.DingtalkSecret != "" {
+		timestamp, sign := dingtalkSign(channel.DingtalkSecret)
 		if u, err := url.Parse(webhook); err == nil {
 			q := u.Query()
 			q.Set("timestamp", timestamp)
@@ -46,75 +68,79 @@ func SendTestRobot(alert *alertModel.AlertChannel) error {
 			webhook = u.String()
 		}
 	}
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Post(webhook, "application/json", bytes.NewReader(data))
+	payload := map[string]interface{}{
+		"msgtype": "markdown",
+		"markdown": map[string]string{
+			"title": msg.Title,
+			"text":  appendMentions(msg.Content, msg.Mentions, func(m string) string { return "@" + m }),
+		},
+	}
+	if len(msg.Mentions) > 0 {
+		// 钉钉要求被 @ 的手机号或用户 ID 同时出现在 at 字段与正文中
+		var mobiles, userIDs []string
+		for _, m := range msg.Mentions {
+			if isMobile(m) {
+				mobiles = append(mobiles, m)
+			} else {
+				userIDs = append(userIDs, m)
+			}
+		}
+		payload["at"] = map[string]interface{}{"atMobiles": mobiles, "atUserIds": userIDs}
+	}
+	respBody, err := postJSON(webhook, payload, nil)
 	if err != nil {
 		return err
 	}
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
-		if err != nil {
-			fmt.Printf("关闭响应体失败: %v\n", err)
-		}
-	}(resp.Body)
+	return checkErrCode(respBody)
+}
 
-	bodyBytes, _ := io.ReadAll(resp.Body)
-	fmt.Printf("钉钉响应状态码: %d, 内容: %s\n", resp.StatusCode, string(bodyBytes))
+func (dingtalkNotifier) SupportsMention() bool { return true }
 
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("发送失败，状态码: %d, 响应: %s", resp.StatusCode, string(bodyBytes))
-	}
-	return nil
-}
+// 企业微信群机器人，markdown 消息，以 <@userid> 提醒成员
+type wecomNotifier struct{}
 
-// 发送钉钉 markdown 消息，支持模板渲染
-func SendDingTalkMarkdownWithTemplate(webhook, secret, title, tplContent string, data map[string]interface{}) error {
-	content := tplContent
-	if data != nil {
-		// 渲染模板
-		tpl, err := template.New("msg").Parse(tplContent)
-		if err != nil {
-			return err
-		}
-		var buf bytes.Buffer
-		if err := tpl.Execute(&buf, data); err != nil {
-			return err
-		}
-		content = buf.String()
-	}
-	msg := map[string]interface{}{
+func (wecomNotifier) SupportsMention() bool { return true }
+
+func (wecomNotifier) Send(channel *alertModel.AlertChannel, msg Message) error {
+	respBody, err := postJSON(channel.Target, map[string]interface{}{
 		"msgtype": "markdown",
 		"markdown": map[string]string{
-			"title": title,
-			"text":  content,
+			"content": appendMentions(msg.Content, msg.Mentions, func(m string) string { return "<@" + m + ">" }),
 		},
+	}, nil)
+	if err != nil {
+		return err
 	}
-	jsonData, _ := json.Marshal(msg)
-	// 加签
-	if secret != "" {
-		timestamp, sign := dingtalkSign(secret)
-		if u, err := url.Parse(webhook); err == nil {
-			q := u.Query()
-			q.Set("timestamp", timestamp)
-			q.Set("sign", sign)
-			u.RawQuery = q.Encode()
-			webhook = u.String()
-		}
+	return checkErrCode(respBody)
+}
+
+// 飞书/Lark 群机器人，消息卡片中的 markdown 元素
+type feishuNotifier struct{}
+
+func (feishuNotifier) SupportsMention() bool { return true }
+
+func (feishuNotifier) Send(channel *alertModel.AlertChannel, msg Message) error {
+	payload := map[string]interface{}{
+		"msg_type": "interactive",
+		"card": map[string]interface{}{
+			"header": map[string]interface{}{
+				"title": map[string]string{"tag": "plain_text", "content": msg.Title},
+			},
+			"elements": []map[string]string{
+				{"tag": "markdown", "content": appendMentions(msg.Content, msg.Mentions, func(m string) string {
+					return "<at id=" + m + "></at>"
+				})},
+			},
+		},
+	}
+	if channel.DingtalkSecret != "" {
+		timestamp := time.Now().Unix()
+		payload["timestamp"] = fmt.Sprintf("%d", timestamp)
+		payload["sign"] = feishuSign(channel.DingtalkSecret, timestamp)
 	}
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Post(webhook, "application/json", bytes.NewReader(jsonData))
+	respBody, err := postJSON(channel.Target, payload, nil)
 	if err != nil {
 		return err
 	}
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
-		if err != nil {
-			fmt.Printf("关闭响应体失败: %v\n", err)
-		}
-	}(resp.Body)
-	bodyBytes, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("发送失败，状态码: %d, 响应: %s", resp.StatusCode, string(bodyBytes))
-	}
-	return nil
+	return checkErrCode(respBody)
 }
@@ -0,0 +1,206 @@
+package alert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	infraRedis "octoops/internal/infra/redis"
+	alertModel "octoops/internal/model/alert"
+	aliyunService "octoops/internal/service/aliyun"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+// 每个短信/语音渠道每小时默认最多发送的条数
+const defaultSMSRateLimit = 20
+
+// SMSConfig 短信/语音渠道配置，保存在渠道的 config 字段；渠道 target 为逗号分隔的手机号，
+// 渠道密钥为服务商的 AccessKeySecret（阿里云）或 Bearer Token（HTTP 网关）
+type SMSConfig struct {
+	Provider  string `json:"provider"`   // aliyun/http
+	RateLimit int    `json:"rate_limit"` // 每小时最多发送条数，0 使用默认值
+	// 阿里云
+	AccessKey        string            `json:"access_key"`
+	RegionID         string            `json:"region_id"`
+	Endpoint         string            `json:"endpoint"`           // 覆盖默认接入地址，带 http:// 前缀时以 HTTP 访问（如本地模拟服务）
+	SignName         string            `json:"sign_name"`          // 短信签名
+	TemplateCode     string            `json:"template_code"`      // 短信模板 CODE 或语音 TTS 模板 ID
+	CalledShowNumber string            `json:"called_show_number"` // 语音主叫显号
+	TemplateParams   map[string]string `json:"template_params"`    // 模板变量，值可引用 .Title/.Content/.Data.xxx；为空时传 content
+	// HTTP 网关
+	URL          string            `json:"url"`
+	BodyTemplate string            `json:"body_template"` // 请求体模板，见 WebhookConfig.BodyTemplate，另可引用 .Kind 与 .Phones；为空时发送默认结构
+	Headers      map[string]string `json:"headers"`
+}
+
+// SMSRequest 交给服务商的发送请求
+type SMSRequest struct {
+	Kind    string // sms/voice
+	Phones  []string
+	Secret  string
+	Message Message
+}
+
+// SMSProvider 短信/语音服务商
+type SMSProvider interface {
+	Send(cfg SMSConfig, req SMSRequest) error
+}
+
+var (
+	smsProvidersMu sync.RWMutex
+	smsProviders   = map[string]SMSProvider{
+		"aliyun": aliyunSMSProvider{},
+		"http":   httpSMSProvider{},
+	}
+)
+
+// RegisterSMSProvider 注册短信/语音服务商
+func RegisterSMSProvider(name string, p SMSProvider) {
+	smsProvidersMu.Lock()
+	defer smsProvidersMu.Unlock()
+	smsProviders[name] = p
+}
+
+// smsNotifier 短信（kind=sms）与语音电话（kind=voice）渠道
+type smsNotifier struct {
+	kind string
+}
+
+func (n smsNotifier) Send(channel *alertModel.AlertChannel, msg Message) error {
+	var cfg SMSConfig
+	if err := json.Unmarshal([]byte(channel.Config), &cfg); err != nil {
+		return fmt.Errorf("短信渠道配置无效: %v", err)
+	}
+	smsProvidersMu.RLock()
+	provider, ok := smsProviders[cfg.Provider]
+	smsProvidersMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("未知短信服务商: %s", cfg.Provider)
+	}
+	var phones []string
+	for _, p := range strings.Split(channel.Target, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			phones = append(phones, p)
+		}
+	}
+	if len(phones) == 0 {
+		return fmt.Errorf("未配置手机号")
+	}
+	if !allowSMS(channel.ID, cfg.RateLimit) {
+		return fmt.Errorf("渠道发送过于频繁，已超过每小时限额")
+	}
+	secret, err := channelSecret(channel)
+	if err != nil {
+		return err
+	}
+	return provider.Send(cfg, SMSRequest{Kind: n.kind, Phones: phones, Secret: secret, Message: msg})
+}
+
+// allowSMS 每小时计数限流，多副本经 Redis 共享计数；Redis 不可用时退化为进程内计数，不会放开限额
+func allowSMS(channelID uint, limit int) bool {
+	if limit <= 0 {
+		limit = defaultSMSRateLimit
+	}
+	key := fmt.Sprintf("octoops:alert:sms:%d:%d", channelID, time.Now().Unix()/3600)
+	return infraRedis.CountInWindow(key, 2*time.Hour) <= int64(limit)
+}
+
+// templateParams 渲染服务商模板变量，未配置时传入消息正文
+func templateParams(cfg SMSConfig, msg Message) (string, error) {
+	params := map[string]string{}
+	if len(cfg.TemplateParams) == 0 {
+		params["content"] = msg.Content
+	}
+	for name, text := range cfg.TemplateParams {
+		tpl, err := template.New(name).Funcs(TemplateFuncs).Parse(text)
+		if err != nil {
+			return "", fmt.Errorf("模板变量 %s 解析失败: %v", name, err)
+		}
+		var buf bytes.Buffer
+		if err := tpl.Execute(&buf, msg); err != nil {
+			return "", fmt.Errorf("模板变量 %s 渲染失败: %v", name, err)
+		}
+		params[name] = buf.String()
+	}
+	data, err := json.Marshal(params)
+	return string(data), err
+}
+
+// aliyunSMSProvider 阿里云短信服务与语音服务
+type aliyunSMSProvider struct{}
+
+func (aliyunSMSProvider) Send(cfg SMSConfig, req SMSRequest) error {
+	region := cfg.RegionID
+	if region == "" {
+		region = "cn-hangzhou"
+	}
+	openCfg, err := aliyunService.NewOpenAPIConfig(cfg.AccessKey, req.Secret, region)
+	if err != nil {
+		return err
+	}
+	if endpoint, ok := strings.CutPrefix(cfg.Endpoint, "http://"); ok {
+		openCfg.Endpoint = tea.String(endpoint)
+		openCfg.Protocol = tea.String("HTTP")
+	} else if cfg.Endpoint != "" {
+		openCfg.Endpoint = tea.String(strings.TrimPrefix(cfg.Endpoint, "https://"))
+	}
+	params, err := templateParams(cfg, req.Message)
+	if err != nil {
+		return err
+	}
+	if req.Kind == "sms" {
+		return aliyunService.SendSms(openCfg, strings.Join(req.Phones, ","), cfg.SignName, cfg.TemplateCode, params)
+	}
+	var errs []string
+	for _, phone := range req.Phones {
+		if err := aliyunService.SingleCallByTts(openCfg, cfg.CalledShowNumber, phone, cfg.TemplateCode, params); err != nil {
+			errs = append(errs, phone+": "+err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("语音通知失败: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// httpSMSProvider 通用 HTTP 短信/语音网关，默认请求体为 {"kind","phones","title","content"}
+type httpSMSProvider struct{}
+
+func (httpSMSProvider) Send(cfg SMSConfig, req SMSRequest) error {
+	if cfg.URL == "" {
+		return fmt.Errorf("未配置网关地址")
+	}
+	var body []byte
+	var err error
+	if cfg.BodyTemplate == "" {
+		body, err = json.Marshal(map[string]interface{}{
+			"kind":    req.Kind,
+			"phones":  req.Phones,
+			"title":   req.Message.Title,
+			"content": req.Message.Content,
+		})
+	} else {
+		// 模板中可额外引用 .Kind 与 .Phones
+		body, err = renderBody(cfg.BodyTemplate, struct {
+			Message
+			Kind   string
+			Phones []string
+		}{req.Message, req.Kind, req.Phones})
+	}
+	if err != nil {
+		return err
+	}
+	headers := map[string]string{}
+	for k, v := range cfg.Headers {
+		headers[k] = v
+	}
+	if req.Secret != "" {
+		headers["Authorization"] = "Bearer " + req.Secret
+	}
+	_, err = postJSON(cfg.URL, body, headers)
+	return err
+}
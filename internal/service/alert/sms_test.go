@@ -0,0 +1,156 @@
+package alert
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	alertModel "octoops/internal/model/alert"
+)
+
+type fakeSMSProvider struct {
+	reqs []SMSRequest
+}
+
+func (p *fakeSMSProvider) Send(cfg SMSConfig, req SMSRequest) error {
+	p.reqs = append(p.reqs, req)
+	return nil
+}
+
+func TestSMSNotifierUsesProvider(t *testing.T) {
+	fake := &fakeSMSProvider{}
+	RegisterSMSProvider("fake", fake)
+	channel := &alertModel.AlertChannel{Type: "voice", Target: "13800000000, 13900000000", DingtalkSecret: "s", Config: `{"provider":"fake"}`}
+	if err := Notify(channel, alertModel.AlertTemplate{Content: "作业 {{ .JobName }} 失败"}, &TemplateData{JobName: "ods"}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if len(fake.reqs) != 1 {
+		t.Fatalf("provider called %d times, want 1", len(fake.reqs))
+	}
+	req := fake.reqs[0]
+	if req.Kind != "voice" || len(req.Phones) != 2 || req.Phones[1] != "13900000000" || req.Secret != "s" || req.Message.Content != "作业 ods 失败" {
+		t.Errorf("unexpected request: %+v", req)
+	}
+}
+
+func TestHTTPSMSProvider(t *testing.T) {
+	var body map[string]interface{}
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(data, &body)
+		auth = r.Header.Get("Authorization")
+	}))
+	defer srv.Close()
+
+	channel := &alertModel.AlertChannel{Type: "sms", Target: "13800000000", DingtalkSecret: "token", Config: `{"provider":"http","url":"` + srv.URL + `"}`}
+	if err := SendTest(channel, ""); err != nil {
+		t.Fatalf("SendTest() error = %v", err)
+	}
+	if body["kind"] != "sms" || body["content"] != "这是一条测试通知。" || auth != "Bearer token" {
+		t.Errorf("body = %v, auth = %q", body, auth)
+	}
+}
+
+func TestAliyunSMSProvider(t *testing.T) {
+	var query map[string][]string
+	var action string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		action = r.Header.Get("x-acs-action")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"Code":"OK","Message":"OK","RequestId":"r"}`))
+	}))
+	defer srv.Close()
+
+	cfg := `{"provider":"aliyun","access_key":"ak","endpoint":"` + srv.URL + `","sign_name":"OctoOps","template_code":"SMS_1","template_params":{"name":"{{ .Data.JobName }}"}}`
+	channel := &alertModel.AlertChannel{Type: "sms", Target: "13800000000", DingtalkSecret: "sk", Config: cfg}
+	if err := Notify(channel, alertModel.AlertTemplate{Content: "content"}, &TemplateData{JobName: "ods"}); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	get := func(k string) string {
+		if v := query[k]; len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+	if action != "SendSms" || get("PhoneNumbers") != "13800000000" || get("TemplateCode") != "SMS_1" || !strings.Contains(get("TemplateParam"), `"name":"ods"`) {
+		t.Errorf("unexpected request: action=%s, query=%v", action, query)
+	}
+}
+
+func TestSMSNotifierDecryptsSecret(t *testing.T) {
+	origEncrypt, origDecrypt := encryptSecret, decryptSecret
+	defer func() { encryptSecret, decryptSecret = origEncrypt, origDecrypt }()
+	encryptSecret = func(s string) (string, error) { return encryptedSecretPrefix + "enc(" + s + ")", nil }
+	decryptSecret = func(s string) (string, error) {
+		return strings.TrimSuffix(strings.TrimPrefix(s, encryptedSecretPrefix+"enc("), ")"), nil
+	}
+
+	stored, err := EncryptChannelSecret("sms", "sk-123")
+	if err != nil || stored != "gcm:enc(sk-123)" {
+		t.Fatalf("EncryptChannelSecret() = (%q, %v)", stored, err)
+	}
+	// 已加密的值和非短信渠道的密钥原样保存
+	if again, _ := EncryptChannelSecret("sms", stored); again != stored {
+		t.Errorf("重复加密: %q", again)
+	}
+	if plain, _ := EncryptChannelSecret("dingtalk", "SEC1"); plain != "SEC1" {
+		t.Errorf("钉钉密钥不应加密: %q", plain)
+	}
+
+	fake := &fakeSMSProvider{}
+	RegisterSMSProvider("fake-secret", fake)
+	channel := &alertModel.AlertChannel{ID: 9001, Type: "sms", Target: "13800000000", DingtalkSecret: stored, Config: `{"provider":"fake-secret"}`}
+	if err := SendTest(channel, ""); err != nil {
+		t.Fatalf("SendTest() error = %v", err)
+	}
+	if len(fake.reqs) != 1 || fake.reqs[0].Secret != "sk-123" {
+		t.Errorf("provider 应收到解密后的密钥: %+v", fake.reqs)
+	}
+
+	if masked := MaskChannel(*channel); masked.DingtalkSecret != MaskedSecret {
+		t.Errorf("MaskChannel() secret = %q", masked.DingtalkSecret)
+	}
+	if kept := MaskChannel(alertModel.AlertChannel{Type: "dingtalk", DingtalkSecret: "SEC1"}); kept.DingtalkSecret != "SEC1" {
+		t.Errorf("MaskChannel() 不应隐藏钉钉密钥: %q", kept.DingtalkSecret)
+	}
+}
+
+func TestPrepareChannelSecretUpdate(t *testing.T) {
+	origEncrypt := encryptSecret
+	defer func() { encryptSecret = origEncrypt }()
+	encryptSecret = func(s string) (string, error) { return encryptedSecretPrefix + s, nil }
+
+	channel := alertModel.AlertChannel{Type: "sms", DingtalkSecret: "gcm:old"}
+	updates := map[string]interface{}{"dingtalk_secret": MaskedSecret, "target": "13800000000"}
+	if err := prepareChannelSecretUpdate(channel, updates); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := updates["dingtalk_secret"]; ok {
+		t.Errorf("提交占位值时应保留原密钥: %v", updates)
+	}
+
+	updates = map[string]interface{}{"dingtalk_secret": "new"}
+	if err := prepareChannelSecretUpdate(channel, updates); err != nil || updates["dingtalk_secret"] != "gcm:new" {
+		t.Errorf("新密钥应加密: %v, %v", updates, err)
+	}
+
+	// 改为短信渠道时按新类型加密
+	updates = map[string]interface{}{"type": "voice", "dingtalk_secret": "token"}
+	if err := prepareChannelSecretUpdate(alertModel.AlertChannel{Type: "webhook"}, updates); err != nil || updates["dingtalk_secret"] != "gcm:token" {
+		t.Errorf("改为语音渠道时应加密: %v, %v", updates, err)
+	}
+}
+
+func TestAllowSMSWithoutRedis(t *testing.T) {
+	// 未初始化 Redis 时按进程内计数限流，超过限额后拒绝
+	for i := 1; i <= 3; i++ {
+		if got, want := allowSMS(9002, 2), i <= 2; got != want {
+			t.Errorf("第 %d 次 allowSMS() = %v, want %v", i, got, want)
+		}
+	}
+}
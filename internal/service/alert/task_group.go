@@ -0,0 +1,229 @@
+package alert
+
+import (
+	"errors"
+	"fmt"
+	"octoops/internal/infra/postgres"
+	alertModel "octoops/internal/model/alert"
+	seatunnelModel "octoops/internal/model/seatunnel"
+	taskModel "octoops/internal/model/task"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var ErrAlertGroupInUse = errors.New("告警组仍被任务关联")
+
+// TaskGroupLink 任务关联告警组的设置，用于整体替换任务的关联
+type TaskGroupLink struct {
+	GroupID     uint   `json:"group_id"`
+	MinSeverity string `json:"min_severity"`
+	NotifyOn    string `json:"notify_on"`
+}
+
+// ListTaskGroups 任务与告警组的关联，source 为空、taskID、groupID 为 0 时不按该字段筛选
+func ListTaskGroups(source string, taskID, groupID uint) ([]alertModel.AlertTaskGroup, error) {
+	var links []alertModel.AlertTaskGroup
+	db := postgres.DB.Order("source, task_id, id")
+	if source != "" {
+		db = db.Where("source = ?", source)
+	}
+	if taskID > 0 {
+		db = db.Where("task_id = ?", taskID)
+	}
+	if groupID > 0 {
+		db = db.Where("group_id = ?", groupID)
+	}
+	err := db.Find(&links).Error
+	return links, err
+}
+
+// SyncTaskGroups 按逗号分隔的告警组 ID 同步任务的关联：保留的告警组沿用原有设置，新增的使用默认设置，
+// 不存在的告警组被忽略；返回实际关联的告警组 ID，ETL 任务同时回写 EtlTask.AlertGroup
+func SyncTaskGroups(db *gorm.DB, source string, taskID uint, groups string) (string, error) {
+	links := make([]TaskGroupLink, 0)
+	for _, id := range parseIDs(groups) {
+		links = append(links, TaskGroupLink{GroupID: id})
+	}
+	var existing []alertModel.AlertTaskGroup
+	if err := db.Where("source = ? AND task_id = ?", source, taskID).Find(&existing).Error; err != nil {
+		return "", err
+	}
+	for i := range links {
+		for _, e := range existing {
+			if e.GroupID == links[i].GroupID {
+				links[i].MinSeverity, links[i].NotifyOn = e.MinSeverity, e.NotifyOn
+			}
+		}
+	}
+	return setTaskGroups(db, source, taskID, links)
+}
+
+// SetTaskGroups 以 links 整体替换任务关联的告警组及其设置
+func SetTaskGroups(source string, taskID uint, links []TaskGroupLink) ([]alertModel.AlertTaskGroup, error) {
+	var err error
+	switch source {
+	case alertModel.TaskSourceETL:
+		err = postgres.DB.Select("id").First(&seatunnelModel.EtlTask{}, taskID).Error
+	case alertModel.TaskSourceCustom:
+		err = postgres.DB.Select("id").First(&taskModel.CustomTask{}, taskID).Error
+	default:
+		return nil, fmt.Errorf("%w: source 取值为 etl/custom", ErrInvalidGroup)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i := range links {
+		if err := normalizeLink(&links[i]); err != nil {
+			return nil, err
+		}
+		var group alertModel.AlertGroup
+		if err := postgres.DB.Select("id").First(&group, links[i].GroupID).Error; err != nil {
+			return nil, fmt.Errorf("%w: 告警组 %d 不存在", ErrInvalidGroup, links[i].GroupID)
+		}
+	}
+	err = postgres.DB.Transaction(func(tx *gorm.DB) error {
+		_, err := setTaskGroups(tx, source, taskID, links)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ListTaskGroups(source, taskID, 0)
+}
+
+// SetTaskGroupsTx 在指定事务中以 links 整体替换任务关联，不存在的告警组忽略，返回同步后的告警组 ID 列表
+func SetTaskGroupsTx(tx *gorm.DB, source string, taskID uint, links []TaskGroupLink) (string, error) {
+	for i := range links {
+		if err := normalizeLink(&links[i]); err != nil {
+			return "", err
+		}
+	}
+	return setTaskGroups(tx, source, taskID, links)
+}
+
+func setTaskGroups(db *gorm.DB, source string, taskID uint, links []TaskGroupLink) (string, error) {
+	ids := make([]uint, 0, len(links))
+	for _, l := range links {
+		ids = append(ids, l.GroupID)
+	}
+	var valid []uint
+	if len(ids) > 0 {
+		if err := db.Model(&alertModel.AlertGroup{}).Where("id IN ?", ids).Pluck("id", &valid).Error; err != nil {
+			return "", err
+		}
+	}
+	exists := make(map[uint]bool, len(valid))
+	for _, id := range valid {
+		exists[id] = true
+	}
+
+	keep := make([]uint, 0, len(links))
+	parts := make([]string, 0, len(links))
+	seen := make(map[uint]bool, len(links))
+	for _, l := range links {
+		if !exists[l.GroupID] || seen[l.GroupID] {
+			continue
+		}
+		seen[l.GroupID] = true
+		if l.NotifyOn == "" {
+			l.NotifyOn = alertModel.DefaultNotifyOn
+		}
+		link := alertModel.AlertTaskGroup{Source: source, TaskID: taskID, GroupID: l.GroupID}
+		if err := db.Where(link).Assign(alertModel.AlertTaskGroup{MinSeverity: l.MinSeverity, NotifyOn: l.NotifyOn}).
+			FirstOrCreate(&link).Error; err != nil {
+			return "", err
+		}
+		keep = append(keep, l.GroupID)
+		parts = append(parts, strconv.FormatUint(uint64(l.GroupID), 10))
+	}
+	remove := db.Where("source = ? AND task_id = ?", source, taskID)
+	if len(keep) > 0 {
+		remove = remove.Where("group_id NOT IN ?", keep)
+	}
+	if err := remove.Delete(&alertModel.AlertTaskGroup{}).Error; err != nil {
+		return "", err
+	}
+	mirror := strings.Join(parts, ",")
+	if source != alertModel.TaskSourceETL {
+		return mirror, nil
+	}
+	err := db.Model(&seatunnelModel.EtlTask{}).Where("id = ?", taskID).UpdateColumn("alert_group", mirror).Error
+	return mirror, err
+}
+
+// normalizeLink 校验关联设置，通知事件去重并按 fail/recover/finish/change 排列
+func normalizeLink(link *TaskGroupLink) error {
+	switch link.MinSeverity {
+	case "", "info", "warning", "critical":
+	default:
+		return fmt.Errorf("%w: min_severity 取值为 info/warning/critical", ErrInvalidGroup)
+	}
+	events := map[string]bool{}
+	for _, e := range strings.Split(link.NotifyOn, ",") {
+		switch e = strings.TrimSpace(e); e {
+		case "":
+		case alertModel.NotifyOnFail, alertModel.NotifyOnRecover, alertModel.NotifyOnFinish, alertModel.NotifyOnChange:
+			events[e] = true
+		default:
+			return fmt.Errorf("%w: notify_on 取值为 fail/recover/finish/change", ErrInvalidGroup)
+		}
+	}
+	if link.NotifyOn != "" && len(events) == 0 {
+		return fmt.Errorf("%w: notify_on 至少包含一个事件", ErrInvalidGroup)
+	}
+	var ordered []string
+	for _, e := range []string{alertModel.NotifyOnFail, alertModel.NotifyOnRecover, alertModel.NotifyOnFinish, alertModel.NotifyOnChange} {
+		if events[e] {
+			ordered = append(ordered, e)
+		}
+	}
+	link.NotifyOn = strings.Join(ordered, ",")
+	return nil
+}
+
+// DeleteTaskGroups 删除任务的全部关联，任务删除时调用
+func DeleteTaskGroups(source string, taskID uint) error {
+	return DeleteTaskGroupsTx(postgres.DB, source, taskID)
+}
+
+// DeleteTaskGroupsTx 在指定事务中删除任务的全部关联
+func DeleteTaskGroupsTx(tx *gorm.DB, source string, taskID uint) error {
+	return tx.Where("source = ? AND task_id = ?", source, taskID).Delete(&alertModel.AlertTaskGroup{}).Error
+}
+
+// DeleteAlertGroup 删除告警组；仍有任务关联时返回 ErrAlertGroupInUse，cascade 为 true 时一并解除关联
+func DeleteAlertGroup(id string, cascade bool) error {
+	group, err := GetAlertGroupByID(id)
+	if err != nil {
+		return err
+	}
+	return postgres.DB.Transaction(func(tx *gorm.DB) error {
+		var used []alertModel.AlertTaskGroup
+		if err := tx.Where("group_id = ?", group.ID).Find(&used).Error; err != nil {
+			return err
+		}
+		if len(used) > 0 && !cascade {
+			return fmt.Errorf("%w: %d 个任务关联了告警组 %s，请先解除关联或使用 cascade=true", ErrAlertGroupInUse, len(used), group.Name)
+		}
+		if err := tx.Delete(&group).Error; err != nil {
+			return err
+		}
+		for _, u := range used {
+			var links []alertModel.AlertTaskGroup
+			if err := tx.Where("source = ? AND task_id = ? AND group_id <> ?", u.Source, u.TaskID, group.ID).
+				Order("id").Find(&links).Error; err != nil {
+				return err
+			}
+			keep := make([]TaskGroupLink, 0, len(links))
+			for _, l := range links {
+				keep = append(keep, TaskGroupLink{GroupID: l.GroupID, MinSeverity: l.MinSeverity, NotifyOn: l.NotifyOn})
+			}
+			if _, err := setTaskGroups(tx, u.Source, u.TaskID, keep); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,21 @@
+package alert
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeLink(t *testing.T) {
+	link := TaskGroupLink{MinSeverity: "warning", NotifyOn: " change,finish,fail ,finish"}
+	if err := normalizeLink(&link); err != nil {
+		t.Fatalf("normalizeLink() error = %v", err)
+	}
+	if link.NotifyOn != "fail,finish,change" {
+		t.Errorf("notify_on = %q, want fail,finish,change", link.NotifyOn)
+	}
+	for _, bad := range []TaskGroupLink{{MinSeverity: "fatal"}, {NotifyOn: "start"}, {NotifyOn: ","}} {
+		if err := normalizeLink(&bad); !errors.Is(err, ErrInvalidGroup) {
+			t.Errorf("normalizeLink(%+v) error = %v, want ErrInvalidGroup", bad, err)
+		}
+	}
+}
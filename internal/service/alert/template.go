@@ -0,0 +1,205 @@
+package alert
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	alertModel "octoops/internal/model/alert"
+	"text/template"
+	"time"
+)
+
+// 模板类型
+const (
+	TemplateKindAlert   = "alert"
+	TemplateKindDigest  = "digest"
+	TemplateKindSummary = "summary"
+)
+
+var (
+	ErrInvalidTemplate = errors.New("告警模板无效")
+	ErrTemplateInUse   = errors.New("告警模板仍被告警组引用")
+)
+
+// DefaultDigestTemplate 告警组未指定摘要模板时使用
+var DefaultDigestTemplate = alertModel.AlertTemplate{
+	Name:  "内置告警摘要",
+	Kind:  TemplateKindDigest,
+	Title: "OctoOps 告警摘要：{{ .GroupName }} {{ .Count }} 条告警",
+	Content: `### 告警摘要（{{ .Count }} 条）
+{{ if .GroupKey }}- 分组：{{ .GroupKey }}
+{{ end }}- 时间：{{ formatTime .Since "01-02 15:04" }} ~ {{ formatTime .Until "01-02 15:04" }}
+{{ range .Alerts }}
+- [{{ upper .Severity }}] **{{ .JobName }}** {{ .Status }}：{{ truncate 100 .Reason }}{{ end }}`,
+}
+
+// DefaultSummaryTemplate 告警组未指定每日汇总模板时使用
+var DefaultSummaryTemplate = alertModel.AlertTemplate{
+	Name:  "内置每日汇总",
+	Kind:  TemplateKindSummary,
+	Title: "OctoOps 每日汇总：{{ .GroupName }} {{ .Date }}",
+	Content: `### {{ .Date }} ETL 运行汇总
+- 提交 {{ .Total.Runs }} 次，完成 {{ .Total.Succeeded }} 次，失败 {{ .Total.Failed }} 次，取消 {{ .Total.Canceled }} 次，告警 {{ .Total.Alerts }} 条
+{{ range .Tasks }}
+- **{{ .JobName }}**：提交 {{ .Runs }}，完成 {{ .Succeeded }}，失败 {{ .Failed }}，告警 {{ .Alerts }}{{ end }}`,
+}
+
+// Previewer 可选接口，渠道按实际发送格式返回预览内容；未实现时按 Markdown 预览渲染后的内容
+type Previewer interface {
+	Preview(msg Message) (format, rendered string, err error)
+}
+
+// PreviewResult 模板在某一渠道类型下的预览结果
+type PreviewResult struct {
+	ChannelType string `json:"channel_type"`
+	Title       string `json:"title"`
+	Content     string `json:"content"`  // 模板渲染后的内容
+	Format      string `json:"format"`   // markdown/html/json
+	Rendered    string `json:"rendered"` // 渠道实际发送的内容，如邮件 HTML、Webhook 请求体
+	Error       string `json:"error,omitempty"`
+}
+
+// SampleTemplateData 模板校验与预览使用的示例数据
+func SampleTemplateData() *TemplateData {
+	firedAt := time.Now().Truncate(time.Second)
+	return &TemplateData{
+		AlertID:      1,
+		RuleName:     "作业失败",
+		Severity:     "critical",
+		State:        "firing",
+		Reason:       "作业状态由 RUNNING 变为 FAILED",
+		FiredAt:      firedAt,
+		TaskID:       1,
+		JobName:      "示例任务",
+		TaskType:     "stream",
+		Cluster:      "default",
+		JobID:        "1000000000000000001",
+		RunID:        "1000000000000000001",
+		Status:       "FAILED",
+		StartTime:    firedAt.Add(-30 * time.Minute).Format("2006-01-02 15:04:05"),
+		EndTime:      firedAt.Format("2006-01-02 15:04:05"),
+		ErrorMessage: "java.lang.RuntimeException: Connection refused",
+		Metrics:      map[string]interface{}{"SourceReceivedCount": 1024, "SinkWriteCount": 1000},
+		Links:        map[string]string{"task": "https://octoops.example.com/seatunnel/stream/1/edit"},
+		Labels:       map[string]string{"severity": "critical", "task_name": "示例任务", "task_type": "stream"},
+	}
+}
+
+// SampleData 返回模板类型对应的示例数据
+func SampleData(kind string) interface{} {
+	switch kind {
+	case TemplateKindDigest:
+		first, second := SampleTemplateData(), SampleTemplateData()
+		second.AlertID, second.TaskID, second.JobName = 2, 2, "示例任务2"
+		return &DigestData{
+			GroupName: "示例告警组",
+			GroupBy:   "cluster",
+			GroupKey:  "default",
+			Count:     2,
+			Since:     first.FiredAt,
+			Until:     second.FiredAt,
+			Alerts:    []*TemplateData{first, second},
+		}
+	case TemplateKindSummary:
+		task := TaskSummary{TaskID: 1, JobName: "示例任务", TaskType: "batch", Runs: 24, Succeeded: 23, Failed: 1, Alerts: 1}
+		return &SummaryData{
+			GroupName: "示例告警组",
+			Date:      time.Now().AddDate(0, 0, -1).Format("2006-01-02"),
+			Tasks:     []TaskSummary{task},
+			Total:     TaskSummary{Runs: 24, Succeeded: 23, Failed: 1, Alerts: 1},
+		}
+	default:
+		return SampleTemplateData()
+	}
+}
+
+// ValidateTemplate 解析标题、内容及各渠道变体，并以对应类型的示例数据试渲染，引用不存在的字段等错误在保存时即可发现
+func ValidateTemplate(tpl alertModel.AlertTemplate) error {
+	switch tpl.Kind {
+	case "", TemplateKindAlert, TemplateKindDigest, TemplateKindSummary:
+	default:
+		return fmt.Errorf("%w: kind 取值为 alert/digest/summary", ErrInvalidTemplate)
+	}
+	if tpl.Content == "" {
+		return fmt.Errorf("%w: content 不能为空", ErrInvalidTemplate)
+	}
+	sample := SampleData(tpl.Kind)
+	check := func(field, text string) error {
+		if text == "" {
+			return nil
+		}
+		if err := executeSample(text, sample); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidTemplate, field, err)
+		}
+		return nil
+	}
+	if err := check("title", tpl.Title); err != nil {
+		return err
+	}
+	if err := check("content", tpl.Content); err != nil {
+		return err
+	}
+	for channelType, v := range tpl.Variants {
+		if _, ok := GetNotifier(channelType); !ok {
+			return fmt.Errorf("%w: 未知渠道类型 %s", ErrInvalidTemplate, channelType)
+		}
+		if err := check("variants."+channelType+".title", v.Title); err != nil {
+			return err
+		}
+		if err := check("variants."+channelType+".content", v.Content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func executeSample(text string, data interface{}) error {
+	t, err := template.New("msg").Funcs(TemplateFuncs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	return t.Execute(&buf, data)
+}
+
+// Preview 以给定数据渲染模板在各渠道类型下的实际发送内容，channelTypes 为空时预览全部渠道类型，data 为空时使用示例数据
+func Preview(tpl alertModel.AlertTemplate, channelTypes []string, data interface{}) []PreviewResult {
+	if len(channelTypes) == 0 {
+		channelTypes = NotifierTypes()
+	}
+	if data == nil {
+		data = SampleData(tpl.Kind)
+	}
+	results := make([]PreviewResult, 0, len(channelTypes))
+	for _, channelType := range channelTypes {
+		results = append(results, previewChannel(tpl, channelType, data))
+	}
+	return results
+}
+
+func previewChannel(tpl alertModel.AlertTemplate, channelType string, data interface{}) PreviewResult {
+	result := PreviewResult{ChannelType: channelType, Format: "markdown"}
+	n, ok := GetNotifier(channelType)
+	if !ok {
+		result.Error = "未知渠道类型: " + channelType
+		return result
+	}
+	msg, err := renderMessage(tpl, channelType, data)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Title = msg.Title
+	result.Content = msg.Content
+	result.Rendered = msg.Content
+	if p, ok := n.(Previewer); ok {
+		format, rendered, err := p.Preview(msg)
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+		result.Format = format
+		result.Rendered = rendered
+	}
+	return result
+}
@@ -0,0 +1,138 @@
+package alert
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// TemplateData 告警模板数据，模板中以 {{ .字段名 }} 引用，如 {{ .JobName }}、{{ .Labels.severity }}、{{ .Links.task }}。
+// JobID、JobName、Status、StartTime、EndTime、TaskType 与早期模板保持兼容
+type TemplateData struct {
+	AlertID         uint      `json:"alert_id"`         // 告警记录 ID
+	RuleName        string    `json:"rule_name"`        // 告警规则名称
+	Severity        string    `json:"severity"`         // 告警级别：info/warning/critical
+	State           string    `json:"state"`            // firing 为告警通知，resolved 为恢复通知
+	Reason          string    `json:"reason"`           // 告警原因
+	EscalationLevel int       `json:"escalation_level"` // 升级步骤，首次通知为 0
+	FiredAt         time.Time `json:"fired_at"`         // 告警触发时间
+
+	TaskID       uint   `json:"task_id"`
+	JobName      string `json:"job_name"`  // 任务名称；调度器告警为“调度器”
+	TaskType     string `json:"task_type"` // stream/batch
+	Cluster      string `json:"cluster"`   // SeaTunnel 集群，为空表示默认集群
+	JobID        string `json:"job_id"`    // SeaTunnel 作业 ID
+	RunID        string `json:"run_id"`    // 本次运行标识，ETL 任务每次提交生成新的作业 ID，故与 JobID 相同
+	Status       string `json:"status"`    // 告警时的作业状态
+	StartTime    string `json:"start_time"`
+	EndTime      string `json:"end_time"`
+	ErrorMessage string `json:"error_message"` // SeaTunnel 返回的作业错误信息
+
+	Metrics map[string]interface{} `json:"metrics"` // 作业指标，如 SourceReceivedCount、SinkWriteCount
+	Links   map[string]string      `json:"links"`   // 相关链接：task（任务页面，需配置 external_url）、job（SeaTunnel 作业详情接口）
+	Labels  map[string]string      `json:"labels"`  // 告警标签，与静默匹配字段一致
+}
+
+// TemplateFuncs 模板可用的辅助函数：
+//
+//	formatTime  {{ formatTime .FiredAt "01-02 15:04" }}，省略格式时为 2006-01-02 15:04:05
+//	truncate    {{ truncate 100 .ErrorMessage }}，按字符截断并追加省略号
+//	default     {{ default "默认集群" .Cluster }}，值为空时使用默认值
+//	upper/lower 大小写转换
+//	join        {{ join "," .Values }}
+//	json        转为 JSON 字符串，用于拼接 JSON 请求体
+var TemplateFuncs = template.FuncMap{
+	"formatTime": formatTime,
+	"truncate":   truncate,
+	"default":    defaultValue,
+	"upper":      strings.ToUpper,
+	"lower":      strings.ToLower,
+	"join":       func(sep string, elems []string) string { return strings.Join(elems, sep) },
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+func formatTime(v interface{}, layout ...string) string {
+	format := "2006-01-02 15:04:05"
+	if len(layout) > 0 && layout[0] != "" {
+		format = layout[0]
+	}
+	switch t := v.(type) {
+	case time.Time:
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(format)
+	case *time.Time:
+		if t == nil || t.IsZero() {
+			return ""
+		}
+		return t.Format(format)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func truncate(n int, s string) string {
+	runes := []rune(s)
+	if n < 0 || len(runes) <= n {
+		return s
+	}
+	return string(runes[:n]) + "…"
+}
+
+func defaultValue(def, v interface{}) interface{} {
+	if v == nil {
+		return def
+	}
+	rv := reflect.ValueOf(v)
+	if rv.IsZero() {
+		return def
+	}
+	switch rv.Kind() {
+	case reflect.Map, reflect.Slice:
+		if rv.Len() == 0 {
+			return def
+		}
+	}
+	return v
+}
+
+// DigestData 告警摘要模板数据，窗口内同一分组的告警合并为一条消息，如 {{ range .Alerts }}{{ .JobName }}{{ end }}
+type DigestData struct {
+	GroupName string          `json:"group_name"` // 告警组名称
+	GroupBy   string          `json:"group_by"`   // 分组方式：cluster/status，为空表示不分组
+	GroupKey  string          `json:"group_key"`  // 分组值，如集群名称或作业状态
+	Count     int             `json:"count"`      // 告警条数
+	Since     time.Time       `json:"since"`      // 窗口内第一条告警的时间
+	Until     time.Time       `json:"until"`      // 窗口内最后一条告警的时间
+	Alerts    []*TemplateData `json:"alerts"`     // 各条告警的模板数据，字段同单条告警模板
+}
+
+// SummaryData 每日汇总模板数据，统计告警组关联任务前一天的运行情况
+type SummaryData struct {
+	GroupName string        `json:"group_name"`
+	Date      string        `json:"date"` // 统计日期 YYYY-MM-DD
+	Tasks     []TaskSummary `json:"tasks"`
+	Total     TaskSummary   `json:"total"` // 全部任务合计，JobName 为空
+}
+
+// TaskSummary 单个任务当天的运行统计
+type TaskSummary struct {
+	TaskID    uint   `json:"task_id"`
+	JobName   string `json:"job_name"`
+	TaskType  string `json:"task_type"`
+	Cluster   string `json:"cluster"`
+	Runs      int    `json:"runs"`      // 提交次数
+	Succeeded int    `json:"succeeded"` // 作业完成次数
+	Failed    int    `json:"failed"`    // 作业失败及提交失败次数
+	Canceled  int    `json:"canceled"`
+	Alerts    int    `json:"alerts"` // 当天触发并通知的告警数
+}
@@ -0,0 +1,85 @@
+package alert
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	alertModel "octoops/internal/model/alert"
+)
+
+func TestValidateTemplate(t *testing.T) {
+	cases := []struct {
+		name  string
+		tpl   alertModel.AlertTemplate
+		field string
+	}{
+		{"ok", alertModel.AlertTemplate{Title: "[{{ upper .Severity }}] {{ .JobName }}", Content: "{{ .Reason }} {{ formatTime .FiredAt }}"}, ""},
+		{"empty content", alertModel.AlertTemplate{}, "content"},
+		{"unclosed action", alertModel.AlertTemplate{Content: "作业 {{ .JobName"}, "content"},
+		{"unknown field", alertModel.AlertTemplate{Title: "{{ .Job }}", Content: "x"}, "title"},
+		{"variant", alertModel.AlertTemplate{Content: "x", Variants: map[string]alertModel.TemplateVariant{
+			"email": {Content: "{{ range .Labels }}"},
+		}}, "variants.email.content"},
+		{"unknown channel type", alertModel.AlertTemplate{Content: "x", Variants: map[string]alertModel.TemplateVariant{
+			"pager": {Content: "x"},
+		}}, "pager"},
+	}
+	for _, c := range cases {
+		err := ValidateTemplate(c.tpl)
+		if c.field == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", c.name, err)
+			}
+			continue
+		}
+		if !errors.Is(err, ErrInvalidTemplate) || !strings.Contains(err.Error(), c.field) {
+			t.Errorf("%s: got %v, want ErrInvalidTemplate mentioning %s", c.name, err, c.field)
+		}
+	}
+}
+
+func TestPreview(t *testing.T) {
+	tpl := alertModel.AlertTemplate{
+		Content: "**{{ .JobName }}** {{ .Status }}",
+		Variants: map[string]alertModel.TemplateVariant{
+			"dingtalk": {Title: "钉钉 {{ .JobName }}", Content: "### {{ .JobName }}"},
+		},
+	}
+	results := Preview(tpl, []string{"email", "dingtalk", "webhook", "pager"}, nil)
+	if len(results) != 4 {
+		t.Fatalf("got %d results, want 4", len(results))
+	}
+	email, ding, webhook, unknown := results[0], results[1], results[2], results[3]
+	if email.Format != "html" || !strings.Contains(email.Rendered, "<strong>示例任务</strong>") || email.Title != defaultTitle {
+		t.Errorf("email preview = %+v", email)
+	}
+	if ding.Format != "markdown" || ding.Rendered != "### 示例任务" || ding.Title != "钉钉 示例任务" {
+		t.Errorf("dingtalk preview = %+v", ding)
+	}
+	if webhook.Format != "json" || !strings.Contains(webhook.Rendered, `"job_name": "示例任务"`) {
+		t.Errorf("webhook preview = %+v", webhook)
+	}
+	if unknown.Error == "" {
+		t.Errorf("unknown channel type should report an error")
+	}
+}
+
+func TestDigestTemplates(t *testing.T) {
+	for _, tpl := range []alertModel.AlertTemplate{DefaultDigestTemplate, DefaultSummaryTemplate} {
+		if err := ValidateTemplate(tpl); err != nil {
+			t.Errorf("%s: %v", tpl.Name, err)
+		}
+	}
+	// 摘要模板引用的 .Alerts 在单条告警数据中不存在
+	alertKind := DefaultDigestTemplate
+	alertKind.Kind = TemplateKindAlert
+	if err := ValidateTemplate(alertKind); !errors.Is(err, ErrInvalidTemplate) {
+		t.Errorf("digest content as alert template: got %v, want ErrInvalidTemplate", err)
+	}
+
+	results := Preview(DefaultDigestTemplate, []string{"dingtalk"}, nil)
+	if results[0].Error != "" || !strings.Contains(results[0].Content, "**示例任务2**") || !strings.Contains(results[0].Title, "2 条告警") {
+		t.Errorf("digest preview = %+v", results[0])
+	}
+}
@@ -0,0 +1,97 @@
+package alert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	alertModel "octoops/internal/model/alert"
+	"text/template"
+)
+
+// Slack Incoming Webhook，mrkdwn 文本
+type slackNotifier struct{}
+
+func (slackNotifier) Send(channel *alertModel.AlertChannel, msg Message) error {
+	_, err := postJSON(channel.Target, map[string]interface{}{
+		"text": "*" + msg.Title + "*\n" + appendMentions(msg.Content, msg.Mentions, func(m string) string { return "<@" + m + ">" }),
+	}, nil)
+	return err
+}
+
+func (slackNotifier) SupportsMention() bool { return true }
+
+// Microsoft Teams Incoming Webhook，MessageCard 格式
+type teamsNotifier struct{}
+
+func (teamsNotifier) Send(channel *alertModel.AlertChannel, msg Message) error {
+	_, err := postJSON(channel.Target, map[string]interface{}{
+		"@type":    "MessageCard",
+		"@context": "https://schema.org/extensions",
+		"summary":  msg.Title,
+		"title":    msg.Title,
+		"text":     msg.Content,
+	}, nil)
+	return err
+}
+
+// WebhookConfig 通用 Webhook 渠道配置，保存在渠道的 config 字段
+type WebhookConfig struct {
+	// BodyTemplate 请求体模板，可引用 .Title、.Content 及告警模板数据 .Data.xxx，支持 TemplateFuncs 中的函数；
+	// 字符串需经 json 函数转义，如 {"text": {{ json .Content }}}；为空时发送默认结构
+	BodyTemplate string            `json:"body_template"`
+	Headers      map[string]string `json:"headers"`
+}
+
+// 通用 JSON Webhook
+type webhookNotifier struct{}
+
+func (webhookNotifier) Send(channel *alertModel.AlertChannel, msg Message) error {
+	var cfg WebhookConfig
+	if channel.Config != "" {
+		if err := json.Unmarshal([]byte(channel.Config), &cfg); err != nil {
+			return fmt.Errorf("Webhook 渠道配置无效: %v", err)
+		}
+	}
+	var body []byte
+	var err error
+	if cfg.BodyTemplate == "" {
+		body, err = json.Marshal(map[string]interface{}{
+			"title":   msg.Title,
+			"content": msg.Content,
+			"data":    msg.Data,
+		})
+	} else {
+		body, err = renderBody(cfg.BodyTemplate, msg)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = postJSON(channel.Target, body, cfg.Headers)
+	return err
+}
+
+// Preview 预览默认结构的请求体，渠道自定义的请求体模板需通过测试发送验证
+func (webhookNotifier) Preview(msg Message) (string, string, error) {
+	body, err := json.MarshalIndent(map[string]interface{}{
+		"title":   msg.Title,
+		"content": msg.Content,
+		"data":    msg.Data,
+	}, "", "  ")
+	return "json", string(body), err
+}
+
+// renderBody 渲染 JSON 请求体模板，渲染结果需为合法 JSON
+func renderBody(bodyTemplate string, data interface{}) ([]byte, error) {
+	tpl, err := template.New("body").Funcs(TemplateFuncs).Parse(bodyTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("请求体模板解析失败: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("请求体模板渲染失败: %v", err)
+	}
+	if !json.Valid(buf.Bytes()) {
+		return nil, fmt.Errorf("请求体模板渲染结果不是合法 JSON")
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,123 @@
+package alerting
+
+import (
+	"fmt"
+	"octoops/internal/event"
+	"octoops/internal/infra/postgres"
+	alertModel "octoops/internal/model/alert"
+	taskModel "octoops/internal/model/task"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 内置告警条件，无需配置告警规则，按自定义任务关联的告警组发送
+const (
+	ConditionCustomTaskFailed = "custom_task_failed"
+	ConditionSGRuleChanged    = "sg_rule_changed"
+)
+
+// sgSyncTaskType 阿里云安全组同步的自定义任务类型，安全组变更通知发送给该类任务关联的告警组
+const sgSyncTaskType = "ecs_sg_sync"
+
+var (
+	customTaskFailedRule = alertModel.AlertRule{Name: "自定义任务执行失败", ConditionType: ConditionCustomTaskFailed, Severity: SeverityCritical}
+	sgRuleChangedRule    = alertModel.AlertRule{Name: "安全组授权变更", ConditionType: ConditionSGRuleChanged, Severity: SeverityInfo}
+)
+
+// onCustomTaskRun 自定义任务执行失败（含重试）时告警，未恢复前的再次失败不重复告警；执行成功时恢复该任务的失败告警
+func onCustomTaskRun(ev event.TaskRunFinished) error {
+	if ev.Source != event.SourceCustom {
+		return nil
+	}
+	var open []alertModel.AlertEvent
+	if err := postgres.DB.Where("source = ? AND task_id = ? AND condition_type = ? AND state <> ?",
+		alertModel.TaskSourceCustom, ev.TaskID, ConditionCustomTaskFailed, StateResolved).Find(&open).Error; err != nil {
+		return err
+	}
+	if ev.Status != "failed" {
+		for _, record := range open {
+			resolve(record, "auto", "任务执行成功")
+		}
+		return nil
+	}
+	if len(open) > 0 {
+		return nil
+	}
+	reason := ev.Result
+	if ev.Attempts > 1 {
+		reason += fmt.Sprintf("（已重试 %d 次）", ev.Attempts-1)
+	}
+	taskID := ev.TaskID
+	rule := customTaskFailedRule
+	record := alertModel.AlertEvent{
+		RuleName:      rule.Name,
+		ConditionType: rule.ConditionType,
+		Severity:      rule.Severity,
+		Source:        alertModel.TaskSourceCustom,
+		TaskID:        &taskID,
+		TaskName:      ev.TaskName,
+		TaskType:      ev.TaskType,
+		Status:        ev.Status,
+		Reason:        reason,
+		DedupKey:      fmt.Sprintf("custom:%d:failed:%d", taskID, ev.At.UnixNano()),
+		Fingerprint:   fmt.Sprintf("custom:%d:failed", taskID),
+		AlertGroups:   taskAlertGroups(alertModel.TaskSourceCustom, taskID, rule.Severity, alertModel.NotifyOnFail),
+		State:         StateFiring,
+		CreatedAt:     time.Now(),
+	}
+	return fireRecord(rule, record, nil)
+}
+
+// onSGRuleChanged 安全组授权 IP 变更后通知安全组同步任务关联的告警组，记录为已恢复的变更通知
+func onSGRuleChanged(ev event.SGRuleChanged) error {
+	var taskIDs []uint
+	if err := postgres.DB.Model(&taskModel.CustomTask{}).Where("custom_type = ?", sgSyncTaskType).
+		Order("id").Pluck("id", &taskIDs).Error; err != nil {
+		return err
+	}
+	rule := sgRuleChangedRule
+	var groups []string
+	for _, id := range taskIDs {
+		if g := taskAlertGroups(alertModel.TaskSourceCustom, id, rule.Severity, alertModel.NotifyOnChange); g != "" {
+			groups = append(groups, g)
+		}
+	}
+	now := time.Now()
+	record := alertModel.AlertEvent{
+		RuleName:      rule.Name,
+		ConditionType: rule.ConditionType,
+		Severity:      rule.Severity,
+		Source:        alertModel.TaskSourceCustom,
+		TaskName:      ev.ConfigName,
+		TaskType:      sgSyncTaskType,
+		Status:        "changed",
+		Reason:        sgChangeReason(ev),
+		DedupKey:      fmt.Sprintf("sg:%d:%s:%d", ev.ConfigID, ev.NewIP, ev.At.UnixNano()),
+		Fingerprint:   fmt.Sprintf("sg:%d", ev.ConfigID),
+		AlertGroups:   strings.Join(dedupe(strings.Split(strings.Join(groups, ","), ",")), ","),
+		State:         StateResolved,
+		ResolvedAt:    &now,
+		ResolvedBy:    "auto",
+		CreatedAt:     now,
+	}
+	return fireRecord(rule, record, nil)
+}
+
+// sgChangeReason 描述安全组变更及实际执行的授权规则
+func sgChangeReason(ev event.SGRuleChanged) string {
+	ports := make([]string, 0, len(ev.Ports))
+	for _, p := range ev.Ports {
+		ports = append(ports, strconv.Itoa(p))
+	}
+	oldIP := ev.OldIP
+	if oldIP == "" {
+		oldIP = "（无）"
+	}
+	reason := fmt.Sprintf("安全组 %s（%s/%s）授权 IP 由 %s 变为 %s，已授权 TCP 端口 %s",
+		ev.ConfigName, ev.RegionID, ev.SecurityGroupID, oldIP, ev.NewIP, strings.Join(ports, ","))
+	if len(ev.Revoked) > 0 {
+		reason += "，已撤销旧 IP 的端口范围 " + strings.Join(ev.Revoked, ",")
+	}
+	return reason
+}
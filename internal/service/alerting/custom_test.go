@@ -0,0 +1,28 @@
+package alerting
+
+import (
+	"testing"
+
+	"octoops/internal/event"
+)
+
+func TestSGChangeReason(t *testing.T) {
+	ev := event.SGRuleChanged{
+		ConfigName:      "办公网",
+		RegionID:        "cn-hangzhou",
+		SecurityGroupID: "sg-123",
+		OldIP:           "1.1.1.1",
+		NewIP:           "2.2.2.2",
+		Ports:           []int{22, 3306},
+		Revoked:         []string{"22/22", "3306/3306"},
+	}
+	want := "安全组 办公网（cn-hangzhou/sg-123）授权 IP 由 1.1.1.1 变为 2.2.2.2，已授权 TCP 端口 22,3306，已撤销旧 IP 的端口范围 22/22,3306/3306"
+	if got := sgChangeReason(ev); got != want {
+		t.Errorf("sgChangeReason() = %q, want %q", got, want)
+	}
+	ev.OldIP, ev.Revoked = "", nil
+	want = "安全组 办公网（cn-hangzhou/sg-123）授权 IP 由 （无） 变为 2.2.2.2，已授权 TCP 端口 22,3306"
+	if got := sgChangeReason(ev); got != want {
+		t.Errorf("sgChangeReason() = %q, want %q", got, want)
+	}
+}
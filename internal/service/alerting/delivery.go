@@ -0,0 +1,306 @@
+package alerting
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"octoops/internal/infra/postgres"
+	alertModel "octoops/internal/model/alert"
+	alertService "octoops/internal/service/alert"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 发送记录状态
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryDead      = "dead"
+)
+
+const (
+	deliveryWorkers      = 4
+	deliveryPollInterval = 2 * time.Second
+	deliveryBatchSize    = 10
+	// 单条发送记录认领后的租约时间，实例崩溃或发送超时时租约过期由其他实例接手
+	deliveryLease = 2 * time.Minute
+
+	defaultMaxAttempts   = 5
+	defaultRetryInterval = 30 * time.Second
+	maxRetryInterval     = time.Hour
+)
+
+var ErrDeliveryNotRetryable = errors.New("只能重试已进入死信的发送记录")
+
+var (
+	deliveryWake = make(chan struct{}, deliveryWorkers)
+	deliveryOnce sync.Once
+)
+
+// DeliveryFilter 发送记录查询条件，为空的字段不参与筛选
+type DeliveryFilter struct {
+	EventID   uint
+	DigestID  uint
+	ChannelID uint
+	Kind      string
+	Status    string
+}
+
+// enqueue 按 base 为每个发送目标写入一条待发送记录，模板数据在入队时固化，重试时内容不变
+func enqueue(base alertModel.AlertDelivery, targets []deliveryTarget, data interface{}) int {
+	if len(targets) == 0 {
+		return 0
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("[Alerting] 序列化告警数据失败: event=%d, kind=%s, error=%v", base.EventID, base.Kind, err)
+		return 0
+	}
+	now := time.Now()
+	deliveries := make([]alertModel.AlertDelivery, 0, len(targets))
+	for _, t := range targets {
+		d := base
+		d.ChannelID = t.Channel.ID
+		d.ChannelName = t.Channel.Name
+		d.ChannelType = t.Channel.Type
+		d.Recipient = strings.Join(t.Recipients, ",")
+		d.Status = DeliveryPending
+		d.Payload = string(payload)
+		d.NextRetryAt = now
+		deliveries = append(deliveries, d)
+	}
+	if err := postgres.DB.Create(&deliveries).Error; err != nil {
+		log.Printf("[Alerting] 写入告警发送队列失败: event=%d, kind=%s, error=%v", base.EventID, base.Kind, err)
+		return 0
+	}
+	wakeDeliveryWorkers()
+	return len(deliveries)
+}
+
+// decodePayload 按发送类型解析模板数据快照
+func decodePayload(d alertModel.AlertDelivery) (interface{}, error) {
+	var data interface{}
+	switch d.Kind {
+	case KindDigest:
+		data = &alertService.DigestData{}
+	case KindSummary:
+		data = &alertService.SummaryData{}
+	default:
+		data = &alertService.TemplateData{}
+	}
+	err := json.Unmarshal([]byte(d.Payload), data)
+	return data, err
+}
+
+func wakeDeliveryWorkers() {
+	for i := 0; i < deliveryWorkers; i++ {
+		select {
+		case deliveryWake <- struct{}{}:
+		default:
+			return
+		}
+	}
+}
+
+// startDeliveryWorkers 启动发送协程，多副本部署时通过 SKIP LOCKED 认领发送记录，
+// 单个渠道响应慢只占用一个协程，不影响状态同步和其他渠道
+func startDeliveryWorkers() {
+	deliveryOnce.Do(func() {
+		for i := 0; i < deliveryWorkers; i++ {
+			go func() {
+				ticker := time.NewTicker(deliveryPollInterval)
+				defer ticker.Stop()
+				for {
+					for deliverBatch() {
+					}
+					select {
+					case <-ticker.C:
+					case <-deliveryWake:
+					}
+				}
+			}()
+		}
+	})
+}
+
+// deliverBatch 逐条认领并发送到期记录，返回是否处理满一批（可能还有剩余）
+func deliverBatch() bool {
+	for i := 0; i < deliveryBatchSize; i++ {
+		d, err := claimDelivery()
+		if err != nil {
+			log.Printf("[Alerting] 认领告警发送记录失败: %v", err)
+			return false
+		}
+		if d == nil {
+			return false
+		}
+		deliverOne(*d)
+	}
+	return true
+}
+
+// claimDelivery 在短事务中认领一条到期记录：递增 attempts 并将 next_retry_at 推后一个租约，没有到期记录时返回 nil
+func claimDelivery() (*alertModel.AlertDelivery, error) {
+	var d alertModel.AlertDelivery
+	found := false
+	err := postgres.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_retry_at <= ?", DeliveryPending, time.Now()).
+			Order("id").Limit(1).Find(&d)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		found = true
+		d.Attempts++
+		return tx.Model(&alertModel.AlertDelivery{}).Where("id = ?", d.ID).Updates(map[string]interface{}{
+			"attempts":      d.Attempts,
+			"next_retry_at": time.Now().Add(deliveryLease),
+		}).Error
+	})
+	if err != nil || !found {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// deliverOne 发送单条已认领的记录并回写结果，attempts 已在认领时递增；
+// 失败时按渠道的重试配置退避，次数耗尽进入死信
+func deliverOne(d alertModel.AlertDelivery) {
+	attempts := d.Attempts
+	updates := map[string]interface{}{}
+
+	data, err := decodePayload(d)
+	if err != nil {
+		updates["status"] = DeliveryDead
+		updates["error"] = "告警数据无法解析: " + err.Error()
+		saveDeliveryResult(d, updates)
+		return
+	}
+	alertData, _ := data.(*alertService.TemplateData)
+	var channel alertModel.AlertChannel
+	if err := postgres.DB.First(&channel, d.ChannelID).Error; err != nil || channel.Status != 1 {
+		updates["status"] = DeliveryDead
+		updates["error"] = "渠道不存在或已禁用"
+		saveDeliveryResult(d, updates)
+		if alertData != nil {
+			publishAlertEvent(alertData, alertModel.AlertChannel{Name: d.ChannelName}, errors.New("渠道不存在或已禁用"))
+		}
+		return
+	}
+
+	err = sendChannel(channel, d, data)
+	if err == nil {
+		updates["status"] = DeliverySucceeded
+		updates["error"] = ""
+		updates["delivered_at"] = time.Now()
+		if alertData != nil {
+			publishAlertEvent(alertData, channel, nil)
+		}
+	} else {
+		message := err.Error()
+		if runes := []rune(message); len(runes) > 500 {
+			message = string(runes[:500])
+		}
+		updates["error"] = message
+		if attempts >= channelMaxAttempts(channel) {
+			updates["status"] = DeliveryDead
+			log.Printf("[ALERT] 告警发送失败且重试次数耗尽: delivery=%d, event=%d, channel=%s, error=%v", d.ID, d.EventID, channel.Name, err)
+			if alertData != nil {
+				publishAlertEvent(alertData, channel, err)
+			}
+		} else {
+			updates["next_retry_at"] = time.Now().Add(retryBackoff(channel, attempts))
+			log.Printf("[ALERT] 告警发送失败，稍后重试: delivery=%d, event=%d, channel=%s, attempts=%d, error=%v", d.ID, d.EventID, channel.Name, attempts, err)
+		}
+	}
+	saveDeliveryResult(d, updates)
+}
+
+// saveDeliveryResult 回写发送结果；租约过期后被其他实例重新认领时 attempts 已变化，放弃回写本次结果
+func saveDeliveryResult(d alertModel.AlertDelivery, updates map[string]interface{}) {
+	result := postgres.DB.Model(&alertModel.AlertDelivery{}).
+		Where("id = ? AND status = ? AND attempts = ?", d.ID, DeliveryPending, d.Attempts).Updates(updates)
+	if result.Error != nil {
+		log.Printf("[Alerting] 更新告警发送记录失败: delivery=%d, error=%v", d.ID, result.Error)
+	} else if result.RowsAffected == 0 {
+		log.Printf("[Alerting] 发送记录租约已过期并被重新认领，忽略本次结果: delivery=%d", d.ID)
+	}
+}
+
+func channelMaxAttempts(channel alertModel.AlertChannel) int {
+	if channel.MaxAttempts > 0 {
+		return channel.MaxAttempts
+	}
+	return defaultMaxAttempts
+}
+
+// retryBackoff 指数退避，从渠道配置的重试间隔（默认 30 秒）起翻倍，最长 1 小时
+func retryBackoff(channel alertModel.AlertChannel, attempts int) time.Duration {
+	base := defaultRetryInterval
+	if channel.RetryInterval > 0 {
+		base = time.Duration(channel.RetryInterval) * time.Second
+	}
+	d := base
+	for i := 1; i < attempts && d < maxRetryInterval; i++ {
+		d *= 2
+	}
+	if d > maxRetryInterval {
+		return maxRetryInterval
+	}
+	return d
+}
+
+// ListAllDeliveries 告警发送记录，可按告警、渠道、类型和状态筛选
+func ListAllDeliveries(filter DeliveryFilter, page, pageSize int) ([]alertModel.AlertDelivery, int64, error) {
+	var deliveries []alertModel.AlertDelivery
+	var total int64
+	db := postgres.DB.Model(&alertModel.AlertDelivery{})
+	if filter.EventID > 0 {
+		db = db.Where("event_id = ?", filter.EventID)
+	}
+	if filter.DigestID > 0 {
+		db = db.Where("digest_id = ?", filter.DigestID)
+	}
+	if filter.ChannelID > 0 {
+		db = db.Where("channel_id = ?", filter.ChannelID)
+	}
+	if filter.Kind != "" {
+		db = db.Where("kind = ?", filter.Kind)
+	}
+	if filter.Status != "" {
+		db = db.Where("status = ?", filter.Status)
+	}
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := db.Order("id desc").Offset((page - 1) * pageSize).Limit(pageSize).Find(&deliveries).Error
+	return deliveries, total, err
+}
+
+// RetryDelivery 将死信记录重新放回队列，按原内容发送并重新计算重试次数
+func RetryDelivery(id interface{}) (alertModel.AlertDelivery, error) {
+	var d alertModel.AlertDelivery
+	if err := postgres.DB.First(&d, id).Error; err != nil {
+		return d, err
+	}
+	result := postgres.DB.Model(&alertModel.AlertDelivery{}).
+		Where("id = ? AND status = ? AND payload <> ''", d.ID, DeliveryDead).
+		Updates(map[string]interface{}{
+			"status":        DeliveryPending,
+			"attempts":      0,
+			"next_retry_at": time.Now(),
+		})
+	if result.Error != nil {
+		return d, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return d, ErrDeliveryNotRetryable
+	}
+	wakeDeliveryWorkers()
+	err := postgres.DB.First(&d, d.ID).Error
+	return d, err
+}
@@ -0,0 +1,37 @@
+package alerting
+
+import (
+	"testing"
+	"time"
+
+	alertModel "octoops/internal/model/alert"
+)
+
+func TestRetryBackoff(t *testing.T) {
+	cases := []struct {
+		channel  alertModel.AlertChannel
+		attempts int
+		want     time.Duration
+	}{
+		{alertModel.AlertChannel{}, 1, 30 * time.Second},
+		{alertModel.AlertChannel{}, 3, 2 * time.Minute},
+		{alertModel.AlertChannel{RetryInterval: 10}, 1, 10 * time.Second},
+		{alertModel.AlertChannel{RetryInterval: 10}, 4, 80 * time.Second},
+		{alertModel.AlertChannel{}, 100, time.Hour},
+		{alertModel.AlertChannel{RetryInterval: 7200}, 1, time.Hour},
+	}
+	for _, c := range cases {
+		if got := retryBackoff(c.channel, c.attempts); got != c.want {
+			t.Errorf("retryBackoff(%d, %d) = %v, want %v", c.channel.RetryInterval, c.attempts, got, c.want)
+		}
+	}
+}
+
+func TestChannelMaxAttempts(t *testing.T) {
+	if got := channelMaxAttempts(alertModel.AlertChannel{}); got != defaultMaxAttempts {
+		t.Errorf("default max attempts = %d, want %d", got, defaultMaxAttempts)
+	}
+	if got := channelMaxAttempts(alertModel.AlertChannel{MaxAttempts: 1}); got != 1 {
+		t.Errorf("max attempts = %d, want 1", got)
+	}
+}
@@ -0,0 +1,359 @@
+package alerting
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"octoops/internal/infra/postgres"
+	alertModel "octoops/internal/model/alert"
+	seatunnelModel "octoops/internal/model/seatunnel"
+	alertService "octoops/internal/service/alert"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// collectDigests 将告警暂存到开启摘要的告警组，返回仍需逐条通知的告警组
+func collectDigests(record alertModel.AlertEvent, groups string, data *alertService.TemplateData) string {
+	ids := splitGroupIDs(groups)
+	if len(ids) == 0 {
+		return groups
+	}
+	var digestGroups []alertModel.AlertGroup
+	if err := postgres.DB.Where("id IN ? AND status = ? AND digest_window > 0", ids, 1).Find(&digestGroups).Error; err != nil || len(digestGroups) == 0 {
+		return groups
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return groups
+	}
+	digested := make(map[uint]bool, len(digestGroups))
+	for _, g := range digestGroups {
+		item := alertModel.AlertDigestItem{
+			GroupID:  g.ID,
+			EventID:  record.ID,
+			GroupKey: digestKey(g.DigestGroupBy, data),
+			Payload:  string(payload),
+		}
+		if err := postgres.DB.Create(&item).Error; err != nil {
+			log.Printf("[Alerting] 暂存告警摘要失败，改为直接通知: event=%d, group=%d, error=%v", record.ID, g.ID, err)
+			continue
+		}
+		digested[g.ID] = true
+	}
+	var rest []string
+	for _, id := range ids {
+		if !digested[id] {
+			rest = append(rest, strconv.FormatUint(uint64(id), 10))
+		}
+	}
+	return strings.Join(rest, ",")
+}
+
+func splitGroupIDs(groups string) []uint {
+	var ids []uint
+	for _, part := range strings.Split(groups, ",") {
+		if id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 64); err == nil {
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids
+}
+
+// digestKey 摘要分组值，集群为空表示默认集群
+func digestKey(groupBy string, data *alertService.TemplateData) string {
+	switch groupBy {
+	case "cluster":
+		if data.Cluster == "" {
+			return "default"
+		}
+		return data.Cluster
+	case "status":
+		return data.Status
+	default:
+		return ""
+	}
+}
+
+type pendingDigest struct {
+	digest alertModel.AlertDigest
+	group  alertModel.AlertGroup
+	data   *alertService.DigestData
+}
+
+// flushDigests 发送窗口已结束的摘要：同一告警组同一分组值的告警自第一条起满 DigestWindow 分钟后合并为一条，
+// 已恢复的告警不再计入；多副本下通过 SKIP LOCKED 保证每条告警只进入一个摘要
+func flushDigests(now time.Time) {
+	var groupIDs []uint
+	if err := postgres.DB.Model(&alertModel.AlertDigestItem{}).Where("digest_id IS NULL").
+		Distinct().Pluck("group_id", &groupIDs).Error; err != nil {
+		log.Printf("[Alerting] 查询待发送告警摘要失败: %v", err)
+		return
+	}
+	for _, groupID := range groupIDs {
+		pending, err := claimDigests(groupID, now)
+		if err != nil {
+			log.Printf("[Alerting] 生成告警摘要失败: group=%d, error=%v", groupID, err)
+			continue
+		}
+		for _, p := range pending {
+			log.Printf("[Alerting] 发送告警摘要: group=%s, key=%s, count=%d", p.group.Name, p.digest.GroupKey, p.digest.Count)
+			enqueue(alertModel.AlertDelivery{DigestID: &p.digest.ID, Kind: KindDigest, TemplateID: p.group.DigestTemplateID},
+				groupTargets(strconv.FormatUint(uint64(groupID), 10), now), p.data)
+		}
+	}
+}
+
+// digestBatch 同一分组值下待合并发送的告警
+type digestBatch struct {
+	key   string
+	items []alertModel.AlertDigestItem
+}
+
+// readyDigests 按分组值归并未恢复的告警，返回自第一条起已满窗口的分组，按分组值排序；
+// items 需按写入顺序排列，window 为 0（摘要已关闭）时全部立即发送
+func readyDigests(items []alertModel.AlertDigestItem, resolved map[uint]bool, windowMinutes int, now time.Time) []digestBatch {
+	byKey := make(map[string][]alertModel.AlertDigestItem)
+	for _, item := range items {
+		if !resolved[item.EventID] {
+			byKey[item.GroupKey] = append(byKey[item.GroupKey], item)
+		}
+	}
+	window := time.Duration(windowMinutes) * time.Minute
+	keys := make([]string, 0, len(byKey))
+	for key := range byKey {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var ready []digestBatch
+	for _, key := range keys {
+		keyItems := byKey[key]
+		if windowMinutes > 0 && now.Sub(keyItems[0].CreatedAt) < window {
+			continue
+		}
+		ready = append(ready, digestBatch{key: key, items: keyItems})
+	}
+	return ready
+}
+
+func claimDigests(groupID uint, now time.Time) ([]pendingDigest, error) {
+	var pending []pendingDigest
+	err := postgres.DB.Transaction(func(tx *gorm.DB) error {
+		var items []alertModel.AlertDigestItem
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("group_id = ? AND digest_id IS NULL", groupID).Order("id").Find(&items).Error; err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		var group alertModel.AlertGroup
+		if err := tx.First(&group, groupID).Error; err != nil || group.Status != 1 {
+			// 告警组已删除或已禁用，暂存的告警不再发送
+			return tx.Where("group_id = ? AND digest_id IS NULL", groupID).Delete(&alertModel.AlertDigestItem{}).Error
+		}
+		eventIDs := make([]uint, 0, len(items))
+		for _, item := range items {
+			eventIDs = append(eventIDs, item.EventID)
+		}
+		var resolved []uint
+		if err := tx.Model(&alertModel.AlertEvent{}).Where("id IN ? AND state = ?", eventIDs, StateResolved).
+			Pluck("id", &resolved).Error; err != nil {
+			return err
+		}
+		if len(resolved) > 0 {
+			if err := tx.Where("group_id = ? AND digest_id IS NULL AND event_id IN ?", groupID, resolved).
+				Delete(&alertModel.AlertDigestItem{}).Error; err != nil {
+				return err
+			}
+		}
+		isResolved := make(map[uint]bool, len(resolved))
+		for _, id := range resolved {
+			isResolved[id] = true
+		}
+
+		for _, batch := range readyDigests(items, isResolved, group.DigestWindow, now) {
+			key, keyItems := batch.key, batch.items
+			data := &alertService.DigestData{
+				GroupName: group.Name,
+				GroupBy:   group.DigestGroupBy,
+				GroupKey:  key,
+				Count:     len(keyItems),
+				Since:     keyItems[0].CreatedAt,
+				Until:     keyItems[len(keyItems)-1].CreatedAt,
+			}
+			ids := make([]uint, 0, len(keyItems))
+			for _, item := range keyItems {
+				ids = append(ids, item.ID)
+				var alert alertService.TemplateData
+				if err := json.Unmarshal([]byte(item.Payload), &alert); err == nil {
+					data.Alerts = append(data.Alerts, &alert)
+				}
+			}
+			digest := alertModel.AlertDigest{GroupID: group.ID, Kind: KindDigest, GroupKey: key, Count: len(keyItems)}
+			if err := tx.Create(&digest).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&alertModel.AlertDigestItem{}).Where("id IN ?", ids).Update("digest_id", digest.ID).Error; err != nil {
+				return err
+			}
+			pending = append(pending, pendingDigest{digest: digest, group: group, data: data})
+		}
+		return nil
+	})
+	return pending, err
+}
+
+// sendDailySummaries 到达告警组配置的汇总时间后发送前一天的运行汇总，每个告警组每天只发送一次
+func sendDailySummaries(now time.Time) {
+	var groups []alertModel.AlertGroup
+	if err := postgres.DB.Where("status = ? AND summary_time <> ''", 1).Find(&groups).Error; err != nil {
+		log.Printf("[Alerting] 加载每日汇总配置失败: %v", err)
+		return
+	}
+	for _, group := range groups {
+		at, err := time.ParseInLocation("15:04", group.SummaryTime, now.Location())
+		if err != nil {
+			continue
+		}
+		if now.Hour()*60+now.Minute() < at.Hour()*60+at.Minute() {
+			continue
+		}
+		date := now.AddDate(0, 0, -1).Format("2006-01-02")
+		dedupKey := fmt.Sprintf("summary:%d:%s", group.ID, date)
+		var sent int64
+		if err := postgres.DB.Model(&alertModel.AlertDigest{}).Where("dedup_key = ?", dedupKey).Count(&sent).Error; err != nil || sent > 0 {
+			continue
+		}
+		// 先生成汇总再记录发送，统计失败时下一轮重试
+		data, err := dailySummary(group, date)
+		if err != nil {
+			log.Printf("[Alerting] 统计每日汇总失败: group=%d, error=%v", group.ID, err)
+			continue
+		}
+		digest := alertModel.AlertDigest{
+			GroupID:  group.ID,
+			Kind:     KindSummary,
+			GroupKey: date,
+			Count:    len(data.Tasks),
+			DedupKey: dedupKey,
+		}
+		result := postgres.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&digest)
+		if result.Error != nil {
+			log.Printf("[Alerting] 记录每日汇总失败: group=%d, error=%v", group.ID, result.Error)
+			continue
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		targets := groupTargets(strconv.FormatUint(uint64(group.ID), 10), now)
+		log.Printf("[Alerting] 发送每日汇总: group=%s, date=%s, tasks=%d", group.Name, date, len(data.Tasks))
+		if len(targets) > 0 && enqueue(alertModel.AlertDelivery{DigestID: &digest.ID, Kind: KindSummary, TemplateID: group.SummaryTemplateID}, targets, data) == 0 {
+			// 写入发送队列失败，删除发送记录以便下一轮重试
+			if err := postgres.DB.Delete(&digest).Error; err != nil {
+				log.Printf("[Alerting] 删除每日汇总记录失败: group=%d, error=%v", group.ID, err)
+			}
+		}
+	}
+}
+
+// dailySummary 统计告警组关联任务在 date 当天的运行情况
+func dailySummary(group alertModel.AlertGroup, date string) (*alertService.SummaryData, error) {
+	data := &alertService.SummaryData{GroupName: group.Name, Date: date}
+	var tasks []seatunnelModel.EtlTask
+	if err := postgres.DB.Where("id IN (?)", postgres.DB.Model(&alertModel.AlertTaskGroup{}).Select("task_id").Where("source = ? AND group_id = ?", alertModel.TaskSourceETL, group.ID)).
+		Order("id").Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	summaries := make(map[uint]*alertService.TaskSummary)
+	var taskIDs []uint
+	for _, task := range tasks {
+		summaries[task.ID] = &alertService.TaskSummary{TaskID: task.ID, JobName: task.Name, TaskType: task.TaskType, Cluster: task.Cluster}
+		taskIDs = append(taskIDs, task.ID)
+	}
+	if len(taskIDs) == 0 {
+		return data, nil
+	}
+
+	var stats []alertModel.AlertTaskDailyStat
+	if err := postgres.DB.Where("date = ? AND task_id IN ?", date, taskIDs).Find(&stats).Error; err != nil {
+		return nil, err
+	}
+	for _, stat := range stats {
+		s := summaries[stat.TaskID]
+		s.Runs, s.Succeeded, s.Failed, s.Canceled = stat.Runs, stat.Succeeded, stat.Failed, stat.Canceled
+	}
+
+	start, err := time.ParseInLocation("2006-01-02", date, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	var counts []struct {
+		TaskID uint
+		Count  int
+	}
+	if err := postgres.DB.Model(&alertModel.AlertEvent{}).Select("task_id, count(*) AS count").
+		Where("source = ? AND task_id IN ? AND suppressed = '' AND created_at >= ? AND created_at < ?", alertModel.TaskSourceETL, taskIDs, start, start.AddDate(0, 0, 1)).
+		Group("task_id").Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	for _, c := range counts {
+		summaries[c.TaskID].Alerts = c.Count
+	}
+
+	for _, id := range taskIDs {
+		s := summaries[id]
+		data.Tasks = append(data.Tasks, *s)
+		data.Total.Runs += s.Runs
+		data.Total.Succeeded += s.Succeeded
+		data.Total.Failed += s.Failed
+		data.Total.Canceled += s.Canceled
+		data.Total.Alerts += s.Alerts
+	}
+	return data, nil
+}
+
+// recordRunStat 累加任务当天的运行统计，status 为 submitted/submit_failed 或作业状态
+func recordRunStat(taskID uint, status string, at time.Time) error {
+	var runs, succeeded, failed, canceled int
+	switch status {
+	case "submitted":
+		runs = 1
+	case "submit_failed":
+		runs, failed = 1, 1
+	case "FINISHED":
+		succeeded = 1
+	case "FAILED":
+		failed = 1
+	case "CANCELED":
+		canceled = 1
+	default:
+		return nil
+	}
+	return postgres.DB.Exec(`INSERT INTO alert_task_daily_stats (date, task_id, runs, succeeded, failed, canceled, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (date, task_id) DO UPDATE SET runs = alert_task_daily_stats.runs + EXCLUDED.runs,
+succeeded = alert_task_daily_stats.succeeded + EXCLUDED.succeeded, failed = alert_task_daily_stats.failed + EXCLUDED.failed,
+canceled = alert_task_daily_stats.canceled + EXCLUDED.canceled, updated_at = EXCLUDED.updated_at`,
+		at.Format("2006-01-02"), taskID, runs, succeeded, failed, canceled, time.Now()).Error
+}
+
+// digestTemplate 摘要与每日汇总使用的模板，未配置或模板已删除时使用内置模板
+func digestTemplate(kind string, templateID uint) alertModel.AlertTemplate {
+	fallback := alertService.DefaultDigestTemplate
+	if kind == KindSummary {
+		fallback = alertService.DefaultSummaryTemplate
+	}
+	if templateID == 0 {
+		return fallback
+	}
+	var tpl alertModel.AlertTemplate
+	if err := postgres.DB.First(&tpl, templateID).Error; err != nil {
+		log.Printf("[Alerting] 模板 %d 不存在，使用内置模板", templateID)
+		return fallback
+	}
+	return tpl
+}
@@ -0,0 +1,96 @@
+package alerting
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	alertModel "octoops/internal/model/alert"
+	alertService "octoops/internal/service/alert"
+)
+
+func TestReadyDigests(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	item := func(id, eventID uint, key string, ago time.Duration) alertModel.AlertDigestItem {
+		return alertModel.AlertDigestItem{ID: id, EventID: eventID, GroupKey: key, CreatedAt: now.Add(-ago)}
+	}
+	items := []alertModel.AlertDigestItem{
+		item(1, 101, "cluster-b", 12*time.Minute),
+		item(2, 102, "cluster-a", 11*time.Minute),
+		item(3, 103, "cluster-c", 3*time.Minute),
+		item(4, 104, "cluster-a", 2*time.Minute),
+		item(5, 105, "cluster-b", time.Minute),
+	}
+	tests := []struct {
+		name     string
+		resolved map[uint]bool
+		window   int
+		want     map[string][]uint
+		wantKeys []string
+	}{
+		{
+			name:     "window elapsed since first alert",
+			window:   10,
+			wantKeys: []string{"cluster-a", "cluster-b"},
+			want:     map[string][]uint{"cluster-a": {2, 4}, "cluster-b": {1, 5}},
+		},
+		{
+			name:     "resolved alerts excluded",
+			resolved: map[uint]bool{101: true},
+			window:   10,
+			wantKeys: []string{"cluster-a"},
+			want:     map[string][]uint{"cluster-a": {2, 4}},
+		},
+		{
+			name:     "window not elapsed",
+			window:   30,
+			wantKeys: nil,
+		},
+		{
+			name:     "digest disabled sends all",
+			window:   0,
+			wantKeys: []string{"cluster-a", "cluster-b", "cluster-c"},
+			want:     map[string][]uint{"cluster-a": {2, 4}, "cluster-b": {1, 5}, "cluster-c": {3}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ready := readyDigests(items, tt.resolved, tt.window, now)
+			var keys []string
+			for _, batch := range ready {
+				keys = append(keys, batch.key)
+				var ids []uint
+				for _, it := range batch.items {
+					ids = append(ids, it.ID)
+				}
+				if !reflect.DeepEqual(ids, tt.want[batch.key]) {
+					t.Errorf("batch %s items = %v, want %v", batch.key, ids, tt.want[batch.key])
+				}
+			}
+			if !reflect.DeepEqual(keys, tt.wantKeys) {
+				t.Errorf("readyDigests() keys = %v, want %v", keys, tt.wantKeys)
+			}
+		})
+	}
+}
+
+func TestDigestKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		groupBy string
+		data    alertService.TemplateData
+		want    string
+	}{
+		{"cluster", "cluster", alertService.TemplateData{Cluster: "prod", Status: "FAILED"}, "prod"},
+		{"default cluster", "cluster", alertService.TemplateData{Status: "FAILED"}, "default"},
+		{"status", "status", alertService.TemplateData{Cluster: "prod", Status: "FAILED"}, "FAILED"},
+		{"all in one", "", alertService.TemplateData{Cluster: "prod", Status: "FAILED"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := digestKey(tt.groupBy, &tt.data); got != tt.want {
+				t.Errorf("digestKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,420 @@
+package alerting
+
+import (
+	"fmt"
+	"log"
+	"octoops/internal/event"
+	"octoops/internal/infra/postgres"
+	alertModel "octoops/internal/model/alert"
+	seatunnelModel "octoops/internal/model/seatunnel"
+	"octoops/internal/scheduler"
+	"os"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// 定时评估的间隔，用于运行时长、截止时间和调度器状态类规则
+	evaluateInterval = time.Minute
+	// 失败事件去重记录的保留时间，需覆盖发件箱的最长重试周期
+	failureKeyRetention = 7 * 24 * time.Hour
+)
+
+var (
+	startOnce          sync.Once
+	schedulerStoppedAt time.Time
+)
+
+// RegisterEventHandlers 订阅作业状态变化、任务运行结束与安全组变更事件，评估事件类告警规则并记录每日运行统计
+func RegisterEventHandlers() {
+	event.On("alerting.job_status", func(ev event.JobStatusChanged) error {
+		return onJobStatusChanged(ev)
+	})
+	event.On("alerting.task_run", func(ev event.TaskRunFinished) error {
+		// 提交失败同样计入连续失败次数
+		if ev.Source != event.SourceEtl || ev.Status != "failed" {
+			return nil
+		}
+		var task seatunnelModel.EtlTask
+		if err := postgres.DB.First(&task, ev.TaskID).Error; err != nil {
+			return err
+		}
+		return recordFailure(task, "提交作业失败: "+ev.Result, fmt.Sprintf("submit:%d", ev.At.UnixNano()), ev.At)
+	})
+	event.On("alerting.custom_task_run", func(ev event.TaskRunFinished) error {
+		return onCustomTaskRun(ev)
+	})
+	event.On("alerting.sg_rule_changed", func(ev event.SGRuleChanged) error {
+		return onSGRuleChanged(ev)
+	})
+	// 运行统计单独订阅，告警处理失败重试时不重复计数
+	event.On("alerting.run_stat.job_status", func(ev event.JobStatusChanged) error {
+		return recordRunStat(ev.TaskID, ev.NewStatus, ev.At)
+	})
+	event.On("alerting.run_stat.task_run", func(ev event.TaskRunFinished) error {
+		if ev.Source != event.SourceEtl {
+			return nil
+		}
+		if ev.Status == "failed" {
+			return recordRunStat(ev.TaskID, "submit_failed", ev.At)
+		}
+		return recordRunStat(ev.TaskID, "submitted", ev.At)
+	})
+}
+
+// Start 启动定时评估协程（同时执行告警升级、摘要与每日汇总）和告警发送协程，需在调度器初始化之后调用
+func Start() {
+	startOnce.Do(func() {
+		startDeliveryWorkers()
+		go func() {
+			ticker := time.NewTicker(evaluateInterval)
+			defer ticker.Stop()
+			for range ticker.C {
+				now := time.Now()
+				evaluateScheduled(now)
+				processEscalations(now)
+				flushDigests(now)
+				sendDailySummaries(now)
+			}
+		}()
+	})
+}
+
+func onJobStatusChanged(ev event.JobStatusChanged) error {
+	var task seatunnelModel.EtlTask
+	if err := postgres.DB.First(&task, ev.TaskID).Error; err != nil {
+		return err
+	}
+	rules, err := taskRules(task, ConditionStatusTransition)
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		if !transitionMatches(rule, ev.OldStatus, ev.NewStatus) {
+			continue
+		}
+		reason := fmt.Sprintf("作业状态由 %s 变为 %s", displayStatus(ev.OldStatus), ev.NewStatus)
+		if err := fire(rule, &task, ev.NewStatus, reason, fmt.Sprintf("task:%d:transition:%s:%d", task.ID, ev.NewStatus, ev.At.UnixNano())); err != nil {
+			return err
+		}
+	}
+
+	// 离线作业完成或实时作业恢复运行视为恢复
+	recovered := ev.NewStatus == "FINISHED" || ev.NewStatus == "RUNNING" && task.TaskType == "stream"
+	if err := resolveTaskEvents(task, ev.NewStatus, recovered); err != nil {
+		return err
+	}
+	switch {
+	case ev.NewStatus == "FAILED":
+		return recordFailure(task, "作业状态变为 FAILED", fmt.Sprintf("status:%d", ev.At.UnixNano()), ev.At)
+	case recovered:
+		// 恢复时清零连续失败次数
+		return postgres.DB.Where("task_id = ?", task.ID).Delete(&alertModel.AlertTaskState{}).Error
+	}
+	return nil
+}
+
+// transitionMatches 状态变化规则：目标状态一致，且未限定来源状态或来源状态一致
+func transitionMatches(rule alertModel.AlertRule, oldStatus, newStatus string) bool {
+	return rule.ToStatus == newStatus && (rule.FromStatus == "" || rule.FromStatus == oldStatus)
+}
+
+// failureThresholdReached 连续失败规则恰好达到阈值时通知一次，同一轮连续失败不重复通知
+func failureThresholdReached(rule alertModel.AlertRule, count int) bool {
+	return rule.Threshold > 0 && count == rule.Threshold
+}
+
+// recordFailure 累加连续失败次数，并评估连续失败规则。failureKey 标识单次失败，
+// 发件箱重复投递同一事件时不重复计数，沿用首次计入时的次数重新评估（告警按 dedupKey 去重）
+func recordFailure(task seatunnelModel.EtlTask, reason, failureKey string, at time.Time) error {
+	var count int
+	err := postgres.DB.Transaction(func(tx *gorm.DB) error {
+		failure := alertModel.AlertTaskFailure{TaskID: task.ID, FailureKey: fmt.Sprintf("task:%d:%s", task.ID, failureKey)}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&failure)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			if err := tx.Where("failure_key = ?", failure.FailureKey).First(&failure).Error; err != nil {
+				return err
+			}
+			count = failure.Count
+			return nil
+		}
+		if err := tx.Raw(`INSERT INTO alert_task_states (task_id, consecutive_failures, updated_at) VALUES (?, 1, ?)
+ON CONFLICT (task_id) DO UPDATE SET consecutive_failures = alert_task_states.consecutive_failures + 1, updated_at = EXCLUDED.updated_at
+RETURNING consecutive_failures`, task.ID, time.Now()).Scan(&count).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&failure).Update("count", count).Error; err != nil {
+			return err
+		}
+		return tx.Where("task_id = ? AND created_at < ?", task.ID, time.Now().Add(-failureKeyRetention)).
+			Delete(&alertModel.AlertTaskFailure{}).Error
+	})
+	if err != nil {
+		return err
+	}
+	rules, err := taskRules(task, ConditionConsecutiveFailures)
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		if !failureThresholdReached(rule, count) {
+			continue
+		}
+		msg := fmt.Sprintf("连续失败 %d 次，最近一次: %s", count, reason)
+		if err := fire(rule, &task, task.JobStatus, msg, fmt.Sprintf("task:%d:failures:%d:%d", task.ID, count, at.Unix())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// evaluateScheduled 评估运行时长、截止时间和调度器停止规则
+func evaluateScheduled(now time.Time) {
+	var rules []alertModel.AlertRule
+	if err := postgres.DB.Where("status = ? AND condition_type IN ?", 1,
+		[]string{ConditionDurationExceeded, ConditionDeadlineMissed, ConditionSchedulerStopped}).Find(&rules).Error; err != nil {
+		log.Printf("[Alerting] 加载告警规则失败: %v", err)
+		return
+	}
+
+	host, _ := os.Hostname()
+	var recovered bool
+	schedulerStoppedAt, recovered = schedulerStopState(scheduler.IsRunning(), schedulerStoppedAt, now)
+	if recovered {
+		resolveSchedulerEvents(host)
+	}
+
+	for _, rule := range rules {
+		var err error
+		switch rule.ConditionType {
+		case ConditionDurationExceeded:
+			err = evaluateDuration(rule, now)
+		case ConditionDeadlineMissed:
+			err = evaluateDeadline(rule, now)
+		case ConditionSchedulerStopped:
+			if !schedulerStoppedAt.IsZero() {
+				reason := fmt.Sprintf("实例 %s 的调度器已于 %s 停止", host, schedulerStoppedAt.Format("2006-01-02 15:04:05"))
+				err = fire(rule, nil, "STOPPED", reason, fmt.Sprintf("scheduler:%s:%d", host, schedulerStoppedAt.Unix()))
+			}
+		}
+		if err != nil {
+			log.Printf("[Alerting] 评估告警规则失败: rule=%d, error=%v", rule.ID, err)
+		}
+	}
+}
+
+// schedulerStopState 根据调度器运行状态更新停止时间，返回新的停止时间及是否由停止恢复为运行
+func schedulerStopState(running bool, stoppedAt, now time.Time) (time.Time, bool) {
+	if running {
+		return time.Time{}, !stoppedAt.IsZero()
+	}
+	if stoppedAt.IsZero() {
+		return now, false
+	}
+	return stoppedAt, false
+}
+
+// durationExceeded 离线作业处于运行中且自最近一次运行起超过规则阈值
+func durationExceeded(rule alertModel.AlertRule, task seatunnelModel.EtlTask, now time.Time) bool {
+	threshold := time.Duration(rule.DurationMinutes) * time.Minute
+	return task.JobStatus == "RUNNING" && task.LastRunTime != nil && now.Sub(*task.LastRunTime) >= threshold
+}
+
+// deadlineDue 返回当天的起始时间，以及当前是否已过规则的每日截止时间
+func deadlineDue(rule alertModel.AlertRule, now time.Time) (time.Time, bool, error) {
+	deadline, err := time.ParseInLocation("15:04", rule.Deadline, now.Location())
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	due := dayStart.Add(time.Duration(deadline.Hour())*time.Hour + time.Duration(deadline.Minute())*time.Minute)
+	return dayStart, !now.Before(due), nil
+}
+
+// deadlineMissed 启用的任务当天尚未完成
+func deadlineMissed(task seatunnelModel.EtlTask, dayStart time.Time) bool {
+	if task.Status != 1 {
+		return false
+	}
+	return task.JobStatus != "FINISHED" || task.FinishTime == nil || task.FinishTime.Before(dayStart)
+}
+
+// evaluateDuration 离线作业运行超过阈值时告警，每次运行只通知一次
+func evaluateDuration(rule alertModel.AlertRule, now time.Time) error {
+	tasks, err := ruleTasks(rule, "batch")
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		if !durationExceeded(rule, task, now) {
+			continue
+		}
+		reason := fmt.Sprintf("作业已运行 %d 分钟，超过阈值 %d 分钟", int(now.Sub(*task.LastRunTime).Minutes()), rule.DurationMinutes)
+		if err := fire(rule, &task, task.JobStatus, reason, fmt.Sprintf("task:%d:duration:%d", task.ID, task.LastRunTime.Unix())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// evaluateDeadline 启用的离线作业到每日截止时间仍未完成时告警，每天只通知一次
+func evaluateDeadline(rule alertModel.AlertRule, now time.Time) error {
+	dayStart, due, err := deadlineDue(rule, now)
+	if err != nil || !due {
+		return err
+	}
+	tasks, err := ruleTasks(rule, "batch")
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		if !deadlineMissed(task, dayStart) {
+			continue
+		}
+		reason := fmt.Sprintf("作业截至 %s 仍未完成，当前状态 %s", rule.Deadline, displayStatus(task.JobStatus))
+		if err := fire(rule, &task, task.JobStatus, reason, fmt.Sprintf("task:%d:deadline:%s", task.ID, dayStart.Format("2006-01-02"))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveSchedulerEvents 调度器恢复运行后恢复本实例的调度器停止告警
+func resolveSchedulerEvents(host string) {
+	var records []alertModel.AlertEvent
+	postgres.DB.Where("condition_type = ? AND state <> ? AND dedup_key LIKE ?", ConditionSchedulerStopped, StateResolved, "scheduler:"+host+":%").Find(&records)
+	for _, record := range records {
+		resolve(record, "auto", "调度器已恢复运行")
+	}
+}
+
+// taskRules 返回对任务生效的指定条件类型的启用规则
+func taskRules(task seatunnelModel.EtlTask, conditionType string) ([]alertModel.AlertRule, error) {
+	var rules []alertModel.AlertRule
+	err := postgres.DB.Where("status = ? AND condition_type = ?", 1, conditionType).
+		Where("task_id = ? OR task_id IS NULL", task.ID).
+		Where("task_type = '' OR task_type = ?", task.TaskType).
+		Find(&rules).Error
+	return rules, err
+}
+
+// ruleTasks 返回规则作用范围内指定类型的任务
+func ruleTasks(rule alertModel.AlertRule, taskType string) ([]seatunnelModel.EtlTask, error) {
+	if rule.TaskType != "" && rule.TaskType != taskType {
+		return nil, nil
+	}
+	var tasks []seatunnelModel.EtlTask
+	db := postgres.DB.Where("task_type = ?", taskType)
+	if rule.TaskID != nil {
+		db = db.Where("id = ?", *rule.TaskID)
+	}
+	err := db.Find(&tasks).Error
+	return tasks, err
+}
+
+// fire 记录规则触发的告警并通知告警组，task 为空时为调度器告警
+func fire(rule alertModel.AlertRule, task *seatunnelModel.EtlTask, status, reason, dedupKey string) error {
+	record := alertModel.AlertEvent{
+		RuleID:        rule.ID,
+		RuleName:      rule.Name,
+		ConditionType: rule.ConditionType,
+		Severity:      rule.Severity,
+		Source:        alertModel.TaskSourceETL,
+		Status:        status,
+		Reason:        reason,
+		DedupKey:      dedupKey,
+		Fingerprint:   fmt.Sprintf("rule:%d:scheduler", rule.ID),
+		AlertGroups:   rule.AlertGroups,
+		State:         StateFiring,
+		CreatedAt:     time.Now(),
+	}
+	if task != nil {
+		if record.AlertGroups == "" {
+			record.AlertGroups = taskAlertGroups(alertModel.TaskSourceETL, task.ID, rule.Severity, taskNotifyEvent(status))
+		}
+		record.TaskID = &task.ID
+		record.TaskName = task.Name
+		record.TaskType = task.TaskType
+		record.Fingerprint = fmt.Sprintf("rule:%d:task:%d", rule.ID, task.ID)
+	}
+	return fireRecord(rule, record, task)
+}
+
+// fireRecord 记录告警并通知告警组；dedupKey 相同的告警只记录一次，多副本同时评估时也不会重复。
+// 命中静默或处于重复通知间隔内的告警只记录不通知，重复间隔与升级策略取自 rule
+func fireRecord(rule alertModel.AlertRule, record alertModel.AlertEvent, task *seatunnelModel.EtlTask) error {
+	now := record.CreatedAt
+	inserted := false
+	err := postgres.DB.Transaction(func(tx *gorm.DB) error {
+		// 同一指纹串行判断，避免多副本同时通知
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", record.Fingerprint).Error; err != nil {
+			return err
+		}
+		silence, err := findSilence(tx, alertLabels(record), now)
+		if err != nil {
+			return err
+		}
+		if silence != nil {
+			record.Suppressed = SuppressedSilenced
+			record.SilenceID = &silence.ID
+		} else if rule.RepeatInterval > 0 {
+			var last alertModel.AlertEvent
+			if err := tx.Select("created_at").Where("fingerprint = ? AND suppressed = ''", record.Fingerprint).
+				Order("created_at desc").Limit(1).Find(&last).Error; err != nil {
+				return err
+			}
+			if withinRepeatInterval(rule, last.CreatedAt, now) {
+				record.Suppressed = SuppressedDeduplicated
+			}
+		}
+		if record.Suppressed == "" && rule.EscalationID != nil {
+			var escalation alertModel.AlertEscalation
+			if tx.First(&escalation, *rule.EscalationID).Error == nil {
+				record.EscalationID = rule.EscalationID
+				record.NextEscalationAt = nextEscalationAt(parseSteps(escalation.Steps), 0, now)
+			}
+		}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+		inserted = result.RowsAffected > 0
+		return result.Error
+	})
+	if err != nil || !inserted {
+		return err
+	}
+	if record.Suppressed != "" {
+		log.Printf("[Alerting] 告警已抑制(%s): rule=%s, task=%s, reason=%s", record.Suppressed, record.RuleName, record.TaskName, record.Reason)
+		return nil
+	}
+	log.Printf("[Alerting] 触发告警: rule=%s, severity=%s, task=%s, reason=%s", record.RuleName, record.Severity, record.TaskName, record.Reason)
+	if record.AlertGroups == "" {
+		return nil
+	}
+	kind := KindFiring
+	if record.State == StateResolved {
+		// 已恢复的告警（如变更通知）只通知一次，不进入摘要
+		kind = KindNotice
+	}
+	notifyGroups(record, record.AlertGroups, kind, templateData(record, task))
+	return nil
+}
+
+// withinRepeatInterval 距同一指纹上次通知的时间仍在规则的重复通知间隔内，lastNotified 为零值表示从未通知
+func withinRepeatInterval(rule alertModel.AlertRule, lastNotified, now time.Time) bool {
+	if rule.RepeatInterval <= 0 || lastNotified.IsZero() {
+		return false
+	}
+	return now.Sub(lastNotified) < time.Duration(rule.RepeatInterval)*time.Minute
+}
+
+func displayStatus(status string) string {
+	if status == "" {
+		return "（空）"
+	}
+	return status
+}
@@ -0,0 +1,185 @@
+package alerting
+
+import (
+	"testing"
+	"time"
+
+	alertModel "octoops/internal/model/alert"
+	seatunnelModel "octoops/internal/model/seatunnel"
+)
+
+func TestTransitionMatches(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     alertModel.AlertRule
+		old, new string
+		want     bool
+	}{
+		{"any source", alertModel.AlertRule{ToStatus: "FAILED"}, "RUNNING", "FAILED", true},
+		{"source matches", alertModel.AlertRule{FromStatus: "FAILED", ToStatus: "RUNNING"}, "FAILED", "RUNNING", true},
+		{"source differs", alertModel.AlertRule{FromStatus: "FAILED", ToStatus: "RUNNING"}, "CREATED", "RUNNING", false},
+		{"target differs", alertModel.AlertRule{ToStatus: "FAILED"}, "RUNNING", "CANCELED", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := transitionMatches(tt.rule, tt.old, tt.new); got != tt.want {
+				t.Errorf("transitionMatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFailureThresholdReached(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold int
+		count     int
+		want      bool
+	}{
+		{"below", 3, 2, false},
+		{"reached", 3, 3, true},
+		{"already notified", 3, 4, false},
+		{"zero threshold", 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := alertModel.AlertRule{Threshold: tt.threshold}
+			if got := failureThresholdReached(rule, tt.count); got != tt.want {
+				t.Errorf("failureThresholdReached() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDurationExceeded(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	ago := func(d time.Duration) *time.Time {
+		at := now.Add(-d)
+		return &at
+	}
+	rule := alertModel.AlertRule{DurationMinutes: 30}
+	tests := []struct {
+		name string
+		task seatunnelModel.EtlTask
+		want bool
+	}{
+		{"exceeded", seatunnelModel.EtlTask{JobStatus: "RUNNING", LastRunTime: ago(31 * time.Minute)}, true},
+		{"exactly threshold", seatunnelModel.EtlTask{JobStatus: "RUNNING", LastRunTime: ago(30 * time.Minute)}, true},
+		{"within threshold", seatunnelModel.EtlTask{JobStatus: "RUNNING", LastRunTime: ago(10 * time.Minute)}, false},
+		{"not running", seatunnelModel.EtlTask{JobStatus: "FINISHED", LastRunTime: ago(time.Hour)}, false},
+		{"never run", seatunnelModel.EtlTask{JobStatus: "RUNNING"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := durationExceeded(rule, tt.task, now); got != tt.want {
+				t.Errorf("durationExceeded() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeadlineDue(t *testing.T) {
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)
+	tests := []struct {
+		name     string
+		deadline string
+		now      time.Time
+		want     bool
+		wantErr  bool
+	}{
+		{"before deadline", "08:30", day.Add(8*time.Hour + 29*time.Minute), false, false},
+		{"at deadline", "08:30", day.Add(8*time.Hour + 30*time.Minute), true, false},
+		{"after deadline", "08:30", day.Add(23 * time.Hour), true, false},
+		{"invalid", "8点", day.Add(9 * time.Hour), false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dayStart, due, err := deadlineDue(alertModel.AlertRule{Deadline: tt.deadline}, tt.now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("deadlineDue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !dayStart.Equal(day) {
+				t.Errorf("deadlineDue() dayStart = %v, want %v", dayStart, day)
+			}
+			if due != tt.want {
+				t.Errorf("deadlineDue() due = %v, want %v", due, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeadlineMissed(t *testing.T) {
+	dayStart := time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)
+	today := dayStart.Add(3 * time.Hour)
+	yesterday := dayStart.Add(-time.Hour)
+	tests := []struct {
+		name string
+		task seatunnelModel.EtlTask
+		want bool
+	}{
+		{"finished today", seatunnelModel.EtlTask{Status: 1, JobStatus: "FINISHED", FinishTime: &today}, false},
+		{"finished yesterday", seatunnelModel.EtlTask{Status: 1, JobStatus: "FINISHED", FinishTime: &yesterday}, true},
+		{"still running", seatunnelModel.EtlTask{Status: 1, JobStatus: "RUNNING", FinishTime: &yesterday}, true},
+		{"never finished", seatunnelModel.EtlTask{Status: 1, JobStatus: "FINISHED"}, true},
+		{"disabled", seatunnelModel.EtlTask{Status: 0, JobStatus: "FAILED"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := deadlineMissed(tt.task, dayStart); got != tt.want {
+				t.Errorf("deadlineMissed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchedulerStopState(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	earlier := now.Add(-10 * time.Minute)
+	tests := []struct {
+		name          string
+		running       bool
+		stoppedAt     time.Time
+		wantStoppedAt time.Time
+		wantRecovered bool
+	}{
+		{"keeps running", true, time.Time{}, time.Time{}, false},
+		{"stops", false, time.Time{}, now, false},
+		{"stays stopped", false, earlier, earlier, false},
+		{"recovers", true, earlier, time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stoppedAt, recovered := schedulerStopState(tt.running, tt.stoppedAt, now)
+			if !stoppedAt.Equal(tt.wantStoppedAt) || recovered != tt.wantRecovered {
+				t.Errorf("schedulerStopState() = (%v, %v), want (%v, %v)", stoppedAt, recovered, tt.wantStoppedAt, tt.wantRecovered)
+			}
+		})
+	}
+}
+
+func TestWithinRepeatInterval(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		name     string
+		interval int
+		last     time.Time
+		want     bool
+	}{
+		{"never notified", 30, time.Time{}, false},
+		{"within interval", 30, now.Add(-10 * time.Minute), true},
+		{"interval elapsed", 30, now.Add(-30 * time.Minute), false},
+		{"long ago", 30, now.Add(-2 * time.Hour), false},
+		{"repeat disabled", 0, now.Add(-time.Minute), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := alertModel.AlertRule{RepeatInterval: tt.interval}
+			if got := withinRepeatInterval(rule, tt.last, now); got != tt.want {
+				t.Errorf("withinRepeatInterval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
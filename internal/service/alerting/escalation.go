@@ -0,0 +1,171 @@
+package alerting
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"octoops/internal/infra/postgres"
+	alertModel "octoops/internal/model/alert"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrInvalidEscalation = errors.New("升级策略参数无效")
+
+// EscalationStep 升级步骤，AfterMinutes 从告警触发时起算
+type EscalationStep struct {
+	AfterMinutes int    `json:"after_minutes"`
+	AlertGroups  string `json:"alert_groups"`
+}
+
+// EscalationRequest 创建/更新升级策略的请求
+type EscalationRequest struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Steps       []EscalationStep `json:"steps"`
+}
+
+func ListEscalations() ([]alertModel.AlertEscalation, error) {
+	var escalations []alertModel.AlertEscalation
+	err := postgres.DB.Order("id desc").Find(&escalations).Error
+	return escalations, err
+}
+
+func GetEscalation(id interface{}) (alertModel.AlertEscalation, error) {
+	var escalation alertModel.AlertEscalation
+	err := postgres.DB.First(&escalation, id).Error
+	return escalation, err
+}
+
+func CreateEscalation(req EscalationRequest) (alertModel.AlertEscalation, error) {
+	var escalation alertModel.AlertEscalation
+	if err := applyEscalation(&escalation, req); err != nil {
+		return escalation, err
+	}
+	err := postgres.DB.Create(&escalation).Error
+	return escalation, err
+}
+
+func UpdateEscalation(id interface{}, req EscalationRequest) (alertModel.AlertEscalation, error) {
+	escalation, err := GetEscalation(id)
+	if err != nil {
+		return escalation, err
+	}
+	if err := applyEscalation(&escalation, req); err != nil {
+		return escalation, err
+	}
+	err = postgres.DB.Save(&escalation).Error
+	return escalation, err
+}
+
+// DeleteEscalation 删除升级策略，引用该策略的规则不再升级
+func DeleteEscalation(id interface{}) error {
+	return postgres.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&alertModel.AlertRule{}).Where("escalation_id = ?", id).Update("escalation_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&alertModel.AlertEscalation{}, id).Error
+	})
+}
+
+func applyEscalation(escalation *alertModel.AlertEscalation, req EscalationRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return fmt.Errorf("%w: name 不能为空", ErrInvalidEscalation)
+	}
+	if len(req.Steps) == 0 {
+		return fmt.Errorf("%w: steps 不能为空", ErrInvalidEscalation)
+	}
+	last := 0
+	for i := range req.Steps {
+		step := &req.Steps[i]
+		if step.AfterMinutes <= last {
+			return fmt.Errorf("%w: 第 %d 步的 after_minutes 需大于 %d", ErrInvalidEscalation, i+1, last)
+		}
+		last = step.AfterMinutes
+		groups, err := normalizeGroups(step.AlertGroups)
+		if err != nil || groups == "" {
+			return fmt.Errorf("%w: 第 %d 步需指定有效的告警组", ErrInvalidEscalation, i+1)
+		}
+		step.AlertGroups = groups
+	}
+	data, _ := json.Marshal(req.Steps)
+	escalation.Name = name
+	escalation.Description = req.Description
+	escalation.Steps = string(data)
+	return nil
+}
+
+func parseSteps(raw string) []EscalationStep {
+	var steps []EscalationStep
+	_ = json.Unmarshal([]byte(raw), &steps)
+	return steps
+}
+
+// nextEscalationAt 返回第 level 步的执行时间，没有后续步骤时返回 nil
+func nextEscalationAt(steps []EscalationStep, level int, firedAt time.Time) *time.Time {
+	if level >= len(steps) {
+		return nil
+	}
+	at := firedAt.Add(time.Duration(steps[level].AfterMinutes) * time.Minute)
+	return &at
+}
+
+// advanceEscalation 推进告警的升级进度，返回本步需通知的告警组和需回写的字段；
+// 策略被删除或步骤已执行完时停止升级，advanced 为 false
+func advanceEscalation(record *alertModel.AlertEvent, steps []EscalationStep) (groups string, updates map[string]interface{}, advanced bool) {
+	updates = map[string]interface{}{"next_escalation_at": nil}
+	if record.EscalationLevel >= len(steps) {
+		return "", updates, false
+	}
+	groups = steps[record.EscalationLevel].AlertGroups
+	record.EscalationLevel++
+	updates["escalation_level"] = record.EscalationLevel
+	updates["next_escalation_at"] = nextEscalationAt(steps, record.EscalationLevel, record.CreatedAt)
+	return groups, updates, true
+}
+
+// processEscalations 对到期且未确认的告警执行下一步升级；先推进升级进度再发送，多副本下同一步骤只执行一次
+func processEscalations(now time.Time) {
+	type pending struct {
+		record alertModel.AlertEvent
+		groups string
+	}
+	var due []pending
+	err := postgres.DB.Transaction(func(tx *gorm.DB) error {
+		var records []alertModel.AlertEvent
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("next_escalation_at <= ? AND state = ?", now, StateFiring).
+			Order("next_escalation_at").Limit(50).Find(&records).Error; err != nil {
+			return err
+		}
+		for _, record := range records {
+			var escalation alertModel.AlertEscalation
+			var steps []EscalationStep
+			if record.EscalationID != nil && tx.First(&escalation, *record.EscalationID).Error == nil {
+				steps = parseSteps(escalation.Steps)
+			}
+			groups, updates, advanced := advanceEscalation(&record, steps)
+			if advanced {
+				due = append(due, pending{record: record, groups: groups})
+			}
+			if err := tx.Model(&alertModel.AlertEvent{}).Where("id = ?", record.ID).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("[Alerting] 处理告警升级失败: %v", err)
+		return
+	}
+	for _, p := range due {
+		task := eventTask(p.record)
+		log.Printf("[Alerting] 告警未确认，执行第 %d 步升级: event=%d, groups=%s", p.record.EscalationLevel, p.record.ID, p.groups)
+		notifyGroups(p.record, p.groups, KindEscalation, templateData(p.record, task))
+	}
+}
@@ -0,0 +1,70 @@
+package alerting
+
+import (
+	"testing"
+	"time"
+
+	alertModel "octoops/internal/model/alert"
+)
+
+func TestNextEscalationAt(t *testing.T) {
+	firedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	steps := []EscalationStep{{AfterMinutes: 10, AlertGroups: "2"}, {AfterMinutes: 30, AlertGroups: "3"}}
+	tests := []struct {
+		name  string
+		level int
+		want  *time.Time
+	}{
+		{"first step", 0, ptrTime(firedAt.Add(10 * time.Minute))},
+		{"second step", 1, ptrTime(firedAt.Add(30 * time.Minute))},
+		{"no more steps", 2, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nextEscalationAt(steps, tt.level, firedAt)
+			if (got == nil) != (tt.want == nil) || (got != nil && !got.Equal(*tt.want)) {
+				t.Errorf("nextEscalationAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdvanceEscalation(t *testing.T) {
+	firedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	steps := []EscalationStep{{AfterMinutes: 10, AlertGroups: "2"}, {AfterMinutes: 30, AlertGroups: "3,4"}}
+
+	record := alertModel.AlertEvent{CreatedAt: firedAt}
+	groups, updates, advanced := advanceEscalation(&record, steps)
+	if !advanced || groups != "2" || record.EscalationLevel != 1 {
+		t.Fatalf("第一步: groups=%q, level=%d, advanced=%v", groups, record.EscalationLevel, advanced)
+	}
+	// 下一步按告警触发时间计算，而不是按本步执行时间
+	if next, _ := updates["next_escalation_at"].(*time.Time); next == nil || !next.Equal(firedAt.Add(30*time.Minute)) {
+		t.Errorf("第一步后 next_escalation_at = %v, want %v", updates["next_escalation_at"], firedAt.Add(30*time.Minute))
+	}
+
+	groups, updates, advanced = advanceEscalation(&record, steps)
+	if !advanced || groups != "3,4" || record.EscalationLevel != 2 {
+		t.Fatalf("第二步: groups=%q, level=%d, advanced=%v", groups, record.EscalationLevel, advanced)
+	}
+	if next, _ := updates["next_escalation_at"].(*time.Time); next != nil {
+		t.Errorf("最后一步后 next_escalation_at = %v, want nil", next)
+	}
+
+	groups, updates, advanced = advanceEscalation(&record, steps)
+	if advanced || groups != "" || record.EscalationLevel != 2 {
+		t.Errorf("步骤执行完后不应继续升级: groups=%q, level=%d, advanced=%v", groups, record.EscalationLevel, advanced)
+	}
+	if _, ok := updates["escalation_level"]; ok || updates["next_escalation_at"] != nil {
+		t.Errorf("步骤执行完后只应清空 next_escalation_at: %v", updates)
+	}
+
+	// 策略被删除时步骤为空，同样停止升级
+	if _, _, advanced := advanceEscalation(&alertModel.AlertEvent{CreatedAt: firedAt}, nil); advanced {
+		t.Error("策略被删除时不应升级")
+	}
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
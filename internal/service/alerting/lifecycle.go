@@ -0,0 +1,239 @@
+package alerting
+
+import (
+	"errors"
+	"log"
+	"octoops/internal/infra/postgres"
+	alertModel "octoops/internal/model/alert"
+	seatunnelModel "octoops/internal/model/seatunnel"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 告警状态
+const (
+	StateFiring       = "firing"
+	StateAcknowledged = "acknowledged"
+	StateResolved     = "resolved"
+)
+
+var ErrEventResolved = errors.New("告警已恢复")
+
+// sentDelivery 告警已发送过的渠道及接收人
+type sentDelivery struct {
+	ChannelID uint
+	Recipient string
+}
+
+func GetEvent(id interface{}) (alertModel.AlertEvent, error) {
+	var record alertModel.AlertEvent
+	err := postgres.DB.First(&record, id).Error
+	return record, err
+}
+
+// ListDeliveries 告警的各渠道发送记录
+func ListDeliveries(eventID uint) ([]alertModel.AlertDelivery, error) {
+	var deliveries []alertModel.AlertDelivery
+	err := postgres.DB.Where("event_id = ?", eventID).Order("id").Find(&deliveries).Error
+	return deliveries, err
+}
+
+// AckEvent 确认告警，停止后续升级
+func AckEvent(id interface{}, operator string) (alertModel.AlertEvent, error) {
+	record, err := GetEvent(id)
+	if err != nil {
+		return record, err
+	}
+	if proceed, err := canAck(record.State); !proceed {
+		return record, err
+	}
+	err = postgres.DB.Model(&record).Where("state = ?", StateFiring).Updates(map[string]interface{}{
+		"state":              StateAcknowledged,
+		"acknowledged_at":    time.Now(),
+		"acknowledged_by":    operator,
+		"next_escalation_at": nil,
+	}).Error
+	if err != nil {
+		return record, err
+	}
+	return GetEvent(record.ID)
+}
+
+// canAck 只有告警中的告警可以确认；已确认的重复确认视为成功，已恢复的返回 ErrEventResolved
+func canAck(state string) (bool, error) {
+	switch state {
+	case StateResolved:
+		return false, ErrEventResolved
+	case StateAcknowledged:
+		return false, nil
+	}
+	return true, nil
+}
+
+// ResolveEvent 手动恢复告警，并向已通知的渠道发送恢复通知
+func ResolveEvent(id interface{}, operator string) (alertModel.AlertEvent, error) {
+	record, err := GetEvent(id)
+	if err != nil {
+		return record, err
+	}
+	if record.State != StateResolved {
+		resolve(record, operator, "已由 "+operator+" 手动恢复")
+	}
+	return GetEvent(record.ID)
+}
+
+// resolveTaskEvents 自动恢复任务未恢复的告警：作业恢复时恢复全部非当前状态触发的告警，
+// 作业结束运行时恢复运行时长告警
+func resolveTaskEvents(task seatunnelModel.EtlTask, status string, recovered bool) error {
+	db := postgres.DB.Where("source = ? AND task_id = ? AND state <> ?", alertModel.TaskSourceETL, task.ID, StateResolved)
+	if recovered {
+		db = db.Where("status <> ?", status)
+	} else if status != "RUNNING" {
+		db = db.Where("condition_type = ?", ConditionDurationExceeded)
+	} else {
+		return nil
+	}
+	var records []alertModel.AlertEvent
+	if err := db.Find(&records).Error; err != nil {
+		return err
+	}
+	for _, record := range records {
+		resolve(record, "auto", "作业状态已变为 "+status)
+	}
+	return nil
+}
+
+// resolve 将告警标记为已恢复；已通知的告警向原渠道发送恢复通知，多副本下只有成功更新状态的一方发送
+func resolve(record alertModel.AlertEvent, resolvedBy, reason string) {
+	now := time.Now()
+	result := postgres.DB.Model(&alertModel.AlertEvent{}).
+		Where("id = ? AND state <> ?", record.ID, StateResolved).
+		Updates(map[string]interface{}{
+			"state":              StateResolved,
+			"resolved_at":        now,
+			"resolved_by":        resolvedBy,
+			"next_escalation_at": nil,
+		})
+	if result.Error != nil {
+		log.Printf("[Alerting] 恢复告警失败: event=%d, error=%v", record.ID, result.Error)
+		return
+	}
+	if result.RowsAffected == 0 || record.Suppressed != "" {
+		return
+	}
+	log.Printf("[Alerting] 告警已恢复: event=%d, rule=%s, task=%s, reason=%s", record.ID, record.RuleName, record.TaskName, reason)
+
+	// 恢复通知发送给收到过告警的渠道和接收人，值班人已交接时仍通知原值班人
+	var sent []sentDelivery
+	postgres.DB.Model(&alertModel.AlertDelivery{}).
+		Where("(event_id = ? OR digest_id IN (?)) AND kind <> ? AND status IN ?", record.ID,
+			postgres.DB.Model(&alertModel.AlertDigestItem{}).Select("digest_id").Where("event_id = ? AND digest_id IS NOT NULL", record.ID),
+			KindResolved, []string{DeliveryPending, DeliverySucceeded}).
+		Distinct("channel_id", "recipient").Find(&sent)
+	if len(sent) == 0 {
+		return
+	}
+	allowed, all := recoverChannels(record)
+	sent = filterSent(sent, allowed, all)
+	if len(sent) == 0 {
+		return
+	}
+	channelIDs := make([]uint, 0, len(sent))
+	for _, s := range sent {
+		channelIDs = append(channelIDs, s.ChannelID)
+	}
+	var channels []alertModel.AlertChannel
+	postgres.DB.Where("id IN ? AND status = ?", channelIDs, 1).Find(&channels)
+	targets := resolveTargets(sent, channels)
+
+	record.State = StateResolved
+	record.ResolvedAt = &now
+	record.ResolvedBy = resolvedBy
+	data := templateData(record, eventTask(record))
+	data.Reason = "告警已恢复：" + reason + "（原因：" + record.Reason + "）"
+	enqueue(alertModel.AlertDelivery{EventID: record.ID, Kind: KindResolved}, targets, data)
+}
+
+// filterSent 只保留允许发送恢复通知的渠道，all 为 true 时不限制
+func filterSent(sent []sentDelivery, allowed map[uint]bool, all bool) []sentDelivery {
+	if all {
+		return sent
+	}
+	var kept []sentDelivery
+	for _, s := range sent {
+		if allowed[s.ChannelID] {
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
+
+// resolveTargets 按原发送记录组装恢复通知的目标，已删除或停用（不在 channels 中）的渠道跳过
+func resolveTargets(sent []sentDelivery, channels []alertModel.AlertChannel) []deliveryTarget {
+	byID := make(map[uint]alertModel.AlertChannel, len(channels))
+	for _, c := range channels {
+		byID[c.ID] = c
+	}
+	var targets []deliveryTarget
+	for _, s := range sent {
+		if channel, ok := byID[s.ChannelID]; ok {
+			targets = append(targets, deliveryTarget{Channel: channel, Recipients: recipientList(s.Recipient)})
+		}
+	}
+	return targets
+}
+
+// eventTask 返回告警关联的 ETL 任务，自定义任务的告警或任务已删除时返回 nil
+func eventTask(record alertModel.AlertEvent) *seatunnelModel.EtlTask {
+	if record.TaskID == nil || record.Source == alertModel.TaskSourceCustom {
+		return nil
+	}
+	var task seatunnelModel.EtlTask
+	if postgres.DB.First(&task, *record.TaskID).Error != nil {
+		return nil
+	}
+	return &task
+}
+
+// recoverChannels 任务告警按任务关联发送时，只向开启了恢复通知（notify_on 含 recover）的告警组渠道发送恢复通知；
+// all 为 true 表示不限制，如规则指定了告警组或全部关联都开启了恢复通知
+func recoverChannels(record alertModel.AlertEvent) (map[uint]bool, bool) {
+	if record.TaskID == nil || record.AlertGroups == "" {
+		return nil, true
+	}
+	// 内置告警（如自定义任务执行失败）没有规则，始终按任务关联发送
+	if record.RuleID != 0 {
+		var rule alertModel.AlertRule
+		if err := postgres.DB.Unscoped().Select("id", "alert_groups").First(&rule, record.RuleID).Error; err != nil || rule.AlertGroups != "" {
+			return nil, true
+		}
+	}
+	groups, all := recoverGroups(record.AlertGroups, taskAlertGroups(eventSource(record), *record.TaskID, record.Severity, alertModel.NotifyOnRecover))
+	if all {
+		return nil, true
+	}
+	allowed := make(map[uint]bool)
+	for _, t := range groupTargets(groups, time.Now()) {
+		allowed[t.Channel.ID] = true
+	}
+	return allowed, false
+}
+
+// recoverGroups 返回告警通知过的告警组中开启了恢复通知的部分；all 为 true 表示全部开启
+func recoverGroups(notified, recoverEnabled string) (string, bool) {
+	enabled := make(map[uint]bool)
+	for _, id := range splitGroupIDs(recoverEnabled) {
+		enabled[id] = true
+	}
+	var groups []string
+	all := true
+	for _, id := range splitGroupIDs(notified) {
+		if enabled[id] {
+			groups = append(groups, strconv.FormatUint(uint64(id), 10))
+		} else {
+			all = false
+		}
+	}
+	return strings.Join(groups, ","), all
+}
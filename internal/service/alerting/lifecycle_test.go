@@ -0,0 +1,86 @@
+package alerting
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	alertModel "octoops/internal/model/alert"
+)
+
+func TestCanAck(t *testing.T) {
+	tests := []struct {
+		state   string
+		proceed bool
+		err     error
+	}{
+		{StateFiring, true, nil},
+		{StateAcknowledged, false, nil},
+		{StateResolved, false, ErrEventResolved},
+	}
+	for _, tt := range tests {
+		t.Run(tt.state, func(t *testing.T) {
+			proceed, err := canAck(tt.state)
+			if proceed != tt.proceed || !errors.Is(err, tt.err) {
+				t.Errorf("canAck(%q) = (%v, %v), want (%v, %v)", tt.state, proceed, err, tt.proceed, tt.err)
+			}
+		})
+	}
+}
+
+func TestRecoverGroups(t *testing.T) {
+	tests := []struct {
+		name     string
+		notified string
+		enabled  string
+		want     string
+		wantAll  bool
+	}{
+		{"all enabled", "1,2", "1,2,3", "1,2", true},
+		{"partially enabled", "1,2,3", "3,1", "1,3", false},
+		{"none enabled", "1,2", "", "", false},
+		{"nothing notified", "", "1", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, all := recoverGroups(tt.notified, tt.enabled)
+			if got != tt.want || all != tt.wantAll {
+				t.Errorf("recoverGroups() = (%q, %v), want (%q, %v)", got, all, tt.want, tt.wantAll)
+			}
+		})
+	}
+}
+
+func TestFilterSent(t *testing.T) {
+	sent := []sentDelivery{{ChannelID: 1, Recipient: "a@example.com"}, {ChannelID: 2}, {ChannelID: 3, Recipient: "13800000000"}}
+	if got := filterSent(sent, nil, true); !reflect.DeepEqual(got, sent) {
+		t.Errorf("filterSent(all) = %v, want %v", got, sent)
+	}
+	want := []sentDelivery{{ChannelID: 1, Recipient: "a@example.com"}, {ChannelID: 3, Recipient: "13800000000"}}
+	if got := filterSent(sent, map[uint]bool{1: true, 3: true}, false); !reflect.DeepEqual(got, want) {
+		t.Errorf("filterSent() = %v, want %v", got, want)
+	}
+	if got := filterSent(sent, map[uint]bool{}, false); len(got) != 0 {
+		t.Errorf("filterSent(none) = %v, want empty", got)
+	}
+}
+
+func TestResolveTargets(t *testing.T) {
+	sent := []sentDelivery{
+		{ChannelID: 1, Recipient: "a@example.com, b@example.com"},
+		{ChannelID: 1, Recipient: "c@example.com"},
+		{ChannelID: 2},
+		{ChannelID: 9, Recipient: "x@example.com"},
+	}
+	// 渠道 9 已删除或停用，不在查询结果中
+	channels := []alertModel.AlertChannel{{ID: 1, Type: "email"}, {ID: 2, Type: "dingtalk"}}
+	got := resolveTargets(sent, channels)
+	want := []deliveryTarget{
+		{Channel: channels[0], Recipients: []string{"a@example.com", "b@example.com"}},
+		{Channel: channels[0], Recipients: []string{"c@example.com"}},
+		{Channel: channels[1]},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("resolveTargets() = %+v, want %+v", got, want)
+	}
+}
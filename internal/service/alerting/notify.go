@@ -0,0 +1,216 @@
+package alerting
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"octoops/internal/config"
+	"octoops/internal/infra/postgres"
+	alertModel "octoops/internal/model/alert"
+	seatunnelModel "octoops/internal/model/seatunnel"
+	alertService "octoops/internal/service/alert"
+	"octoops/internal/service/realtime"
+	seatunnelService "octoops/internal/service/seatunnel"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 告警发送类型
+const (
+	KindFiring     = "firing"
+	KindEscalation = "escalation"
+	KindResolved   = "resolved"
+	KindDigest     = "digest"
+	KindSummary    = "summary"
+	KindNotice     = "notice" // 变更通知，如安全组授权 IP 变化
+)
+
+var severityRank = map[string]int{SeverityInfo: 1, SeverityWarning: 2, SeverityCritical: 3}
+
+// taskNotifyEvent 任务告警对应的关联通知事件：离线作业完成为 finish，其余为 fail
+func taskNotifyEvent(status string) string {
+	if status == "FINISHED" {
+		return alertModel.NotifyOnFinish
+	}
+	return alertModel.NotifyOnFail
+}
+
+// linkAccepts 任务与告警组的关联是否接收指定级别的事件
+func linkAccepts(link alertModel.AlertTaskGroup, severity, event string) bool {
+	if link.MinSeverity != "" && severityRank[severity] < severityRank[link.MinSeverity] {
+		return false
+	}
+	notifyOn := link.NotifyOn
+	if notifyOn == "" {
+		notifyOn = alertModel.DefaultNotifyOn
+	}
+	for _, e := range strings.Split(notifyOn, ",") {
+		if strings.TrimSpace(e) == event {
+			return true
+		}
+	}
+	return false
+}
+
+// eventSource 告警的任务来源，来源字段为空的历史告警视为 ETL 任务
+func eventSource(record alertModel.AlertEvent) string {
+	if record.Source == "" {
+		return alertModel.TaskSourceETL
+	}
+	return record.Source
+}
+
+// taskAlertGroups 返回任务关联的告警组中接收该级别与事件的告警组 ID，逗号分隔
+func taskAlertGroups(source string, taskID uint, severity, event string) string {
+	var links []alertModel.AlertTaskGroup
+	if err := postgres.DB.Where("source = ? AND task_id = ?", source, taskID).Order("id").Find(&links).Error; err != nil {
+		log.Printf("[Alerting] 查询任务告警组失败: source=%s, task=%d, error=%v", source, taskID, err)
+		return ""
+	}
+	var ids []string
+	for _, link := range links {
+		if linkAccepts(link, severity, event) {
+			ids = append(ids, strconv.FormatUint(uint64(link.GroupID), 10))
+		}
+	}
+	return strings.Join(ids, ",")
+}
+
+// notifyGroups 向告警组内的渠道、用户及当前值班人分发告警，data 为告警模板数据，返回入队的发送记录数；
+// 开启摘要的告警组暂存首次告警，窗口结束后合并发送
+func notifyGroups(record alertModel.AlertEvent, groups, kind string, data *alertService.TemplateData) int {
+	if kind == KindFiring {
+		groups = collectDigests(record, groups, data)
+	}
+	return enqueue(alertModel.AlertDelivery{EventID: record.ID, Kind: kind}, groupTargets(groups, time.Now()), data)
+}
+
+// sendChannel 按发送类型选择模板后发送：单条告警使用渠道模板，摘要与每日汇总使用告警组配置的模板；
+// 发送记录指定了接收人时发送给接收人
+func sendChannel(channel alertModel.AlertChannel, d alertModel.AlertDelivery, data interface{}) error {
+	recipients := recipientList(d.Recipient)
+	if d.Kind == KindDigest || d.Kind == KindSummary {
+		return alertService.NotifyRecipients(&channel, digestTemplate(d.Kind, d.TemplateID), data, recipients)
+	}
+	if channel.TemplateID == 0 {
+		return errors.New("渠道未配置告警模板")
+	}
+	var tpl alertModel.AlertTemplate
+	if err := postgres.DB.First(&tpl, channel.TemplateID).Error; err != nil {
+		return errors.New("渠道未配置告警模板")
+	}
+	return alertService.NotifyRecipients(&channel, tpl, data, recipients)
+}
+
+// templateData 根据告警记录和任务构造模板数据；ETL 任务会查询 SeaTunnel 作业详情补充错误信息和指标
+func templateData(record alertModel.AlertEvent, task *seatunnelModel.EtlTask) *alertService.TemplateData {
+	data := &alertService.TemplateData{
+		AlertID:         record.ID,
+		RuleName:        record.RuleName,
+		Severity:        record.Severity,
+		State:           record.State,
+		Reason:          record.Reason,
+		EscalationLevel: record.EscalationLevel,
+		FiredAt:         record.CreatedAt,
+		JobName:         "调度器",
+		Status:          record.Status,
+		Labels:          alertLabels(record),
+		Links:           map[string]string{},
+	}
+	if task == nil {
+		// 自定义任务告警没有 ETL 任务详情，使用告警记录中的任务信息
+		if record.TaskName != "" {
+			data.JobName = record.TaskName
+			data.TaskType = record.TaskType
+		}
+		if record.TaskID != nil {
+			data.TaskID = *record.TaskID
+		}
+		return data
+	}
+	data.TaskID = task.ID
+	data.JobName = task.Name
+	data.TaskType = task.TaskType
+	data.Cluster = task.Cluster
+	data.Labels["cluster"] = task.Cluster
+	if task.LastRunTime != nil {
+		data.StartTime = task.LastRunTime.Format("2006-01-02 15:04:05")
+	}
+	if task.FinishTime != nil {
+		data.EndTime = task.FinishTime.Format("2006-01-02 15:04:05")
+	}
+	if base := config.GetExternalURL(); base != "" {
+		data.Links["task"] = fmt.Sprintf("%s/seatunnel/%s/%d/edit", base, task.TaskType, task.ID)
+	}
+	if task.JobID != nil && *task.JobID != "" {
+		data.JobID = *task.JobID
+		data.RunID = *task.JobID
+		if engine, ok := config.SeatunnelClusterURL(task.Cluster); ok {
+			data.Links["job"] = engine + "/job-info/" + *task.JobID
+		}
+		info := seatunnelService.QuerySeatunnelJobStatus(task.Cluster, *task.JobID)
+		data.ErrorMessage = info.ErrorMsg
+		data.Metrics = info.Metrics
+	}
+	return data
+}
+
+// publishAlertEvent 推送告警发送结果的实时事件
+func publishAlertEvent(data *alertService.TemplateData, channel alertModel.AlertChannel, sendErr error) {
+	permission := "notify:group:read"
+	source := data.Labels["source"]
+	switch {
+	case source == alertModel.TaskSourceCustom:
+		permission = "task:custom:read"
+	case data.TaskType != "":
+		permission = realtime.TaskReadPermission(data.TaskType)
+	}
+	if source == "" {
+		source = alertModel.TaskSourceETL
+	}
+	ev := realtime.Event{
+		Type:     realtime.TypeAlert,
+		Source:   source,
+		TaskID:   data.TaskID,
+		TaskName: data.JobName,
+		TaskType: data.TaskType,
+		Status:   "sent",
+		Message:  data.Reason + "，已通过 " + channel.Name + " 发送告警",
+	}
+	if sendErr != nil {
+		ev.Status = "failed"
+		ev.Message = "通过 " + channel.Name + " 发送告警失败: " + sendErr.Error()
+	}
+	realtime.Publish(permission, ev)
+}
+
+// ErrPreviewForbidden 无权查看预览所用任务的数据
+var ErrPreviewForbidden = errors.New("无权查看该任务，请使用示例数据预览")
+
+// PreviewData 以任务的最近一次运行构造模板预览数据，taskID 为 0 时返回示例数据；
+// allowed 判断当前用户是否拥有权限码，使用真实任务数据时需有该任务类型的 etl:<task_type>:read 权限
+func PreviewData(taskID uint, allowed func(permission string) bool) (*alertService.TemplateData, error) {
+	if taskID == 0 {
+		return alertService.SampleTemplateData(), nil
+	}
+	var task seatunnelModel.EtlTask
+	if err := postgres.DB.First(&task, taskID).Error; err != nil {
+		return nil, err
+	}
+	if !allowed("etl:" + task.TaskType + ":read") {
+		return nil, ErrPreviewForbidden
+	}
+	record := alertModel.AlertEvent{
+		RuleName:  "模板预览",
+		TaskID:    &task.ID,
+		TaskName:  task.Name,
+		TaskType:  task.TaskType,
+		Severity:  SeverityWarning,
+		State:     StateFiring,
+		Status:    task.JobStatus,
+		Reason:    "模板预览，非真实告警",
+		CreatedAt: time.Now(),
+	}
+	return templateData(record, &task), nil
+}
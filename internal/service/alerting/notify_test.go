@@ -0,0 +1,30 @@
+package alerting
+
+import (
+	"testing"
+
+	alertModel "octoops/internal/model/alert"
+)
+
+func TestLinkAccepts(t *testing.T) {
+	cases := []struct {
+		link     alertModel.AlertTaskGroup
+		severity string
+		event    string
+		want     bool
+	}{
+		{alertModel.AlertTaskGroup{}, SeverityInfo, alertModel.NotifyOnFinish, true},
+		{alertModel.AlertTaskGroup{MinSeverity: SeverityWarning}, SeverityInfo, alertModel.NotifyOnFail, false},
+		{alertModel.AlertTaskGroup{MinSeverity: SeverityWarning}, SeverityCritical, alertModel.NotifyOnFail, true},
+		{alertModel.AlertTaskGroup{NotifyOn: "fail"}, SeverityCritical, alertModel.NotifyOnRecover, false},
+		{alertModel.AlertTaskGroup{NotifyOn: "fail,recover"}, SeverityWarning, alertModel.NotifyOnRecover, true},
+	}
+	for i, c := range cases {
+		if got := linkAccepts(c.link, c.severity, c.event); got != c.want {
+			t.Errorf("case %d: linkAccepts(%+v, %s, %s) = %v, want %v", i, c.link, c.severity, c.event, got, c.want)
+		}
+	}
+	if taskNotifyEvent("FINISHED") != alertModel.NotifyOnFinish || taskNotifyEvent("FAILED") != alertModel.NotifyOnFail {
+		t.Error("taskNotifyEvent() mismatch")
+	}
+}
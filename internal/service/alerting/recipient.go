@@ -0,0 +1,161 @@
+package alerting
+
+import (
+	"log"
+	"octoops/internal/infra/postgres"
+	alertModel "octoops/internal/model/alert"
+	rbacModel "octoops/internal/model/rbac"
+	alertService "octoops/internal/service/alert"
+	"sort"
+	"strings"
+	"time"
+)
+
+// deliveryTarget 一个发送目标：渠道及接收人，Recipients 为空时发送到渠道配置的目标
+type deliveryTarget struct {
+	Channel    alertModel.AlertChannel
+	Recipients []string
+}
+
+// recipientList 解析发送记录中逗号分隔的接收人
+func recipientList(recipient string) []string {
+	var list []string
+	for _, r := range strings.Split(recipient, ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			list = append(list, r)
+		}
+	}
+	return list
+}
+
+// groupTargets 解析告警组成员：渠道成员发送到渠道目标，用户成员与值班表当前值班人按其接收方式发送；
+// 同一渠道只产生一条发送记录，接收人合并
+func groupTargets(groups string, at time.Time) []deliveryTarget {
+	plain := make(map[uint]bool)
+	userIDs := make(map[uint]bool)
+	for _, gid := range strings.Split(groups, ",") {
+		if gid = strings.TrimSpace(gid); gid == "" {
+			continue
+		}
+		var members []alertModel.AlertGroupMember
+		postgres.DB.Where("group_id = ?", gid).Find(&members)
+		for _, m := range members {
+			switch m.ChannelType {
+			case alertModel.MemberTypeUser:
+				userIDs[m.ChannelID] = true
+			case alertModel.MemberTypeOnCall:
+				shift, err := alertService.CurrentShift(m.ChannelID, at)
+				if err != nil {
+					log.Printf("[Alerting] 查询当前值班人失败: schedule=%d, error=%v", m.ChannelID, err)
+					continue
+				}
+				userIDs[shift.UserID] = true
+			default:
+				plain[m.ChannelID] = true
+			}
+		}
+	}
+
+	recipients := make(map[uint][]string)
+	if len(userIDs) > 0 {
+		ids := make([]uint, 0, len(userIDs))
+		for id := range userIDs {
+			ids = append(ids, id)
+		}
+		// 已禁用的用户（含当前值班人）不再接收告警，userRecipients 只使用 users 中用户的接收方式
+		var users []rbacModel.User
+		postgres.DB.Select("id", "email").Where("id IN ? AND status = ?", ids, 1).Find(&users)
+		var contacts []alertModel.AlertUserContact
+		postgres.DB.Where("user_id IN ?", ids).Order("id").Find(&contacts)
+		recipients = userRecipients(users, contacts, defaultEmailChannel())
+	}
+
+	channelIDs := make([]uint, 0, len(plain)+len(recipients))
+	for id := range plain {
+		channelIDs = append(channelIDs, id)
+	}
+	for id := range recipients {
+		if !plain[id] {
+			channelIDs = append(channelIDs, id)
+		}
+	}
+	if len(channelIDs) == 0 {
+		return nil
+	}
+	var channels []alertModel.AlertChannel
+	postgres.DB.Where("id IN ? AND status = ?", channelIDs, 1).Find(&channels)
+	return buildTargets(channels, plain, recipients)
+}
+
+// defaultEmailChannel 未配置接收方式的用户使用的邮件渠道：最早创建的启用邮件渠道，没有时返回 0
+func defaultEmailChannel() uint {
+	var channel alertModel.AlertChannel
+	if err := postgres.DB.Select("id").Where("type = ? AND status = ?", "email", 1).Order("id").First(&channel).Error; err != nil {
+		return 0
+	}
+	return channel.ID
+}
+
+// userRecipients 按用户的接收方式汇总各渠道的接收人；邮件渠道未填写目标时使用用户邮箱，
+// 没有任何接收方式的用户发送到 emailChannel
+func userRecipients(users []rbacModel.User, contacts []alertModel.AlertUserContact, emailChannel uint) map[uint][]string {
+	emails := make(map[uint]string, len(users))
+	for _, u := range users {
+		emails[u.ID] = u.Email
+	}
+	result := make(map[uint][]string)
+	covered := make(map[uint]bool)
+	for _, c := range contacts {
+		target := c.Target
+		if target == "" {
+			target = emails[c.UserID]
+		}
+		if _, ok := emails[c.UserID]; !ok || target == "" {
+			continue
+		}
+		result[c.ChannelID] = append(result[c.ChannelID], target)
+		covered[c.UserID] = true
+	}
+	for _, u := range users {
+		if !covered[u.ID] && u.Email != "" && emailChannel > 0 {
+			result[emailChannel] = append(result[emailChannel], u.Email)
+		}
+	}
+	return result
+}
+
+// buildTargets 合并渠道成员与用户接收人：渠道同时作为成员时，群机器人在群消息中 @ 接收人，
+// 邮件/短信/语音在渠道目标之外追加接收人；不支持指定接收人的渠道只发送到渠道目标
+func buildTargets(channels []alertModel.AlertChannel, plain map[uint]bool, recipients map[uint][]string) []deliveryTarget {
+	sort.Slice(channels, func(i, j int) bool { return channels[i].ID < channels[j].ID })
+	var targets []deliveryTarget
+	for _, channel := range channels {
+		list := recipients[channel.ID]
+		if !alertService.SupportsRecipients(channel.Type) {
+			list = nil
+		}
+		if len(list) == 0 {
+			if plain[channel.ID] {
+				targets = append(targets, deliveryTarget{Channel: channel})
+			}
+			continue
+		}
+		if plain[channel.ID] && !alertService.SupportsMention(channel.Type) {
+			list = append(recipientList(channel.Target), list...)
+		}
+		targets = append(targets, deliveryTarget{Channel: channel, Recipients: dedupe(list)})
+	}
+	return targets
+}
+
+func dedupe(list []string) []string {
+	seen := make(map[string]bool, len(list))
+	result := make([]string, 0, len(list))
+	for _, s := range list {
+		if !seen[s] {
+			seen[s] = true
+			result = append(result, s)
+		}
+	}
+	return result
+}
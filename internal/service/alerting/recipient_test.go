@@ -0,0 +1,52 @@
+package alerting
+
+import (
+	"reflect"
+	"testing"
+
+	alertModel "octoops/internal/model/alert"
+	rbacModel "octoops/internal/model/rbac"
+)
+
+func TestUserRecipients(t *testing.T) {
+	users := []rbacModel.User{{ID: 1, Email: "a@example.com"}, {ID: 2, Email: "b@example.com"}, {ID: 3}}
+	contacts := []alertModel.AlertUserContact{
+		{UserID: 1, ChannelID: 10},                        // 邮件渠道，使用用户邮箱
+		{UserID: 1, ChannelID: 20, Target: "13800000000"}, // 钉钉 @ 手机号
+		{UserID: 9, ChannelID: 20, Target: "ghost"},       // 用户不在本次接收人中
+	}
+	got := userRecipients(users, contacts, 10)
+	want := map[uint][]string{
+		10: {"a@example.com", "b@example.com"}, // 用户 2 没有接收方式，回落到默认邮件渠道
+		20: {"13800000000"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("userRecipients() = %v, want %v", got, want)
+	}
+}
+
+func TestBuildTargets(t *testing.T) {
+	channels := []alertModel.AlertChannel{
+		{ID: 3, Type: "webhook", Target: "http://hook"},
+		{ID: 2, Type: "dingtalk", Target: "http://robot"},
+		{ID: 1, Type: "email", Target: "ops@example.com"},
+		{ID: 4, Type: "sms", Target: "13900000000"},
+	}
+	plain := map[uint]bool{1: true, 2: true, 3: true}
+	recipients := map[uint][]string{
+		1: {"a@example.com", "ops@example.com"},
+		2: {"13800000000"},
+		3: {"ignored"},
+		4: {"13700000000"},
+	}
+	got := buildTargets(channels, plain, recipients)
+	want := []deliveryTarget{
+		{Channel: channels[0], Recipients: []string{"ops@example.com", "a@example.com"}},
+		{Channel: channels[1], Recipients: []string{"13800000000"}},
+		{Channel: channels[2]},
+		{Channel: channels[3], Recipients: []string{"13700000000"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("buildTargets() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,231 @@
+package alerting
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"octoops/internal/infra/postgres"
+	alertModel "octoops/internal/model/alert"
+	seatunnelModel "octoops/internal/model/seatunnel"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 告警条件类型
+const (
+	ConditionStatusTransition    = "status_transition"
+	ConditionConsecutiveFailures = "consecutive_failures"
+	ConditionDurationExceeded    = "duration_exceeded"
+	ConditionDeadlineMissed      = "deadline_missed"
+	ConditionSchedulerStopped    = "scheduler_stopped"
+)
+
+// 告警级别
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// 告警未通知的原因
+const (
+	SuppressedDeduplicated = "deduplicated"
+	SuppressedSilenced     = "silenced"
+)
+
+var ErrInvalidRule = errors.New("告警规则参数无效")
+
+// ListRules 告警规则列表，taskID 非 0 时返回该任务专属规则及全局规则
+func ListRules(taskID uint) ([]alertModel.AlertRule, error) {
+	var rules []alertModel.AlertRule
+	db := postgres.DB.Order("id desc")
+	if taskID > 0 {
+		db = db.Where("task_id = ? OR task_id IS NULL", taskID)
+	}
+	err := db.Find(&rules).Error
+	return rules, err
+}
+
+func GetRule(id interface{}) (alertModel.AlertRule, error) {
+	var rule alertModel.AlertRule
+	err := postgres.DB.First(&rule, id).Error
+	return rule, err
+}
+
+func CreateRule(rule *alertModel.AlertRule) error {
+	rule.ID = 0
+	if err := validateRule(rule); err != nil {
+		return err
+	}
+	return postgres.DB.Create(rule).Error
+}
+
+func UpdateRule(id interface{}, req alertModel.AlertRule) (alertModel.AlertRule, error) {
+	rule, err := GetRule(id)
+	if err != nil {
+		return rule, err
+	}
+	req.ID = rule.ID
+	req.CreatedAt = rule.CreatedAt
+	if err := validateRule(&req); err != nil {
+		return rule, err
+	}
+	if err := postgres.DB.Save(&req).Error; err != nil {
+		return rule, err
+	}
+	return req, nil
+}
+
+func DeleteRule(id interface{}) error {
+	return postgres.DB.Delete(&alertModel.AlertRule{}, id).Error
+}
+
+// validateRule 校验条件参数并规范化字段
+func validateRule(rule *alertModel.AlertRule) error {
+	rule.Name = strings.TrimSpace(rule.Name)
+	if rule.Name == "" {
+		return fmt.Errorf("%w: name 不能为空", ErrInvalidRule)
+	}
+	rule.FromStatus = strings.ToUpper(strings.TrimSpace(rule.FromStatus))
+	rule.ToStatus = strings.ToUpper(strings.TrimSpace(rule.ToStatus))
+	switch rule.ConditionType {
+	case ConditionStatusTransition:
+		if rule.ToStatus == "" {
+			return fmt.Errorf("%w: 状态变化规则需指定 to_status", ErrInvalidRule)
+		}
+	case ConditionConsecutiveFailures:
+		if rule.Threshold < 1 {
+			return fmt.Errorf("%w: 连续失败规则需指定 threshold ≥ 1", ErrInvalidRule)
+		}
+	case ConditionDurationExceeded:
+		if rule.DurationMinutes < 1 {
+			return fmt.Errorf("%w: 运行时长规则需指定 duration_minutes ≥ 1", ErrInvalidRule)
+		}
+	case ConditionDeadlineMissed:
+		if _, err := time.Parse("15:04", rule.Deadline); err != nil {
+			return fmt.Errorf("%w: deadline 格式应为 HH:MM", ErrInvalidRule)
+		}
+	case ConditionSchedulerStopped:
+		if rule.AlertGroups == "" {
+			return fmt.Errorf("%w: 调度器停止规则需指定告警组", ErrInvalidRule)
+		}
+		rule.TaskID = nil
+		rule.TaskType = ""
+	default:
+		return fmt.Errorf("%w: 未知条件类型 %s", ErrInvalidRule, rule.ConditionType)
+	}
+	switch rule.Severity {
+	case "":
+		rule.Severity = SeverityWarning
+	case SeverityInfo, SeverityWarning, SeverityCritical:
+	default:
+		return fmt.Errorf("%w: severity 取值为 info/warning/critical", ErrInvalidRule)
+	}
+	if rule.TaskType != "" && rule.TaskType != "stream" && rule.TaskType != "batch" {
+		return fmt.Errorf("%w: task_type 取值为 stream/batch", ErrInvalidRule)
+	}
+	if rule.TaskID != nil {
+		var task seatunnelModel.EtlTask
+		if err := postgres.DB.Select("id").First(&task, *rule.TaskID).Error; err != nil {
+			return fmt.Errorf("%w: 任务 %d 不存在", ErrInvalidRule, *rule.TaskID)
+		}
+	}
+	if rule.RepeatInterval < 0 {
+		return fmt.Errorf("%w: repeat_interval 不能小于 0", ErrInvalidRule)
+	}
+	if rule.EscalationID != nil {
+		if _, err := GetEscalation(*rule.EscalationID); err != nil {
+			return fmt.Errorf("%w: 升级策略 %d 不存在", ErrInvalidRule, *rule.EscalationID)
+		}
+	}
+	groups, err := normalizeGroups(rule.AlertGroups)
+	if err != nil {
+		return err
+	}
+	rule.AlertGroups = groups
+	return nil
+}
+
+func normalizeGroups(raw string) (string, error) {
+	var ids []string
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if _, err := strconv.ParseUint(part, 10, 64); err != nil {
+			return "", fmt.Errorf("%w: 无效的告警组 ID %s", ErrInvalidRule, part)
+		}
+		ids = append(ids, part)
+	}
+	return strings.Join(ids, ","), nil
+}
+
+// EnsureDefaultRules 没有任何规则时创建默认规则，保持“作业变为 FAILED 时通知任务告警组”的原有行为
+func EnsureDefaultRules() {
+	var count int64
+	if err := postgres.DB.Unscoped().Model(&alertModel.AlertRule{}).Count(&count).Error; err != nil {
+		log.Printf("[Alerting] 检查默认告警规则失败: %v", err)
+		return
+	}
+	if count > 0 {
+		return
+	}
+	rule := alertModel.AlertRule{
+		Name:           "作业失败",
+		Description:    "作业状态变为 FAILED 时通知任务配置的告警组",
+		ConditionType:  ConditionStatusTransition,
+		ToStatus:       "FAILED",
+		Severity:       SeverityCritical,
+		RepeatInterval: 30,
+		Status:         1,
+	}
+	if err := postgres.DB.Create(&rule).Error; err != nil {
+		log.Printf("[Alerting] 创建默认告警规则失败: %v", err)
+	}
+}
+
+// EventFilter 告警记录查询条件，为空的字段不参与筛选；Suppressed 为 none 时只返回已通知的告警
+type EventFilter struct {
+	RuleID     uint
+	Source     string // etl/custom
+	TaskID     uint
+	Severity   string
+	State      string
+	Suppressed string
+}
+
+// ListEvents 告警记录
+func ListEvents(filter EventFilter, page, pageSize int) ([]alertModel.AlertEvent, int64, error) {
+	var events []alertModel.AlertEvent
+	var total int64
+	db := postgres.DB.Model(&alertModel.AlertEvent{})
+	if filter.RuleID > 0 {
+		db = db.Where("rule_id = ?", filter.RuleID)
+	}
+	if filter.Source != "" {
+		db = db.Where("source = ?", filter.Source)
+	}
+	if filter.TaskID > 0 {
+		db = db.Where("task_id = ?", filter.TaskID)
+	}
+	if filter.Severity != "" {
+		db = db.Where("severity = ?", filter.Severity)
+	}
+	if filter.State != "" {
+		db = db.Where("state = ?", filter.State)
+	}
+	switch filter.Suppressed {
+	case "":
+	case "none":
+		db = db.Where("suppressed = ''")
+	default:
+		db = db.Where("suppressed = ?", filter.Suppressed)
+	}
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := db.Order("id desc").Offset((page - 1) * pageSize).Limit(pageSize).Find(&events).Error
+	return events, total, err
+}
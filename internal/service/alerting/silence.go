@@ -0,0 +1,171 @@
+package alerting
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"octoops/internal/infra/postgres"
+	alertModel "octoops/internal/model/alert"
+	"regexp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrInvalidSilence = errors.New("静默参数无效")
+
+// silenceFields 静默可匹配的告警字段
+var silenceFields = map[string]bool{
+	"rule_id":        true,
+	"rule_name":      true,
+	"condition_type": true,
+	"severity":       true,
+	"task_id":        true,
+	"task_name":      true,
+	"task_type":      true,
+	"source":         true,
+}
+
+// Matcher 静默匹配条件，Regex 为 true 时 Value 按完整正则匹配
+type Matcher struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Regex bool   `json:"regex"`
+}
+
+// SilenceRequest 创建/更新静默的请求，StartsAt 为空表示立即生效
+type SilenceRequest struct {
+	Comment  string     `json:"comment"`
+	Matchers []Matcher  `json:"matchers"`
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   time.Time  `json:"ends_at"`
+}
+
+// ListSilences 静默列表，active 为 true 时只返回当前生效的静默
+func ListSilences(active bool) ([]alertModel.AlertSilence, error) {
+	var silences []alertModel.AlertSilence
+	db := postgres.DB.Order("id desc")
+	if active {
+		now := time.Now()
+		db = db.Where("starts_at <= ? AND ends_at > ?", now, now)
+	}
+	err := db.Find(&silences).Error
+	return silences, err
+}
+
+func GetSilence(id interface{}) (alertModel.AlertSilence, error) {
+	var silence alertModel.AlertSilence
+	err := postgres.DB.First(&silence, id).Error
+	return silence, err
+}
+
+func CreateSilence(req SilenceRequest, operator string) (alertModel.AlertSilence, error) {
+	silence := alertModel.AlertSilence{CreatedBy: operator}
+	if err := applySilence(&silence, req); err != nil {
+		return silence, err
+	}
+	err := postgres.DB.Create(&silence).Error
+	return silence, err
+}
+
+func UpdateSilence(id interface{}, req SilenceRequest) (alertModel.AlertSilence, error) {
+	silence, err := GetSilence(id)
+	if err != nil {
+		return silence, err
+	}
+	if err := applySilence(&silence, req); err != nil {
+		return silence, err
+	}
+	err = postgres.DB.Save(&silence).Error
+	return silence, err
+}
+
+func DeleteSilence(id interface{}) error {
+	return postgres.DB.Delete(&alertModel.AlertSilence{}, id).Error
+}
+
+func applySilence(silence *alertModel.AlertSilence, req SilenceRequest) error {
+	// 没有匹配条件的静默会屏蔽全部告警，不允许创建
+	if len(req.Matchers) == 0 {
+		return fmt.Errorf("%w: matchers 不能为空", ErrInvalidSilence)
+	}
+	for i := range req.Matchers {
+		m := &req.Matchers[i]
+		m.Name = strings.TrimSpace(m.Name)
+		if !silenceFields[m.Name] {
+			return fmt.Errorf("%w: 不支持的匹配字段 %s", ErrInvalidSilence, m.Name)
+		}
+		if m.Regex {
+			if _, err := regexp.Compile(m.Value); err != nil {
+				return fmt.Errorf("%w: 正则表达式无效 %s", ErrInvalidSilence, m.Value)
+			}
+		}
+	}
+	startsAt := time.Now()
+	if req.StartsAt != nil {
+		startsAt = *req.StartsAt
+	}
+	if !req.EndsAt.After(startsAt) {
+		return fmt.Errorf("%w: ends_at 需晚于 starts_at", ErrInvalidSilence)
+	}
+	data, _ := json.Marshal(req.Matchers)
+	silence.Comment = req.Comment
+	silence.Matchers = string(data)
+	silence.StartsAt = startsAt
+	silence.EndsAt = req.EndsAt
+	return nil
+}
+
+// alertLabels 告警记录中可供静默匹配的字段
+func alertLabels(record alertModel.AlertEvent) map[string]string {
+	labels := map[string]string{
+		"rule_id":        fmt.Sprint(record.RuleID),
+		"rule_name":      record.RuleName,
+		"condition_type": record.ConditionType,
+		"severity":       record.Severity,
+		"task_name":      record.TaskName,
+		"task_type":      record.TaskType,
+	}
+	if record.TaskID != nil {
+		labels["task_id"] = fmt.Sprint(*record.TaskID)
+	}
+	if record.Source != "" {
+		labels["source"] = record.Source
+	}
+	return labels
+}
+
+// findSilence 返回命中告警的生效静默
+func findSilence(db *gorm.DB, labels map[string]string, now time.Time) (*alertModel.AlertSilence, error) {
+	var silences []alertModel.AlertSilence
+	if err := db.Where("starts_at <= ? AND ends_at > ?", now, now).Order("id").Find(&silences).Error; err != nil {
+		return nil, err
+	}
+	for i := range silences {
+		if matchSilence(silences[i].Matchers, labels) {
+			return &silences[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// matchSilence 判断告警是否满足静默的全部匹配条件
+func matchSilence(raw string, labels map[string]string) bool {
+	var matchers []Matcher
+	if err := json.Unmarshal([]byte(raw), &matchers); err != nil || len(matchers) == 0 {
+		return false
+	}
+	for _, m := range matchers {
+		value := labels[m.Name]
+		if m.Regex {
+			re, err := regexp.Compile("^(?:" + m.Value + ")$")
+			if err != nil || !re.MatchString(value) {
+				return false
+			}
+		} else if value != m.Value {
+			return false
+		}
+	}
+	return true
+}
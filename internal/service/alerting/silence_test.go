@@ -0,0 +1,27 @@
+package alerting
+
+import "testing"
+
+func TestMatchSilence(t *testing.T) {
+	labels := map[string]string{"task_id": "3", "task_name": "ods_orders", "severity": "critical"}
+	tests := []struct {
+		name     string
+		matchers string
+		want     bool
+	}{
+		{name: "equal", matchers: `[{"name":"task_id","value":"3"}]`, want: true},
+		{name: "all matchers required", matchers: `[{"name":"task_id","value":"3"},{"name":"severity","value":"warning"}]`, want: false},
+		{name: "regex", matchers: `[{"name":"task_name","value":"ods_.*","regex":true}]`, want: true},
+		{name: "regex is anchored", matchers: `[{"name":"task_name","value":"orders","regex":true}]`, want: false},
+		{name: "missing label", matchers: `[{"name":"task_type","value":"stream"}]`, want: false},
+		{name: "empty matchers", matchers: `[]`, want: false},
+		{name: "invalid json", matchers: `{`, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchSilence(tt.matchers, labels); got != tt.want {
+				t.Errorf("matchSilence() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package aliyun
+
+import (
+	"fmt"
+
+	openapi "github.com/alibabacloud-go/darabonba-openapi/client"
+	util "github.com/alibabacloud-go/tea-utils/service"
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+const (
+	dysmsEndpoint = "dysmsapi.aliyuncs.com"
+	dyvmsEndpoint = "dyvmsapi.aliyuncs.com"
+)
+
+// SendSms 发送短信，phones 为逗号分隔的手机号，templateParam 为 JSON 字符串
+func SendSms(cfg *openapi.Config, phones, signName, templateCode, templateParam string) error {
+	return callRPC(cfg, dysmsEndpoint, "SendSms", map[string]string{
+		"PhoneNumbers":  phones,
+		"SignName":      signName,
+		"TemplateCode":  templateCode,
+		"TemplateParam": templateParam,
+	})
+}
+
+// SingleCallByTts 发起文本转语音通知，一次呼叫一个号码
+func SingleCallByTts(cfg *openapi.Config, calledShowNumber, phone, ttsCode, ttsParam string) error {
+	query := map[string]string{
+		"CalledNumber": phone,
+		"TtsCode":      ttsCode,
+		"TtsParam":     ttsParam,
+	}
+	if calledShowNumber != "" {
+		query["CalledShowNumber"] = calledShowNumber
+	}
+	return callRPC(cfg, dyvmsEndpoint, "SingleCallByTts", query)
+}
+
+// callRPC 以 RPC 风格调用短信/语音服务（版本 2017-05-25），cfg 未指定 Endpoint 时使用默认地址
+func callRPC(cfg *openapi.Config, endpoint, action string, query map[string]string) error {
+	c := *cfg
+	if tea.StringValue(c.Endpoint) == "" {
+		c.Endpoint = tea.String(endpoint)
+	}
+	client, err := openapi.NewClient(&c)
+	if err != nil {
+		return err
+	}
+	protocol := "HTTPS"
+	if tea.StringValue(c.Protocol) != "" {
+		protocol = tea.StringValue(c.Protocol)
+	}
+	params := &openapi.Params{
+		Action:      tea.String(action),
+		Version:     tea.String("2017-05-25"),
+		Protocol:    tea.String(protocol),
+		Pathname:    tea.String("/"),
+		Method:      tea.String("POST"),
+		AuthType:    tea.String("AK"),
+		Style:       tea.String("RPC"),
+		ReqBodyType: tea.String("json"),
+		BodyType:    tea.String("json"),
+	}
+	req := &openapi.OpenApiRequest{Query: map[string]*string{}}
+	for k, v := range query {
+		req.Query[k] = tea.String(v)
+	}
+	resp, err := client.CallApi(params, req, &util.RuntimeOptions{})
+	if err != nil {
+		return err
+	}
+	body, _ := resp["body"].(map[string]interface{})
+	if code, _ := body["Code"].(string); code != "OK" {
+		return fmt.Errorf("%s 调用失败: %v %v", action, body["Code"], body["Message"])
+	}
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 	"gorm.io/gorm"
 
 	"log"
+	"octoops/internal/event"
 	"octoops/internal/infra/postgres"
 	aliyunModel "octoops/internal/model/aliyun"
 	"octoops/internal/utils"
@@ -63,6 +64,15 @@ func Initialization(cfg *aliyunModel.SGConfig) (*ecs.Client, error) {
 		}
 		return nil, fmt.Errorf("ECS客户端初始化失败: %v", err)
 	}
+	openCfg, err := NewOpenAPIConfig(ak, sk, cfg.RegionId)
+	if err != nil {
+		return nil, err
+	}
+	return ecs.NewClient(openCfg)
+}
+
+// NewOpenAPIConfig 以 AccessKey 构造阿里云 OpenAPI 客户端配置，sk 为明文
+func NewOpenAPIConfig(ak, sk, regionId string) (*openapi.Config, error) {
 	config := new(credential.Config).
 		SetType("access_key").
 		SetAccessKeyId(ak).
@@ -71,11 +81,10 @@ func Initialization(cfg *aliyunModel.SGConfig) (*ecs.Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	openCfg := &openapi.Config{
+	return &openapi.Config{
 		Credential: cred,
-		RegionId:   tea.String(cfg.RegionId),
-	}
-	return ecs.NewClient(openCfg)
+		RegionId:   tea.String(regionId),
+	}, nil
 }
 
 // 授权安全组（官方示例风格）
@@ -158,6 +167,7 @@ func UpdateSecurityGroupIfIPChanged(db *gorm.DB) error {
 	}
 
 	// 1. 撤销oldIP下所有tcp规则
+	var revoked []string
 	if oldIP != "" {
 		resp, err := DescribeSecurityGroupAttribute(client, cfg)
 		if err != nil {
@@ -175,6 +185,7 @@ func UpdateSecurityGroupIfIPChanged(db *gorm.DB) error {
 					}
 					return fmt.Errorf("端口范围%s撤销旧授权失败: %v", portRange, err)
 				}
+				revoked = append(revoked, portRange)
 			}
 		}
 	}
@@ -191,6 +202,19 @@ func UpdateSecurityGroupIfIPChanged(db *gorm.DB) error {
 		"last_ip":            newIP,
 		"last_ip_updated_at": time.Now(),
 	})
+	if oldIP != newIP {
+		event.PublishDurable(event.SGRuleChanged{
+			ConfigID:        cfg.ID,
+			ConfigName:      cfg.Name,
+			RegionID:        cfg.RegionId,
+			SecurityGroupID: cfg.SecurityGroupId,
+			OldIP:           oldIP,
+			NewIP:           newIP,
+			Ports:           portList,
+			Revoked:         revoked,
+			At:              time.Now(),
+		})
+	}
 	return nil
 }
 
@@ -220,11 +244,28 @@ func GetSecurityGroupDetailString(client *ecs.Client, cfg *aliyunModel.SGConfig)
 }
 **/
 
-// 批量同步所有ECS安全组配置
-func SyncAllECSSecurityGroups() error {
+// SGSyncFailure 单个安全组配置的同步失败原因
+type SGSyncFailure struct {
+	ConfigID uint   `json:"config_id"`
+	Name     string `json:"name"`
+	Error    string `json:"error"`
+}
+
+// SGSyncReport 安全组批量同步结果
+type SGSyncReport struct {
+	Total     int             `json:"total"`
+	Succeeded int             `json:"succeeded"`
+	Failed    []SGSyncFailure `json:"failed"`
+}
+
+// 批量同步所有ECS安全组配置，返回各配置的同步结果
+func SyncAllECSSecurityGroups() (SGSyncReport, error) {
 	var configs []aliyunModel.SGConfig
 	dbIns := postgres.DB
-	dbIns.Where("status != 0").Find(&configs)
+	if err := dbIns.Where("status != 0").Find(&configs).Error; err != nil {
+		return SGSyncReport{}, fmt.Errorf("查询安全组配置失败: %v", err)
+	}
+	report := SGSyncReport{Total: len(configs), Failed: []SGSyncFailure{}}
 	var failed []string
 	for _, cfg := range configs {
 		ins := dbIns.Session(&gorm.Session{}).Model(&aliyunModel.SGConfig{}).Where("id = ?", cfg.ID)
@@ -232,22 +273,25 @@ func SyncAllECSSecurityGroups() error {
 		if err != nil {
 			log.Printf("[ECS SG Sync] 配置ID=%d 同步失败: %v", cfg.ID, err)
 			failed = append(failed, fmt.Sprintf("ID=%d: %v", cfg.ID, err))
+			report.Failed = append(report.Failed, SGSyncFailure{ConfigID: cfg.ID, Name: cfg.Name, Error: err.Error()})
+			continue
 		}
+		report.Succeeded++
 	}
 	if len(failed) > 0 {
-		return fmt.Errorf("部分安全组同步失败: %v", failed)
+		return report, fmt.Errorf("部分安全组同步失败: %v", failed)
 	}
-	return nil
+	return report, nil
 }
 
-// 封装统一同步函数
-func SyncECSSecurityGroups() string {
+// 封装统一同步函数，返回同步结果与错误
+func SyncECSSecurityGroups() (SGSyncReport, error) {
 	log.Printf("[Scheduler] 开始同步ECS安全组")
-	err := SyncAllECSSecurityGroups()
+	report, err := SyncAllECSSecurityGroups()
 	if err != nil {
 		log.Printf("[Scheduler] ECS安全组同步失败: %v", err)
-		return "ECS安全组同步失败: " + err.Error()
+		return report, err
 	}
 	log.Printf("[Scheduler] ECS安全组同步完成")
-	return "ECS安全组同步完成"
+	return report, nil
 }
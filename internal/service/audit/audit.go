@@ -0,0 +1,24 @@
+package audit
+
+import (
+	"log"
+	"octoops/internal/event"
+	"strconv"
+	"strings"
+)
+
+// RegisterEventHandlers 订阅登录、安全组变更等安全相关事件并记录审计日志
+func RegisterEventHandlers() {
+	event.On("audit.user_logged_in", func(ev event.UserLoggedIn) error {
+		log.Printf("[Audit] 用户登录: userID=%d, username=%s, ip=%s, at=%s", ev.UserID, ev.Username, ev.IP, ev.At.Format("2006-01-02 15:04:05"))
+		return nil
+	})
+	event.On("audit.sg_rule_changed", func(ev event.SGRuleChanged) error {
+		ports := make([]string, 0, len(ev.Ports))
+		for _, p := range ev.Ports {
+			ports = append(ports, strconv.Itoa(p))
+		}
+		log.Printf("[Audit] 安全组授权变更: configID=%d, name=%s, oldIP=%s, newIP=%s, ports=%s", ev.ConfigID, ev.ConfigName, ev.OldIP, ev.NewIP, strings.Join(ports, ","))
+		return nil
+	})
+}
@@ -0,0 +1,527 @@
+package bulk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"octoops/internal/infra/postgres"
+	seatunnelModel "octoops/internal/model/seatunnel"
+	"octoops/internal/scheduler"
+	seatunnelService "octoops/internal/service/seatunnel"
+	"octoops/internal/utils"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	ActionStart   = "start"
+	ActionStop    = "stop"
+	ActionRestart = "restart"
+	ActionEnable  = "enable"
+	ActionDisable = "disable"
+	ActionDelete  = "delete"
+)
+
+const (
+	StatusRunning  = "running"
+	StatusCanceled = "canceled"
+	StatusFinished = "finished"
+)
+
+const (
+	ItemStatusPending   = "pending"
+	ItemStatusRunning   = "running"
+	ItemStatusSucceeded = "succeeded"
+	ItemStatusFailed    = "failed"
+	ItemStatusSkipped   = "skipped"
+	ItemStatusCanceled  = "canceled"
+)
+
+const (
+	defaultConcurrency = 5
+	maxConcurrency     = 20
+	// 执行实例的租约时间，按 1/3 租约间隔续期；租约过期的操作由其他实例接管
+	runnerLease = 2 * time.Minute
+)
+
+var (
+	ErrInvalidRequest = errors.New("无效的批量操作请求")
+	ErrNoTasks        = errors.New("没有匹配的任务")
+	ErrInvalidState   = errors.New("批量操作当前状态不允许该操作")
+)
+
+// actionPermissions 批量操作对应的单任务权限动作，校验时拼接为 etl:<任务类型>:<动作>
+var actionPermissions = map[string][]string{
+	ActionStart:   {"submit"},
+	ActionStop:    {"stop"},
+	ActionRestart: {"stop", "submit"},
+	ActionEnable:  {"update"},
+	ActionDisable: {"update"},
+	ActionDelete:  {"delete"},
+}
+
+// Filter 按条件筛选批量操作的目标任务
+type Filter struct {
+	TaskType   string `json:"task_type"`
+	Name       string `json:"name"`
+	Status     *int   `json:"status"`
+	JobStatus  string `json:"job_status"`
+	Cluster    string `json:"cluster"`
+	TemplateID *uint  `json:"template_id"`
+}
+
+// Request 创建批量操作请求，task_ids 与 filter 至少提供一个
+type Request struct {
+	Action        string  `json:"action" binding:"required"`
+	TaskIDs       []uint  `json:"task_ids"`
+	Filter        *Filter `json:"filter"`
+	Concurrency   int     `json:"concurrency"`
+	WithSavePoint bool    `json:"with_savepoint"` // 停止/启动流式任务时使用 SavePoint
+	Force         bool    `json:"force"`          // 允许修改 GitOps 托管的任务
+}
+
+type runner struct {
+	cancel context.CancelFunc
+}
+
+var runners = struct {
+	sync.Mutex
+	runners map[uint]*runner
+}{runners: map[uint]*runner{}}
+
+var (
+	// runnerID 当前实例标识，写入认领的批量操作
+	runnerID   = newRunnerID()
+	resumeOnce sync.Once
+)
+
+func newRunnerID() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s:%d:%d", host, os.Getpid(), time.Now().UnixNano())
+}
+
+// Create 创建批量操作并异步执行，permitted 用于校验当前用户对单个任务的操作权限
+func Create(req Request, operator string, permitted func(code string) bool) (seatunnelModel.BulkOperation, error) {
+	if _, ok := actionPermissions[req.Action]; !ok {
+		return seatunnelModel.BulkOperation{}, fmt.Errorf("%w: 不支持的操作 %s", ErrInvalidRequest, req.Action)
+	}
+	tasks, err := resolveTasks(req)
+	if err != nil {
+		return seatunnelModel.BulkOperation{}, err
+	}
+	if len(tasks) == 0 {
+		return seatunnelModel.BulkOperation{}, ErrNoTasks
+	}
+	if req.Concurrency <= 0 {
+		req.Concurrency = defaultConcurrency
+	}
+	if req.Concurrency > maxConcurrency {
+		req.Concurrency = maxConcurrency
+	}
+	options, _ := json.Marshal(req)
+
+	leaseUntil := time.Now().Add(runnerLease)
+	op := seatunnelModel.BulkOperation{
+		Action:      req.Action,
+		Status:      StatusRunning,
+		Options:     string(options),
+		Concurrency: req.Concurrency,
+		Total:       len(tasks),
+		CreatedBy:   operator,
+		Runner:      runnerID,
+		LeaseUntil:  &leaseUntil,
+	}
+	if err := postgres.DB.Create(&op).Error; err != nil {
+		return op, err
+	}
+	items := make([]seatunnelModel.BulkOperationItem, 0, len(tasks))
+	for _, task := range tasks {
+		item := seatunnelModel.BulkOperationItem{
+			OperationID: op.ID,
+			TaskID:      task.ID,
+			TaskName:    task.Name,
+			TaskType:    task.TaskType,
+			Status:      ItemStatusPending,
+		}
+		for _, action := range actionPermissions[req.Action] {
+			if !permitted(fmt.Sprintf("etl:%s:%s", task.TaskType, action)) {
+				item.Status = ItemStatusSkipped
+				item.Message = "权限不足"
+				break
+			}
+		}
+		items = append(items, item)
+	}
+	if err := postgres.DB.CreateInBatches(&items, 200).Error; err != nil {
+		return op, err
+	}
+	log.Printf("[Bulk] 创建批量操作: id=%d, action=%s, total=%d, operator=%s", op.ID, op.Action, op.Total, operator)
+	startRunner(op.ID)
+	return op, nil
+}
+
+func resolveTasks(req Request) ([]seatunnelModel.EtlTask, error) {
+	var tasks []seatunnelModel.EtlTask
+	query := postgres.DB.Order("id asc")
+	switch {
+	case len(req.TaskIDs) > 0:
+		query = query.Where("id IN ?", req.TaskIDs)
+	case req.Filter != nil:
+		f := req.Filter
+		if f.TaskType == "" && f.Name == "" && f.Status == nil && f.JobStatus == "" && f.Cluster == "" && f.TemplateID == nil {
+			return nil, fmt.Errorf("%w: filter 至少需要一个条件", ErrInvalidRequest)
+		}
+		if f.TaskType != "" {
+			query = query.Where("task_type = ?", f.TaskType)
+		}
+		if f.Name != "" {
+			query = query.Where(`name LIKE ? ESCAPE '\'`, "%"+utils.EscapeLike(f.Name)+"%")
+		}
+		if f.Status != nil {
+			query = query.Where("status = ?", *f.Status)
+		}
+		if f.JobStatus != "" {
+			query = query.Where("job_status = ?", f.JobStatus)
+		}
+		if f.Cluster != "" {
+			query = query.Where("cluster = ?", f.Cluster)
+		}
+		if f.TemplateID != nil {
+			query = query.Where("template_id = ?", *f.TemplateID)
+		}
+	default:
+		return nil, fmt.Errorf("%w: task_ids 与 filter 至少提供一个", ErrInvalidRequest)
+	}
+	err := query.Find(&tasks).Error
+	return tasks, err
+}
+
+// List 分页查询批量操作
+func List(page, pageSize int) ([]seatunnelModel.BulkOperation, int64, error) {
+	var ops []seatunnelModel.BulkOperation
+	var total int64
+	query := postgres.DB.Model(&seatunnelModel.BulkOperation{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Order("id desc").Limit(pageSize).Offset((page - 1) * pageSize).Find(&ops).Error
+	return ops, total, err
+}
+
+// Get 查询批量操作及各任务执行结果
+func Get(id interface{}) (seatunnelModel.BulkOperation, []seatunnelModel.BulkOperationItem, error) {
+	var op seatunnelModel.BulkOperation
+	if err := postgres.DB.First(&op, id).Error; err != nil {
+		return op, nil, err
+	}
+	var items []seatunnelModel.BulkOperationItem
+	err := postgres.DB.Where("operation_id = ?", op.ID).Order("id asc").Find(&items).Error
+	return op, items, err
+}
+
+// Cancel 取消批量操作，未开始的任务标记为已取消，执行中的任务会完成当前步骤
+func Cancel(id uint) error {
+	res := postgres.DB.Model(&seatunnelModel.BulkOperation{}).
+		Where("id = ? AND status = ?", id, StatusRunning).
+		Updates(map[string]interface{}{"status": StatusCanceled, "finished_at": time.Now()})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrInvalidState
+	}
+	stopRunner(id)
+	postgres.DB.Model(&seatunnelModel.BulkOperationItem{}).
+		Where("operation_id = ? AND status = ?", id, ItemStatusPending).
+		Updates(map[string]interface{}{"status": ItemStatusCanceled, "message": "批量操作已取消"})
+	refreshProgress(id)
+	return nil
+}
+
+// ResumeOperations 服务启动时接管租约已过期的批量操作，并定期检查其他实例崩溃后遗留的操作；
+// 其他实例仍在执行（租约未过期）的操作不受影响
+func ResumeOperations() {
+	resumeOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(runnerLease)
+			defer ticker.Stop()
+			for {
+				resumeStaleOperations()
+				<-ticker.C
+			}
+		}()
+	})
+}
+
+func resumeStaleOperations() {
+	var ops []seatunnelModel.BulkOperation
+	if err := postgres.DB.Where("status = ? AND (lease_until IS NULL OR lease_until <= ?)", StatusRunning, time.Now()).
+		Find(&ops).Error; err != nil {
+		log.Printf("[Bulk] 加载批量操作失败: %v", err)
+		return
+	}
+	resumed := 0
+	for _, op := range ops {
+		if !acquireLease(op.ID) {
+			continue
+		}
+		// 原执行实例已失联，其执行中的任务结果未知，标记为失败
+		postgres.DB.Model(&seatunnelModel.BulkOperationItem{}).
+			Where("operation_id = ? AND status = ?", op.ID, ItemStatusRunning).
+			Updates(map[string]interface{}{"status": ItemStatusFailed, "message": "执行实例中断", "finished_at": time.Now()})
+		startRunner(op.ID)
+		resumed++
+	}
+	if resumed > 0 {
+		log.Printf("[Bulk] 接管批量操作 %d 个", resumed)
+	}
+}
+
+// acquireLease 认领租约已过期的批量操作，多个实例同时接管时只有一个成功
+func acquireLease(id uint) bool {
+	now := time.Now()
+	res := postgres.DB.Model(&seatunnelModel.BulkOperation{}).
+		Where("id = ? AND status = ? AND (lease_until IS NULL OR lease_until <= ?)", id, StatusRunning, now).
+		Updates(map[string]interface{}{"runner": runnerID, "lease_until": now.Add(runnerLease)})
+	if res.Error != nil {
+		log.Printf("[Bulk] 认领批量操作失败: id=%d, error=%v", id, res.Error)
+		return false
+	}
+	return res.RowsAffected > 0
+}
+
+// renewLease 续期当前实例持有的租约，返回 false 表示租约已被其他实例接管或操作已结束
+func renewLease(id uint) bool {
+	res := postgres.DB.Model(&seatunnelModel.BulkOperation{}).
+		Where("id = ? AND status = ? AND runner = ?", id, StatusRunning, runnerID).
+		Update("lease_until", time.Now().Add(runnerLease))
+	if res.Error != nil {
+		// 数据库暂时不可用时继续执行，租约过期前恢复即可
+		log.Printf("[Bulk] 续期批量操作租约失败: id=%d, error=%v", id, res.Error)
+		return true
+	}
+	return res.RowsAffected > 0
+}
+
+// keepLease 执行期间定期续期，租约丢失时取消本实例的执行
+func keepLease(ctx context.Context, id uint, cancel context.CancelFunc) {
+	ticker := time.NewTicker(runnerLease / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !renewLease(id) {
+				log.Printf("[Bulk] 批量操作租约已失效，停止执行: id=%d", id)
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+func startRunner(id uint) {
+	runners.Lock()
+	defer runners.Unlock()
+	if _, exists := runners.runners[id]; exists {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &runner{cancel: cancel}
+	runners.runners[id] = r
+	go run(ctx, id, r)
+}
+
+func stopRunner(id uint) {
+	runners.Lock()
+	r, exists := runners.runners[id]
+	delete(runners.runners, id)
+	runners.Unlock()
+	if exists {
+		r.cancel()
+	}
+}
+
+func run(ctx context.Context, id uint, r *runner) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			log.Printf("[Bulk][Panic] operationID=%d, err=%v", id, rec)
+		}
+		runners.Lock()
+		if runners.runners[id] == r {
+			delete(runners.runners, id)
+		}
+		runners.Unlock()
+		r.cancel()
+	}()
+
+	var op seatunnelModel.BulkOperation
+	if err := postgres.DB.First(&op, id).Error; err != nil {
+		return
+	}
+	go keepLease(ctx, id, r.cancel)
+	var req Request
+	_ = json.Unmarshal([]byte(op.Options), &req)
+	var items []seatunnelModel.BulkOperationItem
+	postgres.DB.Where("operation_id = ? AND status = ?", id, ItemStatusPending).Order("id asc").Find(&items)
+
+	sem := make(chan struct{}, op.Concurrency)
+	var wg sync.WaitGroup
+	for _, item := range items {
+		select {
+		case <-ctx.Done():
+		case sem <- struct{}{}:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(item seatunnelModel.BulkOperationItem) {
+			defer func() {
+				if rec := recover(); rec != nil {
+					log.Printf("[Bulk][Panic] operationID=%d, taskID=%d, err=%v", id, item.TaskID, rec)
+				}
+				<-sem
+				wg.Done()
+			}()
+			if executeItem(req, item) {
+				refreshProgress(id)
+			}
+		}(item)
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		// 被取消或租约已被其他实例接管，由取消方或新的执行实例负责收尾
+		return
+	}
+	refreshProgress(id)
+	res := postgres.DB.Model(&seatunnelModel.BulkOperation{}).
+		Where("id = ? AND status = ? AND runner = ?", id, StatusRunning, runnerID).
+		Updates(map[string]interface{}{"status": StatusFinished, "finished_at": time.Now(), "lease_until": nil})
+	if res.RowsAffected > 0 {
+		log.Printf("[Bulk] 批量操作完成: id=%d, action=%s", id, op.Action)
+	}
+}
+
+// executeItem 认领并执行单个任务的操作，回写结果；任务已被取消或由其他实例认领时返回 false
+func executeItem(req Request, item seatunnelModel.BulkOperationItem) bool {
+	res := postgres.DB.Model(&seatunnelModel.BulkOperationItem{}).
+		Where("id = ? AND status = ?", item.ID, ItemStatusPending).
+		Updates(map[string]interface{}{"status": ItemStatusRunning, "started_at": time.Now()})
+	if res.Error != nil {
+		log.Printf("[Bulk] 认领批量操作任务失败: item=%d, error=%v", item.ID, res.Error)
+		return false
+	}
+	if res.RowsAffected == 0 {
+		return false
+	}
+
+	jobStatus, message, status := "", "", ItemStatusSucceeded
+	task, err := seatunnelService.GetTaskByID(item.TaskID)
+	if err != nil {
+		status, message = ItemStatusFailed, "任务不存在"
+	} else {
+		jobStatus, message, err = applyAction(req, task)
+		switch {
+		case errors.Is(err, errSkipped):
+			status = ItemStatusSkipped
+		case err != nil:
+			status, message = ItemStatusFailed, err.Error()
+		}
+	}
+	if runes := []rune(message); len(runes) > 500 {
+		message = string(runes[:500])
+	}
+	// 执行期间被判定为实例中断的任务不再覆盖
+	postgres.DB.Model(&seatunnelModel.BulkOperationItem{}).
+		Where("id = ? AND status = ?", item.ID, ItemStatusRunning).
+		Updates(map[string]interface{}{
+			"status":      status,
+			"job_status":  jobStatus,
+			"message":     message,
+			"finished_at": time.Now(),
+		})
+	return true
+}
+
+var errSkipped = errors.New("skipped")
+
+// applyAction 对单个任务执行批量操作，跳过时返回 errSkipped 及原因
+func applyAction(req Request, task seatunnelModel.EtlTask) (string, string, error) {
+	switch req.Action {
+	case ActionStart:
+		jobStatus, _, err := seatunnelService.StartTask(task, req.WithSavePoint)
+		return jobStatus, "", err
+	case ActionStop:
+		if task.JobStatus != "RUNNING" {
+			return task.JobStatus, "作业未运行", errSkipped
+		}
+		jobStatus, _, _, err := seatunnelService.StopTask(task, req.WithSavePoint)
+		return jobStatus, "", err
+	case ActionRestart:
+		if task.JobStatus == "RUNNING" {
+			if _, _, _, err := seatunnelService.StopTask(task, req.WithSavePoint); err != nil {
+				return "", "", fmt.Errorf("停止作业失败: %v", err)
+			}
+			var err error
+			if task, err = seatunnelService.GetTaskByID(task.ID); err != nil {
+				return "", "", err
+			}
+		} else {
+			// 未运行的作业没有可用的 SavePoint，直接正常启动
+			req.WithSavePoint = false
+		}
+		jobStatus, _, err := seatunnelService.StartTask(task, req.WithSavePoint)
+		return jobStatus, "", err
+	case ActionEnable, ActionDisable:
+		if task.Managed && !req.Force {
+			return "", "任务由 GitOps 目录管理", errSkipped
+		}
+		status := 0
+		if req.Action == ActionEnable {
+			status = 1
+		}
+		if err := seatunnelService.UpdateTask(&task, map[string]interface{}{"status": status}); err != nil {
+			return "", "", err
+		}
+		task.Status = status
+		scheduler.RefreshTask(task)
+		return task.JobStatus, "", nil
+	case ActionDelete:
+		if task.Managed && !req.Force {
+			return "", "任务由 GitOps 目录管理", errSkipped
+		}
+		scheduler.RemoveTask(task.ID)
+		return "", "", seatunnelService.DeleteTask(&task)
+	}
+	return "", "", fmt.Errorf("不支持的操作: %s", req.Action)
+}
+
+func refreshProgress(id uint) {
+	type countRow struct {
+		Status string
+		Count  int
+	}
+	var rows []countRow
+	postgres.DB.Model(&seatunnelModel.BulkOperationItem{}).
+		Select("status, count(*) as count").
+		Where("operation_id = ?", id).
+		Group("status").Scan(&rows)
+	updates := map[string]interface{}{"succeeded": 0, "failed": 0, "skipped": 0}
+	for _, row := range rows {
+		switch row.Status {
+		case ItemStatusSucceeded:
+			updates["succeeded"] = row.Count
+		case ItemStatusFailed:
+			updates["failed"] = row.Count
+		case ItemStatusSkipped, ItemStatusCanceled:
+			updates["skipped"] = updates["skipped"].(int) + row.Count
+		}
+	}
+	postgres.DB.Model(&seatunnelModel.BulkOperation{}).Where("id = ?", id).Updates(updates)
+}
@@ -0,0 +1,704 @@
+package bundle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"octoops/internal/infra/postgres"
+	alertModel "octoops/internal/model/alert"
+	seatunnelModel "octoops/internal/model/seatunnel"
+	taskModel "octoops/internal/model/task"
+	alertService "octoops/internal/service/alert"
+	seatunnelService "octoops/internal/service/seatunnel"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+// CurrentVersion 当前导出包版本，结构不兼容变更时递增
+const CurrentVersion = 1
+
+const (
+	ConflictSkip      = "skip"
+	ConflictOverwrite = "overwrite"
+	ConflictRename    = "rename"
+)
+
+const (
+	ActionCreate    = "create"
+	ActionOverwrite = "overwrite"
+	ActionSkip      = "skip"
+	ActionRename    = "rename"
+)
+
+var (
+	ErrUnsupportedVersion = errors.New("不支持的导出包版本")
+	ErrInvalidConflict    = errors.New("无效的冲突处理方式")
+)
+
+// Bundle 任务与告警配置导出包，对象间通过名称引用，导入时重新映射 ID
+type Bundle struct {
+	Version        int                 `json:"version" yaml:"version"`
+	ExportedAt     time.Time           `json:"exported_at" yaml:"exported_at"`
+	EtlTasks       []EtlTaskSpec       `json:"etl_tasks" yaml:"etl_tasks"`
+	CustomTasks    []CustomTaskSpec    `json:"custom_tasks" yaml:"custom_tasks"`
+	AlertGroups    []AlertGroupSpec    `json:"alert_groups" yaml:"alert_groups"`
+	AlertChannels  []AlertChannelSpec  `json:"alert_channels" yaml:"alert_channels"`
+	AlertTemplates []AlertTemplateSpec `json:"alert_templates" yaml:"alert_templates"`
+}
+
+type EtlTaskSpec struct {
+	Name            string   `json:"name" yaml:"name"`
+	Description     string   `json:"description" yaml:"description"`
+	TaskType        string   `json:"task_type" yaml:"task_type"`
+	CronExpr        string   `json:"cron_expr" yaml:"cron_expr"`
+	Config          string   `json:"config" yaml:"config"`
+	ConfigFormat    string   `json:"config_format" yaml:"config_format"`
+	EncryptConfig   bool     `json:"encrypt_config" yaml:"encrypt_config"`
+	ShadeIdentifier string   `json:"shade_identifier" yaml:"shade_identifier"`
+	ConfigEncrypted bool     `json:"config_encrypted" yaml:"config_encrypted"`
+	ConfigRedacted  bool     `json:"config_redacted,omitempty" yaml:"config_redacted,omitempty"` // 明文配置中的敏感字段已脱敏
+	AlertGroups     []string `json:"alert_groups" yaml:"alert_groups"`                           // 告警组名称
+	Cluster         string   `json:"cluster,omitempty" yaml:"cluster,omitempty"`
+	Status          int      `json:"status" yaml:"status"`
+}
+
+type CustomTaskSpec struct {
+	Name          string `json:"name" yaml:"name"`
+	CustomType    string `json:"custom_type" yaml:"custom_type"`
+	CronExpr      string `json:"cron_expr" yaml:"cron_expr"`
+	Description   string `json:"description" yaml:"description"`
+	Status        int    `json:"status" yaml:"status"`
+	MaxRetries    int    `json:"max_retries,omitempty" yaml:"max_retries,omitempty"`
+	RetryInterval int    `json:"retry_interval,omitempty" yaml:"retry_interval,omitempty"`
+}
+
+type AlertGroupSpec struct {
+	Name            string            `json:"name" yaml:"name"`
+	Description     string            `json:"description" yaml:"description"`
+	Status          int               `json:"status" yaml:"status"`
+	Members         []GroupMemberSpec `json:"members" yaml:"members"`
+	DigestWindow    int               `json:"digest_window,omitempty" yaml:"digest_window,omitempty"`
+	DigestGroupBy   string            `json:"digest_group_by,omitempty" yaml:"digest_group_by,omitempty"`
+	DigestTemplate  string            `json:"digest_template,omitempty" yaml:"digest_template,omitempty"` // 模板名称，为空使用内置模板
+	SummaryTime     string            `json:"summary_time,omitempty" yaml:"summary_time,omitempty"`
+	SummaryTemplate string            `json:"summary_template,omitempty" yaml:"summary_template,omitempty"` // 模板名称，为空使用内置模板
+}
+
+type GroupMemberSpec struct {
+	ChannelType string `json:"channel_type" yaml:"channel_type"`
+	Channel     string `json:"channel" yaml:"channel"` // 渠道名称
+}
+
+type AlertChannelSpec struct {
+	Name           string `json:"name" yaml:"name"`
+	Type           string `json:"type" yaml:"type"`
+	Target         string `json:"target" yaml:"target"`
+	Secret         string `json:"secret,omitempty" yaml:"secret,omitempty"` // 导出时脱敏为空，导入时为空则保留原密钥
+	Config         string `json:"config,omitempty" yaml:"config,omitempty"`
+	ConfigRedacted bool   `json:"config_redacted,omitempty" yaml:"config_redacted,omitempty"` // 配置中的请求头等敏感字段已脱敏
+	Status         int    `json:"status" yaml:"status"`
+	Template       string `json:"template" yaml:"template"` // 模板名称
+	MaxAttempts    int    `json:"max_attempts,omitempty" yaml:"max_attempts,omitempty"`
+	RetryInterval  int    `json:"retry_interval,omitempty" yaml:"retry_interval,omitempty"`
+}
+
+type AlertTemplateSpec struct {
+	Name     string                                `json:"name" yaml:"name"`
+	Kind     string                                `json:"kind,omitempty" yaml:"kind,omitempty"` // 为空表示 alert
+	Title    string                                `json:"title,omitempty" yaml:"title,omitempty"`
+	Content  string                                `json:"content" yaml:"content"`
+	Variants map[string]alertModel.TemplateVariant `json:"variants,omitempty" yaml:"variants,omitempty"`
+}
+
+// ImportItem 导入计划中的单个对象
+type ImportItem struct {
+	Kind       string   `json:"kind"`
+	Name       string   `json:"name"`
+	Action     string   `json:"action"`
+	TargetName string   `json:"target_name"`
+	TargetID   uint     `json:"target_id"`
+	Warnings   []string `json:"warnings,omitempty"`
+}
+
+// ImportResult 导入结果，dry_run 时仅包含计划
+type ImportResult struct {
+	DryRun   bool         `json:"dry_run"`
+	Conflict string       `json:"conflict"`
+	Items    []ImportItem `json:"items"`
+	Created  int          `json:"created"`
+	Updated  int          `json:"updated"`
+	Skipped  int          `json:"skipped"`
+}
+
+// Export 导出全部 ETL 任务、自定义任务及告警配置，渠道密钥与未加密任务配置中的敏感字段脱敏
+func Export() (*Bundle, error) {
+	b := &Bundle{Version: CurrentVersion, ExportedAt: time.Now()}
+
+	var templates []alertModel.AlertTemplate
+	if err := postgres.DB.Order("id asc").Find(&templates).Error; err != nil {
+		return nil, err
+	}
+	templateNames := map[uint]string{}
+	for _, t := range templates {
+		templateNames[t.ID] = t.Name
+		b.AlertTemplates = append(b.AlertTemplates, AlertTemplateSpec{Name: t.Name, Kind: t.Kind, Title: t.Title, Content: t.Content, Variants: t.Variants})
+	}
+
+	var channels []alertModel.AlertChannel
+	if err := postgres.DB.Order("id asc").Find(&channels).Error; err != nil {
+		return nil, err
+	}
+	channelNames := map[uint]string{}
+	for _, ch := range channels {
+		channelNames[ch.ID] = ch.Name
+		spec := AlertChannelSpec{
+			Name:          ch.Name,
+			Type:          ch.Type,
+			Target:        ch.Target,
+			Status:        ch.Status,
+			Template:      templateNames[ch.TemplateID],
+			MaxAttempts:   ch.MaxAttempts,
+			RetryInterval: ch.RetryInterval,
+		}
+		if ch.Config != "" {
+			spec.Config, spec.ConfigRedacted = redactChannelConfig(ch.Config)
+		}
+		b.AlertChannels = append(b.AlertChannels, spec)
+	}
+
+	var groups []alertModel.AlertGroup
+	if err := postgres.DB.Order("id asc").Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	groupNames := map[string]string{}
+	for _, g := range groups {
+		groupNames[strconv.FormatUint(uint64(g.ID), 10)] = g.Name
+		var members []alertModel.AlertGroupMember
+		postgres.DB.Where("group_id = ?", g.ID).Order("id asc").Find(&members)
+		spec := AlertGroupSpec{
+			Name:            g.Name,
+			Description:     g.Description,
+			Status:          g.Status,
+			DigestWindow:    g.DigestWindow,
+			DigestGroupBy:   g.DigestGroupBy,
+			DigestTemplate:  templateNames[g.DigestTemplateID],
+			SummaryTime:     g.SummaryTime,
+			SummaryTemplate: templateNames[g.SummaryTemplateID],
+		}
+		for _, m := range members {
+			// 用户与值班表成员依赖当前环境的账号，不随配置包迁移
+			if m.ChannelType == alertModel.MemberTypeUser || m.ChannelType == alertModel.MemberTypeOnCall {
+				continue
+			}
+			if name, ok := channelNames[m.ChannelID]; ok {
+				spec.Members = append(spec.Members, GroupMemberSpec{ChannelType: m.ChannelType, Channel: name})
+			}
+		}
+		b.AlertGroups = append(b.AlertGroups, spec)
+	}
+
+	var tasks []seatunnelModel.EtlTask
+	if err := postgres.DB.Order("id asc").Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	for _, t := range tasks {
+		spec := EtlTaskSpec{
+			Name:            t.Name,
+			Description:     t.Description,
+			TaskType:        t.TaskType,
+			CronExpr:        t.CronExpr,
+			Config:          t.Config,
+			ConfigFormat:    t.ConfigFormat,
+			EncryptConfig:   t.EncryptConfig,
+			ShadeIdentifier: t.ShadeIdentifier,
+			ConfigEncrypted: t.ConfigEncrypted,
+			Cluster:         t.Cluster,
+			Status:          t.Status,
+		}
+		if !t.ConfigEncrypted {
+			spec.Config, spec.ConfigRedacted = redactConfig(t.Config)
+		}
+		for _, gid := range splitIDs(t.AlertGroup) {
+			if name, ok := groupNames[gid]; ok {
+				spec.AlertGroups = append(spec.AlertGroups, name)
+			}
+		}
+		b.EtlTasks = append(b.EtlTasks, spec)
+	}
+
+	var customTasks []taskModel.CustomTask
+	if err := postgres.DB.Order("id asc").Find(&customTasks).Error; err != nil {
+		return nil, err
+	}
+	for _, t := range customTasks {
+		b.CustomTasks = append(b.CustomTasks, CustomTaskSpec{
+			Name:          t.Name,
+			CustomType:    t.CustomType,
+			CronExpr:      t.CronExpr,
+			Description:   t.Description,
+			Status:        t.Status,
+			MaxRetries:    t.MaxRetries,
+			RetryInterval: t.RetryInterval,
+		})
+	}
+	return b, nil
+}
+
+// Marshal 按格式序列化导出包，format 为 yaml 或 json
+func Marshal(b *Bundle, format string) ([]byte, error) {
+	if format == "json" {
+		return json.MarshalIndent(b, "", "  ")
+	}
+	return yaml.Marshal(b)
+}
+
+// Parse 解析导出包，YAML 兼容 JSON 输入
+func Parse(data []byte) (*Bundle, error) {
+	var b Bundle
+	if err := yaml.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("解析导出包失败: %v", err)
+	}
+	if b.Version != CurrentVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, b.Version)
+	}
+	return &b, nil
+}
+
+// Import 按名称匹配导入导出包，conflict 决定同名对象的处理方式，dryRun 时只生成计划不写库
+func Import(b *Bundle, conflict string, dryRun bool) (*ImportResult, error) {
+	switch conflict {
+	case "":
+		conflict = ConflictSkip
+	case ConflictSkip, ConflictOverwrite, ConflictRename:
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidConflict, conflict)
+	}
+	result := &ImportResult{DryRun: dryRun, Conflict: conflict}
+	err := postgres.DB.Transaction(func(tx *gorm.DB) error {
+		im := &importer{tx: tx, bundle: b, conflict: conflict, dryRun: dryRun, result: result,
+			templateIDs: map[string]uint{}, channelIDs: map[string]uint{}, groupIDs: map[string]uint{}}
+		for _, step := range []func() error{im.importTemplates, im.importChannels, im.importGroups, im.importEtlTasks, im.importCustomTasks} {
+			if err := step(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range result.Items {
+		switch item.Action {
+		case ActionCreate, ActionRename:
+			result.Created++
+		case ActionOverwrite:
+			result.Updated++
+		case ActionSkip:
+			result.Skipped++
+		}
+	}
+	return result, nil
+}
+
+type importer struct {
+	tx       *gorm.DB
+	bundle   *Bundle
+	conflict string
+	dryRun   bool
+	result   *ImportResult
+
+	// 导出包中的名称 -> 本环境 ID，重命名后仍按原名称解析引用
+	templateIDs map[string]uint
+	channelIDs  map[string]uint
+	groupIDs    map[string]uint
+}
+
+// plan 根据冲突策略决定单个对象的动作，existingID 为 0 表示无同名对象
+func (im *importer) plan(kind, name string, existingID uint, exists func(string) bool) ImportItem {
+	item := ImportItem{Kind: kind, Name: name, TargetName: name, TargetID: existingID}
+	if existingID == 0 {
+		item.Action = ActionCreate
+		return item
+	}
+	switch im.conflict {
+	case ConflictOverwrite:
+		item.Action = ActionOverwrite
+	case ConflictRename:
+		item.Action = ActionRename
+		item.TargetID = 0
+		item.TargetName = uniqueName(name, exists)
+	default:
+		item.Action = ActionSkip
+	}
+	return item
+}
+
+func (im *importer) add(item ImportItem) {
+	im.result.Items = append(im.result.Items, item)
+}
+
+// findID 按条件查找本环境中已有对象的 ID，不存在时返回 0
+func (im *importer) findID(model interface{}, query string, args ...interface{}) uint {
+	var id uint
+	im.tx.Model(model).Select("id").Where(query, args...).Order("id asc").Limit(1).Scan(&id)
+	return id
+}
+
+// resolve 解析名称引用：优先使用本次导入的映射，其次匹配本环境同名对象
+func (im *importer) resolve(ids map[string]uint, model interface{}, name string) (uint, bool) {
+	if id, ok := ids[name]; ok {
+		return id, true
+	}
+	id := im.findID(model, "name = ?", name)
+	return id, id != 0
+}
+
+func (im *importer) importTemplates() error {
+	model := &alertModel.AlertTemplate{}
+	exists := func(n string) bool { return im.findID(model, "name = ?", n) != 0 }
+	for _, spec := range im.bundle.AlertTemplates {
+		kind := spec.Kind
+		if kind == "" {
+			kind = alertService.TemplateKindAlert
+		}
+		if err := alertService.ValidateTemplate(alertModel.AlertTemplate{Kind: kind, Title: spec.Title, Content: spec.Content, Variants: spec.Variants}); err != nil {
+			return fmt.Errorf("模板 %s: %w", spec.Name, err)
+		}
+		item := im.plan("alert_template", spec.Name, im.findID(model, "name = ?", spec.Name), exists)
+		if !im.dryRun {
+			switch item.Action {
+			case ActionCreate, ActionRename:
+				tpl := alertModel.AlertTemplate{Name: item.TargetName, Kind: kind, Title: spec.Title, Content: spec.Content, Variants: spec.Variants}
+				if err := im.tx.Create(&tpl).Error; err != nil {
+					return fmt.Errorf("导入模板 %s 失败: %v", spec.Name, err)
+				}
+				item.TargetID = tpl.ID
+			case ActionOverwrite:
+				tpl := alertModel.AlertTemplate{Kind: kind, Title: spec.Title, Content: spec.Content, Variants: spec.Variants}
+				if err := im.tx.Model(model).Where("id = ?", item.TargetID).Select("kind", "title", "content", "variants").Updates(&tpl).Error; err != nil {
+					return fmt.Errorf("覆盖模板 %s 失败: %v", spec.Name, err)
+				}
+			}
+		}
+		im.templateIDs[spec.Name] = item.TargetID
+		im.add(item)
+	}
+	return nil
+}
+
+func (im *importer) importChannels() error {
+	model := &alertModel.AlertChannel{}
+	exists := func(n string) bool { return im.findID(model, "name = ?", n) != 0 }
+	for _, spec := range im.bundle.AlertChannels {
+		item := im.plan("alert_channel", spec.Name, im.findID(model, "name = ?", spec.Name), exists)
+		var templateID uint
+		if spec.Template != "" {
+			id, ok := im.resolve(im.templateIDs, &alertModel.AlertTemplate{}, spec.Template)
+			if !ok {
+				item.Warnings = append(item.Warnings, "模板不存在: "+spec.Template)
+			}
+			templateID = id
+		}
+		if (spec.Type == "dingtalk" || spec.Type == "feishu" || spec.Type == "sms" || spec.Type == "voice") && spec.Secret == "" && item.Action != ActionSkip && item.Action != ActionOverwrite {
+			item.Warnings = append(item.Warnings, "渠道密钥已脱敏，导入后需补充")
+		}
+		if spec.ConfigRedacted {
+			switch item.Action {
+			case ActionCreate, ActionRename:
+				item.Warnings = append(item.Warnings, "渠道配置中的请求头等敏感字段已脱敏，导入后需补充")
+			case ActionOverwrite:
+				item.Warnings = append(item.Warnings, "渠道配置中的请求头等敏感字段已脱敏，已保留本环境原有配置")
+			}
+		}
+		if !im.dryRun {
+			secret, err := alertService.EncryptChannelSecret(spec.Type, spec.Secret)
+			if err != nil {
+				return fmt.Errorf("导入渠道 %s 失败: %v", spec.Name, err)
+			}
+			switch item.Action {
+			case ActionCreate, ActionRename:
+				ch := alertModel.AlertChannel{
+					Name:           item.TargetName,
+					Type:           spec.Type,
+					Target:         spec.Target,
+					DingtalkSecret: secret,
+					Config:         spec.Config,
+					Status:         spec.Status,
+					TemplateID:     templateID,
+					MaxAttempts:    spec.MaxAttempts,
+					RetryInterval:  spec.RetryInterval,
+				}
+				if err := im.tx.Create(&ch).Error; err != nil {
+					return fmt.Errorf("导入渠道 %s 失败: %v", spec.Name, err)
+				}
+				item.TargetID = ch.ID
+			case ActionOverwrite:
+				updates := map[string]interface{}{
+					"type":           spec.Type,
+					"target":         spec.Target,
+					"status":         spec.Status,
+					"template_id":    templateID,
+					"max_attempts":   spec.MaxAttempts,
+					"retry_interval": spec.RetryInterval,
+				}
+				// 脱敏导出的配置不含请求头等敏感值，覆盖时保留本环境原有配置
+				if !spec.ConfigRedacted {
+					updates["config"] = spec.Config
+				}
+				// 脱敏导出的密钥为空，覆盖时保留本环境原有密钥
+				if spec.Secret != "" {
+					updates["dingtalk_secret"] = secret
+				}
+				if err := im.tx.Model(model).Where("id = ?", item.TargetID).Updates(updates).Error; err != nil {
+					return fmt.Errorf("覆盖渠道 %s 失败: %v", spec.Name, err)
+				}
+			}
+		}
+		im.channelIDs[spec.Name] = item.TargetID
+		im.add(item)
+	}
+	return nil
+}
+
+func (im *importer) importGroups() error {
+	model := &alertModel.AlertGroup{}
+	exists := func(n string) bool { return im.findID(model, "name = ?", n) != 0 }
+	for _, spec := range im.bundle.AlertGroups {
+		item := im.plan("alert_group", spec.Name, im.findID(model, "name = ?", spec.Name), exists)
+		var members []alertModel.AlertGroupMember
+		for _, m := range spec.Members {
+			id, ok := im.resolve(im.channelIDs, &alertModel.AlertChannel{}, m.Channel)
+			if !ok {
+				item.Warnings = append(item.Warnings, "渠道不存在，已忽略成员: "+m.Channel)
+				continue
+			}
+			members = append(members, alertModel.AlertGroupMember{ChannelType: m.ChannelType, ChannelID: id})
+		}
+		templateID := func(name string) uint {
+			if name == "" {
+				return 0
+			}
+			id, ok := im.resolve(im.templateIDs, &alertModel.AlertTemplate{}, name)
+			if !ok {
+				item.Warnings = append(item.Warnings, "模板不存在，已改用内置模板: "+name)
+			}
+			return id
+		}
+		digestTemplateID, summaryTemplateID := templateID(spec.DigestTemplate), templateID(spec.SummaryTemplate)
+		if !im.dryRun {
+			switch item.Action {
+			case ActionCreate, ActionRename:
+				group := alertModel.AlertGroup{
+					Name:              item.TargetName,
+					Description:       spec.Description,
+					Status:            spec.Status,
+					DigestWindow:      spec.DigestWindow,
+					DigestGroupBy:     spec.DigestGroupBy,
+					DigestTemplateID:  digestTemplateID,
+					SummaryTime:       spec.SummaryTime,
+					SummaryTemplateID: summaryTemplateID,
+				}
+				if err := im.tx.Create(&group).Error; err != nil {
+					return fmt.Errorf("导入告警组 %s 失败: %v", spec.Name, err)
+				}
+				item.TargetID = group.ID
+			case ActionOverwrite:
+				if err := im.tx.Model(model).Where("id = ?", item.TargetID).Updates(map[string]interface{}{
+					"description":         spec.Description,
+					"status":              spec.Status,
+					"digest_window":       spec.DigestWindow,
+					"digest_group_by":     spec.DigestGroupBy,
+					"digest_template_id":  digestTemplateID,
+					"summary_time":        spec.SummaryTime,
+					"summary_template_id": summaryTemplateID,
+				}).Error; err != nil {
+					return fmt.Errorf("覆盖告警组 %s 失败: %v", spec.Name, err)
+				}
+				if err := im.tx.Where("group_id = ? AND channel_type NOT IN ?", item.TargetID,
+					[]string{alertModel.MemberTypeUser, alertModel.MemberTypeOnCall}).Delete(&alertModel.AlertGroupMember{}).Error; err != nil {
+					return fmt.Errorf("清理告警组 %s 成员失败: %v", spec.Name, err)
+				}
+			}
+			if item.Action != ActionSkip {
+				for i := range members {
+					members[i].GroupID = item.TargetID
+					if err := im.tx.Create(&members[i]).Error; err != nil {
+						return fmt.Errorf("导入告警组 %s 成员失败: %v", spec.Name, err)
+					}
+				}
+			}
+		}
+		im.groupIDs[spec.Name] = item.TargetID
+		im.add(item)
+	}
+	return nil
+}
+
+func (im *importer) importEtlTasks() error {
+	model := &seatunnelModel.EtlTask{}
+	for _, spec := range im.bundle.EtlTasks {
+		if spec.TaskType != "stream" && spec.TaskType != "batch" {
+			im.add(ImportItem{Kind: "etl_task", Name: spec.Name, Action: ActionSkip, TargetName: spec.Name,
+				Warnings: []string{"无效的任务类型: " + spec.TaskType}})
+			continue
+		}
+		exists := func(n string) bool { return im.findID(model, "name = ? AND task_type = ?", n, spec.TaskType) != 0 }
+		item := im.plan("etl_task", spec.Name, im.findID(model, "name = ? AND task_type = ?", spec.Name, spec.TaskType), exists)
+		if item.Action == ActionOverwrite {
+			var managed bool
+			im.tx.Model(model).Select("managed").Where("id = ?", item.TargetID).Scan(&managed)
+			if managed {
+				item.Action = ActionSkip
+				item.Warnings = append(item.Warnings, "任务由 GitOps 目录管理，已跳过覆盖")
+			}
+		}
+		if spec.ConfigRedacted {
+			switch item.Action {
+			case ActionCreate, ActionRename:
+				item.Warnings = append(item.Warnings, "配置敏感字段已脱敏，导入后需补充")
+			case ActionOverwrite:
+				item.Warnings = append(item.Warnings, "配置敏感字段已脱敏，已保留本环境原有配置")
+			}
+		}
+		var groupIDs []string
+		for _, name := range spec.AlertGroups {
+			id, ok := im.resolve(im.groupIDs, &alertModel.AlertGroup{}, name)
+			if !ok {
+				item.Warnings = append(item.Warnings, "告警组不存在，已忽略: "+name)
+				continue
+			}
+			groupIDs = append(groupIDs, strconv.FormatUint(uint64(id), 10))
+		}
+		alertGroup := strings.Join(groupIDs, ",")
+		if !im.dryRun {
+			switch item.Action {
+			case ActionCreate, ActionRename:
+				task := seatunnelModel.EtlTask{
+					Name:            item.TargetName,
+					Description:     spec.Description,
+					TaskType:        spec.TaskType,
+					CronExpr:        spec.CronExpr,
+					Config:          spec.Config,
+					ConfigFormat:    spec.ConfigFormat,
+					EncryptConfig:   spec.EncryptConfig,
+					ShadeIdentifier: spec.ShadeIdentifier,
+					AlertGroup:      alertGroup,
+					Cluster:         spec.Cluster,
+					Status:          spec.Status,
+				}
+				if err := prepareImportedConfig(&task, spec.ConfigEncrypted); err != nil {
+					return fmt.Errorf("加密任务 %s 配置失败: %v", spec.Name, err)
+				}
+				if err := seatunnelService.CreateTaskTx(im.tx, &task); err != nil {
+					return fmt.Errorf("导入任务 %s 失败: %v", spec.Name, err)
+				}
+				item.TargetID = task.ID
+			case ActionOverwrite:
+				var task seatunnelModel.EtlTask
+				if err := im.tx.First(&task, item.TargetID).Error; err != nil {
+					return fmt.Errorf("查询任务 %s 失败: %v", spec.Name, err)
+				}
+				updates := map[string]interface{}{
+					"description":      spec.Description,
+					"cron_expr":        spec.CronExpr,
+					"config_format":    spec.ConfigFormat,
+					"encrypt_config":   spec.EncryptConfig,
+					"shade_identifier": spec.ShadeIdentifier,
+					"alert_group":      alertGroup,
+					"cluster":          spec.Cluster,
+					"status":           spec.Status,
+				}
+				// 脱敏导出的配置不含敏感参数，覆盖时保留本环境原有配置
+				if !spec.ConfigRedacted {
+					merged := task
+					merged.Config = spec.Config
+					merged.ConfigFormat = spec.ConfigFormat
+					merged.EncryptConfig = spec.EncryptConfig
+					merged.ShadeIdentifier = spec.ShadeIdentifier
+					merged.Cluster = spec.Cluster
+					if err := prepareImportedConfig(&merged, spec.ConfigEncrypted); err != nil {
+						return fmt.Errorf("加密任务 %s 配置失败: %v", spec.Name, err)
+					}
+					updates["config"] = merged.Config
+					updates["config_encrypted"] = merged.ConfigEncrypted
+				}
+				if err := seatunnelService.UpdateTaskTx(im.tx, &task, updates); err != nil {
+					return fmt.Errorf("覆盖任务 %s 失败: %v", spec.Name, err)
+				}
+			}
+		}
+		im.add(item)
+	}
+	return nil
+}
+
+func (im *importer) importCustomTasks() error {
+	model := &taskModel.CustomTask{}
+	exists := func(n string) bool { return im.findID(model, "name = ?", n) != 0 }
+	for _, spec := range im.bundle.CustomTasks {
+		item := im.plan("custom_task", spec.Name, im.findID(model, "name = ?", spec.Name), exists)
+		if !im.dryRun {
+			switch item.Action {
+			case ActionCreate, ActionRename:
+				task := taskModel.CustomTask{
+					Name:          item.TargetName,
+					CustomType:    spec.CustomType,
+					CronExpr:      spec.CronExpr,
+					Description:   spec.Description,
+					Status:        spec.Status,
+					MaxRetries:    spec.MaxRetries,
+					RetryInterval: spec.RetryInterval,
+				}
+				if err := im.tx.Create(&task).Error; err != nil {
+					return fmt.Errorf("导入自定义任务 %s 失败: %v", spec.Name, err)
+				}
+				item.TargetID = task.ID
+			case ActionOverwrite:
+				if err := im.tx.Model(model).Where("id = ?", item.TargetID).Updates(map[string]interface{}{
+					"custom_type":    spec.CustomType,
+					"cron_expr":      spec.CronExpr,
+					"description":    spec.Description,
+					"status":         spec.Status,
+					"max_retries":    spec.MaxRetries,
+					"retry_interval": spec.RetryInterval,
+				}).Error; err != nil {
+					return fmt.Errorf("覆盖自定义任务 %s 失败: %v", spec.Name, err)
+				}
+			}
+		}
+		im.add(item)
+	}
+	return nil
+}
+
+// prepareImportedConfig 导出包中已是密文的配置原样保存，明文配置按任务设置加密
+func prepareImportedConfig(task *seatunnelModel.EtlTask, encrypted bool) error {
+	if encrypted {
+		task.ConfigEncrypted = true
+		return nil
+	}
+	return seatunnelService.PrepareTaskConfig(task)
+}
+
+func splitIDs(s string) []string {
+	var ids []string
+	for _, id := range strings.Split(s, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// uniqueName 为重命名导入生成不冲突的名称
+func uniqueName(name string, exists func(string) bool) string {
+	candidate := name + "-imported"
+	for i := 2; exists(candidate); i++ {
+		candidate = fmt.Sprintf("%s-imported-%d", name, i)
+	}
+	return candidate
+}
@@ -0,0 +1,70 @@
+package bundle
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// RedactedValue 导出时替换明文敏感字段的占位值
+const RedactedValue = "******"
+
+// 匹配 JSON/HOCON 中键名含 password、secret、token 等的字段及其取值
+var sensitiveFieldPattern = regexp.MustCompile(`("?[A-Za-z0-9_.-]*(?i:password|passwd|secret|token|access[_.-]?key|credential)[A-Za-z0-9_.-]*"?\s*[:=]\s*)("(?:[^"\\]|\\.)*"|[^\s,"}\]]+)`)
+
+// 渠道配置中键名视为敏感的字段
+var sensitiveKeyPattern = regexp.MustCompile(`(?i)password|passwd|secret|token|access[_.-]?key|credential`)
+
+// redactConfig 将未加密配置中的明文敏感字段替换为占位值，数据源引用与运行变量保持原样
+func redactConfig(config string) (string, bool) {
+	redacted := false
+	result := sensitiveFieldPattern.ReplaceAllStringFunc(config, func(field string) string {
+		m := sensitiveFieldPattern.FindStringSubmatch(field)
+		value := strings.Trim(m[2], `"`)
+		if value == "" || value == RedactedValue || strings.Contains(value, "${") {
+			return field
+		}
+		redacted = true
+		return m[1] + `"` + RedactedValue + `"`
+	})
+	return result, redacted
+}
+
+// redactChannelConfig 将告警渠道 JSON 配置中的请求头（如 Authorization）及键名敏感的字段替换为占位值，
+// 请求体模板等其余字段保持原样；非 JSON 配置按 redactConfig 处理
+func redactChannelConfig(config string) (string, bool) {
+	var cfg map[string]interface{}
+	if err := json.Unmarshal([]byte(config), &cfg); err != nil {
+		return redactConfig(config)
+	}
+	if !redactValues(cfg, false) {
+		return config, false
+	}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return redactConfig(config)
+	}
+	return string(data), true
+}
+
+// redactValues 递归脱敏 m 中的字符串值；all 为 true 时（请求头）脱敏全部值，否则只脱敏键名敏感的字段
+func redactValues(m map[string]interface{}, all bool) bool {
+	redacted := false
+	for k, v := range m {
+		switch val := v.(type) {
+		case map[string]interface{}:
+			if redactValues(val, all || strings.EqualFold(k, "headers")) {
+				redacted = true
+			}
+		case string:
+			if val == "" || val == RedactedValue || strings.Contains(val, "${") {
+				continue
+			}
+			if all || sensitiveKeyPattern.MatchString(k) {
+				m[k] = RedactedValue
+				redacted = true
+			}
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,83 @@
+package bundle
+
+import "testing"
+
+func TestRedactConfig(t *testing.T) {
+	cases := []struct {
+		name     string
+		config   string
+		want     string
+		redacted bool
+	}{
+		{
+			name:     "json password",
+			config:   `{"source":[{"url":"jdbc:mysql://db:3306/a","user":"root","password":"p@ss\"w"}]}`,
+			want:     `{"source":[{"url":"jdbc:mysql://db:3306/a","user":"root","password":"******"}]}`,
+			redacted: true,
+		},
+		{
+			name:     "hocon unquoted",
+			config:   "sink {\n  access_key = AKID123\n  secret_key = \"abc\"\n}",
+			want:     "sink {\n  access_key = \"******\"\n  secret_key = \"******\"\n}",
+			redacted: true,
+		},
+		{
+			name:   "datasource reference kept",
+			config: `{"password":"${datasource.mysql_prod.password}"}`,
+			want:   `{"password":"${datasource.mysql_prod.password}"}`,
+		},
+		{
+			name:   "empty value kept",
+			config: `{"password":""}`,
+			want:   `{"password":""}`,
+		},
+		{
+			name:   "no sensitive field",
+			config: `{"user":"root","table":"orders"}`,
+			want:   `{"user":"root","table":"orders"}`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, redacted := redactConfig(c.config)
+			if got != c.want || redacted != c.redacted {
+				t.Fatalf("redactConfig() = %q, %v; want %q, %v", got, redacted, c.want, c.redacted)
+			}
+		})
+	}
+}
+
+func TestRedactChannelConfig(t *testing.T) {
+	cases := []struct {
+		name     string
+		config   string
+		want     string
+		redacted bool
+	}{
+		{
+			name:     "webhook headers",
+			config:   `{"body_template":"{\"text\": {{ json .Content }}}","headers":{"Authorization":"Bearer abc","X-Env":"prod"}}`,
+			want:     `{"body_template":"{\"text\": {{ json .Content }}}","headers":{"Authorization":"******","X-Env":"******"}}`,
+			redacted: true,
+		},
+		{
+			name:     "sensitive key",
+			config:   `{"provider":"http","url":"https://sms.example.com","api_token":"t-1"}`,
+			want:     `{"api_token":"******","provider":"http","url":"https://sms.example.com"}`,
+			redacted: true,
+		},
+		{
+			name:   "nothing sensitive kept verbatim",
+			config: `{"provider":"aliyun", "sign_name":"OctoOps","headers":{}}`,
+			want:   `{"provider":"aliyun", "sign_name":"OctoOps","headers":{}}`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, redacted := redactChannelConfig(c.config)
+			if got != c.want || redacted != c.redacted {
+				t.Errorf("redactChannelConfig() = (%s, %v), want (%s, %v)", got, redacted, c.want, c.redacted)
+			}
+		})
+	}
+}
@@ -0,0 +1,377 @@
+package gitops
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"octoops/internal/config"
+	"octoops/internal/infra/postgres"
+	alertModel "octoops/internal/model/alert"
+	seatunnelModel "octoops/internal/model/seatunnel"
+	bundleService "octoops/internal/service/bundle"
+	seatunnelService "octoops/internal/service/seatunnel"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ActionCreate    = "create"
+	ActionUpdate    = "update"
+	ActionDisable   = "disable"
+	ActionUnchanged = "unchanged"
+	ActionError     = "error"
+)
+
+var (
+	ErrGitOpsDisabled   = errors.New("未配置 GitOps 目录")
+	ErrReconcileRunning = errors.New("已有 GitOps 同步在进行中")
+)
+
+var reconcileMu sync.Mutex
+
+// DriftItem 单个定义文件或托管任务的同步结果
+type DriftItem struct {
+	File     string   `json:"file"`
+	TaskID   uint     `json:"task_id"`
+	TaskName string   `json:"task_name"`
+	TaskType string   `json:"task_type"`
+	Action   string   `json:"action"`
+	Fields   []string `json:"fields,omitempty"` // 发生漂移的字段
+	Message  string   `json:"message,omitempty"`
+}
+
+type taskFile struct {
+	path string
+	spec bundleService.EtlTaskSpec
+	err  error
+}
+
+// Reconcile 按目录中的定义文件同步 ETL 任务：新建、更新或禁用任务，并生成漂移报告
+// 返回发生变更的任务，供调用方刷新调度
+func Reconcile(trigger string) (*seatunnelModel.GitOpsReport, []seatunnelModel.EtlTask, error) {
+	dir := config.GetGitOpsConfig().Dir
+	if dir == "" {
+		return nil, nil, ErrGitOpsDisabled
+	}
+	if !reconcileMu.TryLock() {
+		return nil, nil, ErrReconcileRunning
+	}
+	defer reconcileMu.Unlock()
+
+	report := &seatunnelModel.GitOpsReport{Dir: dir, Trigger: trigger, StartedAt: time.Now()}
+	var items []DriftItem
+	var changed []seatunnelModel.EtlTask
+
+	files, readErr := loadTaskFiles(dir)
+	if readErr != nil {
+		report.Status = "failed"
+		report.Message = readErr.Error()
+		return finishReport(report, items), nil, nil
+	}
+
+	seen := map[uint]bool{}
+	fileErrors := 0
+	for _, f := range files {
+		item, task, err := reconcileFile(f, seen)
+		if err != nil {
+			item.Action = ActionError
+			item.Message = err.Error()
+			fileErrors++
+			if task == nil {
+				// 文件解析或校验失败时仍视为存在，避免对应托管任务被当作已删除而禁用
+				task = managedTaskBySource(f.path)
+			}
+		}
+		if task != nil {
+			seen[task.ID] = true
+			if item.Action == ActionCreate || item.Action == ActionUpdate {
+				changed = append(changed, *task)
+				if task.Status == 0 && slices.Contains(item.Fields, "status") {
+					stopDisabledJob(*task, &item)
+				}
+			}
+		}
+		items = append(items, item)
+	}
+
+	// 定义文件已删除的托管任务禁用但保留托管标记，恢复文件后自动重新启用；
+	// 存在同步错误或目录中没有定义文件时跳过，避免误禁用
+	skipDisable := fileErrors > 0 || len(files) == 0
+	var managed []seatunnelModel.EtlTask
+	if !skipDisable {
+		postgres.DB.Where("managed = ?", true).Order("id asc").Find(&managed)
+	}
+	for _, task := range managed {
+		if seen[task.ID] {
+			continue
+		}
+		item := DriftItem{File: task.ManagedSource, TaskID: task.ID, TaskName: task.Name, TaskType: task.TaskType, Action: ActionUnchanged}
+		if task.Status != 0 {
+			if err := postgres.DB.Model(&task).Update("status", 0).Error; err != nil {
+				item.Action = ActionError
+				item.Message = err.Error()
+			} else {
+				item.Action = ActionDisable
+				item.Fields = []string{"status"}
+				item.Message = "定义文件已删除"
+				task.Status = 0
+				changed = append(changed, task)
+				stopDisabledJob(task, &item)
+			}
+		}
+		items = append(items, item)
+	}
+
+	for _, item := range items {
+		switch item.Action {
+		case ActionCreate:
+			report.Created++
+		case ActionUpdate:
+			report.Updated++
+		case ActionDisable:
+			report.Disabled++
+		case ActionUnchanged:
+			report.Unchanged++
+		case ActionError:
+			report.Errors++
+		}
+	}
+	report.Status = "success"
+	if report.Errors > 0 {
+		report.Status = "partial"
+	}
+	report.Message = fmt.Sprintf("新建 %d, 更新 %d, 禁用 %d, 无变化 %d, 错误 %d",
+		report.Created, report.Updated, report.Disabled, report.Unchanged, report.Errors)
+	if skipDisable {
+		report.Message += "；存在同步错误或目录中没有定义文件，已跳过禁用已删除文件对应的任务"
+	}
+	return finishReport(report, items), changed, nil
+}
+
+func finishReport(report *seatunnelModel.GitOpsReport, items []DriftItem) *seatunnelModel.GitOpsReport {
+	now := time.Now()
+	report.FinishedAt = &now
+	if items == nil {
+		items = []DriftItem{}
+	}
+	details, _ := json.Marshal(items)
+	report.Details = string(details)
+	if err := postgres.DB.Create(report).Error; err != nil {
+		log.Printf("[GitOps] 保存同步报告失败: %v", err)
+	}
+	log.Printf("[GitOps] 同步完成: dir=%s, status=%s, %s", report.Dir, report.Status, report.Message)
+	return report
+}
+
+// managedTaskBySource 按定义文件路径查找托管任务，不存在时返回 nil
+func managedTaskBySource(path string) *seatunnelModel.EtlTask {
+	var task seatunnelModel.EtlTask
+	if err := postgres.DB.Where("managed = ? AND managed_source = ?", true, path).First(&task).Error; err != nil {
+		return nil
+	}
+	return &task
+}
+
+// stopDisabledJob 停止被禁用的实时任务仍在运行的作业，带 SavePoint 以便重新启用后恢复
+func stopDisabledJob(task seatunnelModel.EtlTask, item *DriftItem) {
+	if task.TaskType != "stream" || task.JobStatus != "RUNNING" {
+		return
+	}
+	if _, _, _, err := seatunnelService.StopTask(task, true); err != nil {
+		log.Printf("[GitOps] 停止已禁用任务的作业失败: taskID=%d, error=%v", task.ID, err)
+		item.Message = strings.TrimPrefix(item.Message+"；停止作业失败: "+err.Error(), "；")
+		return
+	}
+	item.Message = strings.TrimPrefix(item.Message+"；已停止运行中的作业", "；")
+}
+
+// loadTaskFiles 读取目录下所有 yaml/yml/json 定义文件，每个文件对应一个 ETL 任务
+func loadTaskFiles(dir string) ([]taskFile, error) {
+	var files []taskFile
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml", ".json":
+		default:
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, path)
+		files = append(files, taskFile{path: filepath.ToSlash(rel)})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("读取 GitOps 目录失败: %v", err)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+	for i := range files {
+		data, err := os.ReadFile(filepath.Join(dir, files[i].path))
+		if err != nil {
+			files[i].err = fmt.Errorf("读取文件失败: %v", err)
+			continue
+		}
+		if err := yaml.Unmarshal(data, &files[i].spec); err != nil {
+			files[i].err = fmt.Errorf("解析文件失败: %v", err)
+		}
+	}
+	return files, nil
+}
+
+// reconcileFile 同步单个定义文件，返回同步结果及对应任务，seen 为本轮已同步的任务
+func reconcileFile(f taskFile, seen map[uint]bool) (DriftItem, *seatunnelModel.EtlTask, error) {
+	spec := f.spec
+	item := DriftItem{File: f.path, TaskName: spec.Name, TaskType: spec.TaskType}
+	if f.err != nil {
+		return item, nil, f.err
+	}
+	if spec.Name == "" {
+		spec.Name = strings.TrimSuffix(filepath.Base(f.path), filepath.Ext(f.path))
+		item.TaskName = spec.Name
+	}
+	if spec.TaskType != "stream" && spec.TaskType != "batch" {
+		return item, nil, fmt.Errorf("无效的任务类型: %s", spec.TaskType)
+	}
+	if err := seatunnelService.ValidateCluster(spec.Cluster); err != nil {
+		return item, nil, err
+	}
+	alertGroup, missing := resolveAlertGroups(spec.AlertGroups)
+	if len(missing) > 0 {
+		item.Message = "告警组不存在，已忽略: " + strings.Join(missing, ",")
+	}
+
+	var task seatunnelModel.EtlTask
+	err := postgres.DB.Where("managed = ? AND managed_source = ?", true, f.path).First(&task).Error
+	if err != nil {
+		// 首次同步时按名称接管已有的同名任务
+		err = postgres.DB.Where("name = ? AND task_type = ?", spec.Name, spec.TaskType).Order("id asc").First(&task).Error
+	}
+	if err != nil {
+		task = seatunnelModel.EtlTask{
+			Name:            spec.Name,
+			Description:     spec.Description,
+			TaskType:        spec.TaskType,
+			CronExpr:        spec.CronExpr,
+			Config:          spec.Config,
+			ConfigFormat:    spec.ConfigFormat,
+			EncryptConfig:   spec.EncryptConfig,
+			ShadeIdentifier: spec.ShadeIdentifier,
+			AlertGroup:      alertGroup,
+			Cluster:         spec.Cluster,
+			Status:          spec.Status,
+			Managed:         true,
+			ManagedSource:   f.path,
+		}
+		if err := seatunnelService.CreateTask(&task); err != nil {
+			return item, nil, fmt.Errorf("创建任务失败: %v", err)
+		}
+		item.TaskID = task.ID
+		item.Action = ActionCreate
+		return item, &task, nil
+	}
+	item.TaskID = task.ID
+	if seen[task.ID] {
+		return item, nil, fmt.Errorf("与其他定义文件指向同一任务")
+	}
+	if task.TaskType != spec.TaskType {
+		return item, &task, fmt.Errorf("任务类型不允许变更: %s -> %s", task.TaskType, spec.TaskType)
+	}
+
+	// 文件中为明文配置，托管任务在提交时按需加密，存储时不保存密文以便比对漂移
+	desired := map[string]interface{}{
+		"name":             spec.Name,
+		"description":      spec.Description,
+		"cron_expr":        spec.CronExpr,
+		"config":           spec.Config,
+		"config_format":    spec.ConfigFormat,
+		"encrypt_config":   spec.EncryptConfig,
+		"shade_identifier": spec.ShadeIdentifier,
+		"config_encrypted": false,
+		"alert_group":      alertGroup,
+		"cluster":          spec.Cluster,
+		"status":           spec.Status,
+		"managed":          true,
+		"managed_source":   f.path,
+	}
+	current := map[string]interface{}{
+		"name":             task.Name,
+		"description":      task.Description,
+		"cron_expr":        task.CronExpr,
+		"config":           task.Config,
+		"config_format":    task.ConfigFormat,
+		"encrypt_config":   task.EncryptConfig,
+		"shade_identifier": task.ShadeIdentifier,
+		"config_encrypted": task.ConfigEncrypted,
+		"alert_group":      task.AlertGroup,
+		"cluster":          task.Cluster,
+		"status":           task.Status,
+		"managed":          task.Managed,
+		"managed_source":   task.ManagedSource,
+	}
+	updates := map[string]interface{}{}
+	for field, value := range desired {
+		if current[field] != value {
+			updates[field] = value
+			item.Fields = append(item.Fields, field)
+		}
+	}
+	if len(updates) == 0 {
+		item.Action = ActionUnchanged
+		return item, &task, nil
+	}
+	sort.Strings(item.Fields)
+	if err := seatunnelService.UpdateTask(&task, updates); err != nil {
+		return item, &task, fmt.Errorf("更新任务失败: %v", err)
+	}
+	postgres.DB.First(&task, task.ID)
+	item.Action = ActionUpdate
+	return item, &task, nil
+}
+
+// resolveAlertGroups 将告警组名称解析为逗号分隔的 ID，返回不存在的名称
+func resolveAlertGroups(names []string) (string, []string) {
+	var ids, missing []string
+	for _, name := range names {
+		var group alertModel.AlertGroup
+		if err := postgres.DB.Where("name = ?", name).First(&group).Error; err != nil {
+			missing = append(missing, name)
+			continue
+		}
+		ids = append(ids, strconv.FormatUint(uint64(group.ID), 10))
+	}
+	return strings.Join(ids, ","), missing
+}
+
+// ListReports 分页查询同步报告
+func ListReports(page, pageSize int) ([]seatunnelModel.GitOpsReport, int64, error) {
+	var reports []seatunnelModel.GitOpsReport
+	var total int64
+	query := postgres.DB.Model(&seatunnelModel.GitOpsReport{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Order("id desc").Limit(pageSize).Offset((page - 1) * pageSize).Find(&reports).Error
+	return reports, total, err
+}
+
+// GetReport 查询同步报告详情
+func GetReport(id string) (seatunnelModel.GitOpsReport, error) {
+	var report seatunnelModel.GitOpsReport
+	err := postgres.DB.First(&report, id).Error
+	return report, err
+}
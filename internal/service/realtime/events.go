@@ -0,0 +1,66 @@
+package realtime
+
+import "octoops/internal/event"
+
+// TaskReadPermission 查看指定类型 ETL 任务所需的权限码
+func TaskReadPermission(taskType string) string {
+	return "etl:" + taskType + ":read"
+}
+
+// runPermission 查看任务运行事件所需的权限码
+func runPermission(source, taskType string) string {
+	if source == event.SourceCustom {
+		return "task:custom:read"
+	}
+	return TaskReadPermission(taskType)
+}
+
+// RegisterEventHandlers 订阅领域事件并转为实时推送
+func RegisterEventHandlers() {
+	event.On("realtime.job_status", func(ev event.JobStatusChanged) error {
+		Publish(TaskReadPermission(ev.TaskType), Event{
+			Type:       TypeJobStatus,
+			Source:     event.SourceEtl,
+			TaskID:     ev.TaskID,
+			TaskName:   ev.TaskName,
+			TaskType:   ev.TaskType,
+			Status:     ev.NewStatus,
+			PrevStatus: ev.OldStatus,
+			Time:       ev.At,
+		})
+		return nil
+	})
+	event.On("realtime.task_run_started", func(ev event.TaskRunStarted) error {
+		Publish(runPermission(ev.Source, ev.TaskType), Event{
+			Type:     TypeTaskRun,
+			Source:   ev.Source,
+			TaskID:   ev.TaskID,
+			TaskName: ev.TaskName,
+			TaskType: ev.TaskType,
+			Status:   "started",
+			Time:     ev.At,
+		})
+		return nil
+	})
+	event.On("realtime.task_run_finished", func(ev event.TaskRunFinished) error {
+		status := "succeeded"
+		if ev.Status == "failed" {
+			status = "failed"
+		}
+		message := ev.Result
+		if runes := []rune(message); len(runes) > 500 {
+			message = string(runes[:500])
+		}
+		Publish(runPermission(ev.Source, ev.TaskType), Event{
+			Type:     TypeTaskRun,
+			Source:   ev.Source,
+			TaskID:   ev.TaskID,
+			TaskName: ev.TaskName,
+			TaskType: ev.TaskType,
+			Status:   status,
+			Message:  message,
+			Time:     ev.At,
+		})
+		return nil
+	})
+}
@@ -0,0 +1,130 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	infraRedis "octoops/internal/infra/redis"
+)
+
+// channel 事件在各副本间通过 Redis pub/sub 广播的频道
+const channel = "octoops:events"
+
+// 事件类型
+const (
+	TypeJobStatus = "job_status" // ETL 作业状态变化
+	TypeTaskRun   = "task_run"   // 调度器触发的任务开始/结束
+	TypeAlert     = "alert"      // 告警发送
+)
+
+// Event 推送给前端的实时事件
+type Event struct {
+	Type       string    `json:"type"`
+	Source     string    `json:"source"` // etl/custom
+	TaskID     uint      `json:"task_id"`
+	TaskName   string    `json:"task_name"`
+	TaskType   string    `json:"task_type"`
+	Status     string    `json:"status"`
+	PrevStatus string    `json:"prev_status,omitempty"`
+	Message    string    `json:"message,omitempty"`
+	Time       time.Time `json:"time"`
+}
+
+// envelope 广播载荷，Permission 为订阅者接收该事件所需的权限码
+type envelope struct {
+	Permission string `json:"permission"`
+	Event      Event  `json:"event"`
+}
+
+type subscriber struct {
+	ch      chan Event
+	allowed func(permission string) bool
+}
+
+var (
+	mu          sync.RWMutex
+	subscribers = map[*subscriber]struct{}{}
+	startOnce   sync.Once
+)
+
+// Start 订阅 Redis 频道并向本副本的连接分发事件，服务启动时调用一次
+func Start() {
+	startOnce.Do(func() {
+		client := infraRedis.Client()
+		if client == nil {
+			log.Printf("[Realtime] Redis 未初始化，事件仅在本实例内分发")
+			return
+		}
+		pubsub := client.Subscribe(context.Background(), channel)
+		go func() {
+			for msg := range pubsub.Channel() {
+				var env envelope
+				if err := json.Unmarshal([]byte(msg.Payload), &env); err != nil {
+					log.Printf("[Realtime] 解析事件失败: %v", err)
+					continue
+				}
+				dispatch(env)
+			}
+		}()
+	})
+}
+
+// Publish 发布事件，permission 为接收该事件所需的权限码；Redis 不可用时仅分发给本实例
+func Publish(permission string, ev Event) {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	env := envelope{Permission: permission, Event: ev}
+	client := infraRedis.Client()
+	if client == nil {
+		dispatch(env)
+		return
+	}
+	payload, err := json.Marshal(env)
+	if err != nil {
+		log.Printf("[Realtime] 序列化事件失败: %v", err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := client.Publish(ctx, channel, payload).Err(); err != nil {
+		log.Printf("[Realtime] 发布事件失败，仅分发给本实例: %v", err)
+		dispatch(env)
+	}
+}
+
+// Subscribe 注册订阅者，allowed 判断是否拥有事件所需权限；返回的函数用于取消订阅
+func Subscribe(allowed func(permission string) bool) (<-chan Event, func()) {
+	sub := &subscriber{ch: make(chan Event, 64), allowed: allowed}
+	mu.Lock()
+	subscribers[sub] = struct{}{}
+	mu.Unlock()
+	return sub.ch, func() {
+		mu.Lock()
+		delete(subscribers, sub)
+		mu.Unlock()
+	}
+}
+
+func dispatch(env envelope) {
+	// 权限判断可能查询数据库，先复制订阅者列表再释放锁，避免阻塞订阅与取消订阅
+	mu.RLock()
+	subs := make([]*subscriber, 0, len(subscribers))
+	for sub := range subscribers {
+		subs = append(subs, sub)
+	}
+	mu.RUnlock()
+	for _, sub := range subs {
+		if env.Permission != "" && !sub.allowed(env.Permission) {
+			continue
+		}
+		select {
+		case sub.ch <- env.Event:
+		default:
+			// 客户端消费过慢时丢弃事件，前端可通过刷新列表兜底
+		}
+	}
+}
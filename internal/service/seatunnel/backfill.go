@@ -0,0 +1,485 @@
+package seatunnel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"octoops/internal/infra/postgres"
+	seatunnelModel "octoops/internal/model/seatunnel"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	BackfillStatusPending  = "pending"
+	BackfillStatusRunning  = "running"
+	BackfillStatusPaused   = "paused"
+	BackfillStatusCanceled = "canceled"
+	BackfillStatusFinished = "finished"
+
+	SliceStatusPending   = "pending"
+	SliceStatusSubmitted = "submitted"
+	SliceStatusRunning   = "running"
+	SliceStatusFinished  = "finished"
+	SliceStatusFailed    = "failed"
+	SliceStatusCanceled  = "canceled"
+
+	maxBackfillSlices      = 1000
+	maxBackfillConcurrency = 20
+	backfillPollInterval   = 5 * time.Second
+)
+
+var ErrBackfillInvalidState = errors.New("补数作业当前状态不允许该操作")
+
+// errBackfillStopped 提交分片前发现补数已暂停/取消或分片已被处理
+var errBackfillStopped = errors.New("补数作业已停止")
+
+// BackfillRequest 创建补数作业请求
+type BackfillRequest struct {
+	StartDate   string `json:"start_date" binding:"required"`
+	EndDate     string `json:"end_date" binding:"required"`
+	Granularity string `json:"granularity"`
+	VarName     string `json:"var_name"`
+	DateFormat  string `json:"date_format"`
+	Concurrency int    `json:"concurrency"`
+}
+
+type backfillRunner struct {
+	cancel context.CancelFunc
+}
+
+var backfillRunners = struct {
+	sync.Mutex
+	runners map[uint]*backfillRunner
+}{runners: map[uint]*backfillRunner{}}
+
+// CreateBackfill 为离线任务创建补数作业并立即开始执行
+func CreateBackfill(taskID uint, req BackfillRequest, operator string) (seatunnelModel.BackfillJob, error) {
+	task, err := GetTaskByID(taskID)
+	if err != nil {
+		return seatunnelModel.BackfillJob{}, err
+	}
+	if task.TaskType != "batch" {
+		return seatunnelModel.BackfillJob{}, fmt.Errorf("仅离线任务支持补数")
+	}
+	if req.Granularity == "" {
+		req.Granularity = "day"
+	}
+	if req.VarName == "" {
+		req.VarName = "biz_date"
+	}
+	if req.DateFormat == "" {
+		req.DateFormat = "yyyy-MM-dd"
+	}
+	if req.Concurrency < 1 {
+		req.Concurrency = 1
+	}
+	if req.Concurrency > maxBackfillConcurrency {
+		req.Concurrency = maxBackfillConcurrency
+	}
+	dates, err := BuildBackfillDates(req.StartDate, req.EndDate, req.Granularity)
+	if err != nil {
+		return seatunnelModel.BackfillJob{}, err
+	}
+
+	job := seatunnelModel.BackfillJob{
+		TaskID:      task.ID,
+		TaskName:    task.Name,
+		StartDate:   req.StartDate,
+		EndDate:     req.EndDate,
+		Granularity: req.Granularity,
+		VarName:     req.VarName,
+		DateFormat:  req.DateFormat,
+		Concurrency: req.Concurrency,
+		Status:      BackfillStatusRunning,
+		Total:       len(dates),
+		CreatedBy:   operator,
+	}
+	err = postgres.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&job).Error; err != nil {
+			return err
+		}
+		slices := make([]seatunnelModel.BackfillSlice, 0, len(dates))
+		for _, d := range dates {
+			slices = append(slices, seatunnelModel.BackfillSlice{
+				BackfillID: job.ID,
+				BizDate:    d.Format("2006-01-02"),
+				Status:     SliceStatusPending,
+			})
+		}
+		return tx.CreateInBatches(&slices, 200).Error
+	})
+	if err != nil {
+		return seatunnelModel.BackfillJob{}, err
+	}
+
+	log.Printf("[Backfill] 创建补数作业 id=%d, taskID=%d, range=%s..%s, slices=%d, concurrency=%d", job.ID, task.ID, job.StartDate, job.EndDate, job.Total, job.Concurrency)
+	startBackfillRunner(job.ID)
+	return job, nil
+}
+
+// BuildBackfillDates 按粒度展开日期区间（首尾均包含）
+func BuildBackfillDates(start, end, granularity string) ([]time.Time, error) {
+	startDate, err := time.ParseInLocation("2006-01-02", start, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("起始日期格式错误，应为 yyyy-MM-dd: %s", start)
+	}
+	endDate, err := time.ParseInLocation("2006-01-02", end, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("结束日期格式错误，应为 yyyy-MM-dd: %s", end)
+	}
+	if endDate.Before(startDate) {
+		return nil, fmt.Errorf("结束日期不能早于起始日期")
+	}
+
+	var dates []time.Time
+	switch granularity {
+	case "day":
+		for d := startDate; !d.After(endDate); d = d.AddDate(0, 0, 1) {
+			dates = append(dates, d)
+			if len(dates) > maxBackfillSlices {
+				break
+			}
+		}
+	case "month":
+		d := time.Date(startDate.Year(), startDate.Month(), 1, 0, 0, 0, 0, time.Local)
+		for ; !d.After(endDate); d = d.AddDate(0, 1, 0) {
+			dates = append(dates, d)
+			if len(dates) > maxBackfillSlices {
+				break
+			}
+		}
+	default:
+		return nil, fmt.Errorf("不支持的分片粒度: %s", granularity)
+	}
+	if len(dates) > maxBackfillSlices {
+		return nil, fmt.Errorf("补数分片数量超过上限 %d", maxBackfillSlices)
+	}
+	return dates, nil
+}
+
+// BackfillVariables 生成分片的运行变量：<var> 为分片日期，<var>_end 为下一分片日期（不含）
+func BackfillVariables(job seatunnelModel.BackfillJob, bizDate string) (map[string]string, error) {
+	d, err := time.ParseInLocation("2006-01-02", bizDate, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	next := d.AddDate(0, 0, 1)
+	if job.Granularity == "month" {
+		next = d.AddDate(0, 1, 0)
+	}
+	layout := toGoDateLayout(job.DateFormat)
+	return map[string]string{
+		job.VarName:          d.Format(layout),
+		job.VarName + "_end": next.Format(layout),
+	}, nil
+}
+
+// toGoDateLayout 将 yyyy-MM-dd 风格的日期格式转换为 Go 时间格式
+func toGoDateLayout(format string) string {
+	replacer := strings.NewReplacer("yyyy", "2006", "MM", "01", "dd", "02", "HH", "15", "mm", "04", "ss", "05")
+	return replacer.Replace(format)
+}
+
+func ListBackfills(taskID uint, page, pageSize int) ([]seatunnelModel.BackfillJob, int64, error) {
+	var jobs []seatunnelModel.BackfillJob
+	query := postgres.DB.Model(&seatunnelModel.BackfillJob{})
+	if taskID != 0 {
+		query = query.Where("task_id = ?", taskID)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Order("created_at desc").Limit(pageSize).Offset((page - 1) * pageSize).Find(&jobs).Error
+	return jobs, total, err
+}
+
+func GetBackfill(id interface{}) (seatunnelModel.BackfillJob, []seatunnelModel.BackfillSlice, error) {
+	var job seatunnelModel.BackfillJob
+	if err := postgres.DB.First(&job, id).Error; err != nil {
+		return job, nil, err
+	}
+	var slices []seatunnelModel.BackfillSlice
+	err := postgres.DB.Where("backfill_id = ?", job.ID).Order("biz_date asc").Find(&slices).Error
+	return job, slices, err
+}
+
+// PauseBackfill 暂停补数：不再提交新分片，已提交的分片在恢复后继续跟踪
+func PauseBackfill(id uint) error {
+	res := postgres.DB.Model(&seatunnelModel.BackfillJob{}).
+		Where("id = ? AND status = ?", id, BackfillStatusRunning).
+		Update("status", BackfillStatusPaused)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrBackfillInvalidState
+	}
+	stopBackfillRunner(id)
+	return nil
+}
+
+// ResumeBackfill 恢复已暂停的补数作业
+func ResumeBackfill(id uint) error {
+	res := postgres.DB.Model(&seatunnelModel.BackfillJob{}).
+		Where("id = ? AND status = ?", id, BackfillStatusPaused).
+		Update("status", BackfillStatusRunning)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrBackfillInvalidState
+	}
+	startBackfillRunner(id)
+	return nil
+}
+
+// CancelBackfill 取消补数：未提交的分片标记为取消，运行中的作业尝试停止
+func CancelBackfill(id uint) error {
+	res := postgres.DB.Model(&seatunnelModel.BackfillJob{}).
+		Where("id = ? AND status IN ?", id, []string{BackfillStatusRunning, BackfillStatusPaused, BackfillStatusPending}).
+		Updates(map[string]interface{}{"status": BackfillStatusCanceled, "finished_at": time.Now()})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrBackfillInvalidState
+	}
+	stopBackfillRunner(id)
+
+	now := time.Now()
+	postgres.DB.Model(&seatunnelModel.BackfillSlice{}).
+		Where("backfill_id = ? AND status = ?", id, SliceStatusPending).
+		Updates(map[string]interface{}{"status": SliceStatusCanceled, "finished_at": now})
+
+	var job seatunnelModel.BackfillJob
+	postgres.DB.First(&job, id)
+	var task seatunnelModel.EtlTask
+	postgres.DB.Unscoped().Select("id", "cluster").First(&task, job.TaskID)
+
+	var inflight []seatunnelModel.BackfillSlice
+	postgres.DB.Where("backfill_id = ? AND status IN ?", id, []string{SliceStatusSubmitted, SliceStatusRunning}).Find(&inflight)
+	for _, s := range inflight {
+		message := "补数已取消"
+		if s.JobID != "" {
+			if _, _, err := StopSeatunnelJob(task.Cluster, s.JobID, false); err != nil {
+				log.Printf("[Backfill] 停止分片作业失败: backfillID=%d, bizDate=%s, jobId=%s, error=%v", id, s.BizDate, s.JobID, err)
+				message = "补数已取消，停止作业失败: " + err.Error()
+			}
+		}
+		postgres.DB.Model(&s).Updates(map[string]interface{}{"status": SliceStatusCanceled, "message": message, "finished_at": now})
+	}
+	return nil
+}
+
+// ResumeBackfills 服务启动时恢复运行中的补数作业
+func ResumeBackfills() {
+	var jobs []seatunnelModel.BackfillJob
+	if err := postgres.DB.Where("status = ?", BackfillStatusRunning).Find(&jobs).Error; err != nil {
+		log.Printf("[Backfill] 加载运行中的补数作业失败: %v", err)
+		return
+	}
+	for _, job := range jobs {
+		startBackfillRunner(job.ID)
+	}
+	log.Printf("[Backfill] 恢复了 %d 个运行中的补数作业", len(jobs))
+}
+
+func startBackfillRunner(id uint) {
+	backfillRunners.Lock()
+	defer backfillRunners.Unlock()
+	if _, exists := backfillRunners.runners[id]; exists {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	runner := &backfillRunner{cancel: cancel}
+	backfillRunners.runners[id] = runner
+	go runBackfill(ctx, id, runner)
+}
+
+func stopBackfillRunner(id uint) {
+	backfillRunners.Lock()
+	runner, exists := backfillRunners.runners[id]
+	delete(backfillRunners.runners, id)
+	backfillRunners.Unlock()
+	if exists {
+		runner.cancel()
+	}
+}
+
+func runBackfill(ctx context.Context, id uint, runner *backfillRunner) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[Backfill][Panic] backfillID=%d, err=%v", id, r)
+		}
+		backfillRunners.Lock()
+		if backfillRunners.runners[id] == runner {
+			delete(backfillRunners.runners, id)
+		}
+		backfillRunners.Unlock()
+		runner.cancel()
+	}()
+
+	ticker := time.NewTicker(backfillPollInterval)
+	defer ticker.Stop()
+	for {
+		if done := stepBackfill(id); done {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// stepBackfill 执行一轮调度：跟踪在途分片状态，并按并发度补充提交新分片，返回是否结束
+func stepBackfill(id uint) bool {
+	var job seatunnelModel.BackfillJob
+	if err := postgres.DB.First(&job, id).Error; err != nil {
+		log.Printf("[Backfill] 补数作业不存在: id=%d, error=%v", id, err)
+		return true
+	}
+	if job.Status != BackfillStatusRunning {
+		return true
+	}
+	task, err := GetTaskByID(job.TaskID)
+	if err != nil {
+		log.Printf("[Backfill] 任务不存在，取消补数: backfillID=%d, taskID=%d", id, job.TaskID)
+		postgres.DB.Model(&job).Updates(map[string]interface{}{"status": BackfillStatusCanceled, "finished_at": time.Now()})
+		return true
+	}
+
+	var inflight []seatunnelModel.BackfillSlice
+	postgres.DB.Where("backfill_id = ? AND status IN ?", id, []string{SliceStatusSubmitted, SliceStatusRunning}).Find(&inflight)
+	running := 0
+	for _, s := range inflight {
+		if !trackBackfillSlice(task, s) {
+			running++
+		}
+	}
+
+	if free := job.Concurrency - running; free > 0 {
+		var pending []seatunnelModel.BackfillSlice
+		postgres.DB.Where("backfill_id = ? AND status = ?", id, SliceStatusPending).Order("biz_date asc").Limit(free).Find(&pending)
+		for _, s := range pending {
+			submitBackfillSlice(job, task, s)
+		}
+	}
+
+	return refreshBackfillProgress(job)
+}
+
+// trackBackfillSlice 查询分片作业状态，返回分片是否已结束
+func trackBackfillSlice(task seatunnelModel.EtlTask, s seatunnelModel.BackfillSlice) bool {
+	if s.JobID == "" {
+		return false
+	}
+	result := QuerySeatunnelJobStatus(task.Cluster, s.JobID)
+	updates := map[string]interface{}{"job_status": result.JobStatus}
+	finished := false
+	switch result.JobStatus {
+	case "FINISHED":
+		updates["status"] = SliceStatusFinished
+		finished = true
+	case "FAILED", "CANCELED", "CANCEL":
+		updates["status"] = SliceStatusFailed
+		updates["message"] = "作业状态为 " + result.JobStatus
+		finished = true
+	case "RUNNING":
+		updates["status"] = SliceStatusRunning
+	}
+	if finished {
+		updates["finished_at"] = time.Now()
+	}
+	postgres.DB.Model(&s).Updates(updates)
+	return finished
+}
+
+// submitBackfillSlice 提交分片；提交期间持有补数作业的行锁并重新确认作业仍在运行、分片仍待提交，
+// 取消操作会等待本次提交结束，随后停止已提交的作业，避免取消后仍有分片被提交
+func submitBackfillSlice(job seatunnelModel.BackfillJob, task seatunnelModel.EtlTask, s seatunnelModel.BackfillSlice) {
+	err := postgres.DB.Transaction(func(tx *gorm.DB) error {
+		var current seatunnelModel.BackfillJob
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "status").First(&current, job.ID).Error; err != nil {
+			return err
+		}
+		if current.Status != BackfillStatusRunning {
+			return errBackfillStopped
+		}
+		var slice seatunnelModel.BackfillSlice
+		if err := tx.Select("id", "status").First(&slice, s.ID).Error; err != nil {
+			return err
+		}
+		if slice.Status != SliceStatusPending {
+			return errBackfillStopped
+		}
+
+		now := time.Now()
+		vars, err := BackfillVariables(job, s.BizDate)
+		if err != nil {
+			return tx.Model(&s).Updates(map[string]interface{}{"status": SliceStatusFailed, "message": err.Error(), "finished_at": now}).Error
+		}
+		respBody, err := SubmitJobInternal(task.ID, false, vars)
+		if err != nil {
+			log.Printf("[Backfill] 分片提交失败: backfillID=%d, bizDate=%s, error=%v", job.ID, s.BizDate, err)
+			WriteTaskLogWithTrigger(task, []byte(err.Error()), "failed", "backfill")
+			return tx.Model(&s).Updates(map[string]interface{}{"status": SliceStatusFailed, "message": err.Error(), "started_at": now, "finished_at": now}).Error
+		}
+		WriteTaskLogWithTrigger(task, respBody, "success", "backfill")
+		jobID := ParseJobIDFromResponse(respBody)
+		updates := map[string]interface{}{"status": SliceStatusSubmitted, "job_id": jobID, "started_at": now, "message": ""}
+		if jobID == "" {
+			updates["status"] = SliceStatusFailed
+			updates["message"] = "提交成功但未返回 jobId: " + string(respBody)
+			updates["finished_at"] = now
+		}
+		log.Printf("[Backfill] 分片提交成功: backfillID=%d, bizDate=%s, jobId=%s", job.ID, s.BizDate, jobID)
+		return tx.Model(&s).Updates(updates).Error
+	})
+	if err != nil && !errors.Is(err, errBackfillStopped) {
+		log.Printf("[Backfill] 分片提交记录失败: backfillID=%d, bizDate=%s, error=%v", job.ID, s.BizDate, err)
+	}
+}
+
+// refreshBackfillProgress 汇总分片状态，全部结束时标记补数完成
+func refreshBackfillProgress(job seatunnelModel.BackfillJob) bool {
+	type statusCount struct {
+		Status string
+		Count  int
+	}
+	var counts []statusCount
+	postgres.DB.Model(&seatunnelModel.BackfillSlice{}).
+		Select("status, count(*) as count").
+		Where("backfill_id = ?", job.ID).
+		Group("status").
+		Scan(&counts)
+
+	succeeded, failed, open := 0, 0, 0
+	for _, c := range counts {
+		switch c.Status {
+		case SliceStatusFinished:
+			succeeded = c.Count
+		case SliceStatusFailed:
+			failed = c.Count
+		case SliceStatusPending, SliceStatusSubmitted, SliceStatusRunning:
+			open += c.Count
+		}
+	}
+	updates := map[string]interface{}{"succeeded": succeeded, "failed": failed}
+	done := open == 0
+	if done {
+		updates["status"] = BackfillStatusFinished
+		updates["finished_at"] = time.Now()
+		log.Printf("[Backfill] 补数完成: id=%d, succeeded=%d, failed=%d", job.ID, succeeded, failed)
+	}
+	postgres.DB.Model(&job).Where("status = ?", BackfillStatusRunning).Updates(updates)
+	return done
+}
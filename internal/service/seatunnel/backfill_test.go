@@ -0,0 +1,69 @@
+package seatunnel
+
+import (
+	"testing"
+
+	seatunnelModel "octoops/internal/model/seatunnel"
+)
+
+func TestRenderConfigVariables(t *testing.T) {
+	config := `query = "select * from t where dt >= '${biz_date}' and dt < '${biz_date_end}' and env = '${env}'"`
+	got := RenderConfigVariables(config, map[string]string{
+		"biz_date":     "2026-09-01",
+		"biz_date_end": "2026-09-02",
+	})
+	want := `query = "select * from t where dt >= '2026-09-01' and dt < '2026-09-02' and env = '${env}'"`
+	if got != want {
+		t.Fatalf("unexpected render result:\n got: %s\nwant: %s", got, want)
+	}
+	if RenderConfigVariables(config, nil) != config {
+		t.Fatalf("expected config unchanged without variables")
+	}
+}
+
+func TestBuildBackfillDates(t *testing.T) {
+	tests := []struct {
+		name        string
+		start, end  string
+		granularity string
+		wantCount   int
+		shouldErr   bool
+	}{
+		{name: "september daily", start: "2026-09-01", end: "2026-09-30", granularity: "day", wantCount: 30},
+		{name: "single day", start: "2026-09-01", end: "2026-09-01", granularity: "day", wantCount: 1},
+		{name: "monthly", start: "2026-01-15", end: "2026-03-01", granularity: "month", wantCount: 3},
+		{name: "end before start", start: "2026-09-02", end: "2026-09-01", granularity: "day", shouldErr: true},
+		{name: "bad date", start: "2026/09/01", end: "2026-09-01", granularity: "day", shouldErr: true},
+		{name: "unknown granularity", start: "2026-09-01", end: "2026-09-02", granularity: "hour", shouldErr: true},
+		{name: "too many slices", start: "2020-01-01", end: "2026-01-01", granularity: "day", shouldErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dates, err := BuildBackfillDates(tt.start, tt.end, tt.granularity)
+			if tt.shouldErr {
+				if err == nil {
+					t.Fatalf("expected error, got %d dates", len(dates))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(dates) != tt.wantCount {
+				t.Fatalf("expected %d dates, got %d", tt.wantCount, len(dates))
+			}
+		})
+	}
+}
+
+func TestBackfillVariables(t *testing.T) {
+	job := seatunnelModel.BackfillJob{VarName: "dt", DateFormat: "yyyyMMdd", Granularity: "day"}
+	vars, err := BackfillVariables(job, "2026-09-30")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if vars["dt"] != "20260930" || vars["dt_end"] != "20261001" {
+		t.Fatalf("unexpected variables: %v", vars)
+	}
+}
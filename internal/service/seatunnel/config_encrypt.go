@@ -0,0 +1,193 @@
+package seatunnel
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"octoops/internal/infra/postgres"
+	seatunnelModel "octoops/internal/model/seatunnel"
+	"time"
+)
+
+const defaultShadeIdentifier = "base64"
+
+// ErrEncryptedConfigEdit 已加密配置返回的是密文，直接编辑会对密文再次加密
+var ErrEncryptedConfigEdit = errors.New("任务配置已加密，修改配置需提交完整明文并设置 config_plaintext=true")
+
+// ConfigEncryptResult 批量加密存量配置的单任务结果
+type ConfigEncryptResult struct {
+	TaskID   uint   `json:"task_id"`
+	TaskName string `json:"task_name"`
+	Status   string `json:"status"` // encrypted/deferred/skipped/failed
+	Message  string `json:"message"`
+}
+
+// EncryptConfigViaEngine 调用 SeaTunnel /encrypt-config 加密配置中的敏感字段
+// 配置需为 JSON 格式，env.shade.identifier 未设置时使用任务指定或默认的加密插件
+func EncryptConfigViaEngine(cluster, jobConfig, format, shadeIdentifier string) (string, error) {
+	baseURL, err := engineURL(cluster)
+	if err != nil {
+		return "", err
+	}
+	if format != "" && format != "json" {
+		return "", fmt.Errorf("仅支持 JSON 格式的配置加密，当前格式: %s", format)
+	}
+	var cfg map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader([]byte(jobConfig)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&cfg); err != nil {
+		return "", fmt.Errorf("配置不是合法的 JSON: %v", err)
+	}
+	env, _ := cfg["env"].(map[string]interface{})
+	if env == nil {
+		env = map[string]interface{}{}
+	}
+	if _, ok := env["shade.identifier"]; !ok {
+		if shadeIdentifier == "" {
+			shadeIdentifier = defaultShadeIdentifier
+		}
+		env["shade.identifier"] = shadeIdentifier
+	}
+	cfg["env"] = env
+	body, err := json.Marshal(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(baseURL+"/encrypt-config", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("调用 encrypt-config 失败: %v", err)
+	}
+	defer func(body io.ReadCloser) {
+		closeErr := body.Close()
+		if closeErr != nil {
+			fmt.Printf("关闭响应体失败: %v\n", closeErr)
+		}
+	}(resp.Body)
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("调用 encrypt-config 失败，状态码: %d, 响应: %s", resp.StatusCode, string(respBody))
+	}
+	if !json.Valid(respBody) {
+		return "", fmt.Errorf("encrypt-config 返回内容不是合法的 JSON: %s", string(respBody))
+	}
+	return string(respBody), nil
+}
+
+// PrepareTaskConfig 保存任务前处理配置加密：开启加密且配置不含数据源引用时，存储加密后的配置
+// 含数据源引用的配置保持原样存储，在提交时注入数据源后再加密
+func PrepareTaskConfig(task *seatunnelModel.EtlTask) error {
+	task.ConfigEncrypted = false
+	if !task.EncryptConfig || task.Config == "" || HasDatasourceReferences(task.Config) {
+		return nil
+	}
+	encrypted, err := EncryptConfigViaEngine(task.Cluster, task.Config, task.ConfigFormat, task.ShadeIdentifier)
+	if err != nil {
+		return err
+	}
+	task.Config = encrypted
+	task.ConfigEncrypted = true
+	return nil
+}
+
+// PrepareTaskConfigUpdate 更新任务时处理配置加密，结果直接写入更新字段
+// 配置未变化且已加密时不重复加密；已加密的配置只接受 config_plaintext=true 标记的完整明文替换，
+// 否则返回 ErrEncryptedConfigEdit，避免对基于密文修改的配置再次加密
+func PrepareTaskConfigUpdate(dbTask seatunnelModel.EtlTask, updates map[string]interface{}) error {
+	plaintext, _ := updates["config_plaintext"].(bool)
+	delete(updates, "config_plaintext")
+
+	merged := dbTask
+	configChanged := false
+	if v, ok := updates["config"].(string); ok && v != dbTask.Config {
+		if dbTask.ConfigEncrypted && !plaintext {
+			return ErrEncryptedConfigEdit
+		}
+		merged.Config = v
+		configChanged = true
+	}
+	if v, ok := updates["config_format"].(string); ok {
+		merged.ConfigFormat = v
+	}
+	if v, ok := updates["encrypt_config"].(bool); ok {
+		merged.EncryptConfig = v
+	}
+	if v, ok := updates["shade_identifier"].(string); ok {
+		merged.ShadeIdentifier = v
+	}
+	if v, ok := updates["cluster"].(string); ok {
+		merged.Cluster = v
+	}
+	delete(updates, "config_encrypted")
+
+	if !configChanged && (dbTask.ConfigEncrypted || !merged.EncryptConfig) {
+		return nil
+	}
+	if err := PrepareTaskConfig(&merged); err != nil {
+		return err
+	}
+	updates["config"] = merged.Config
+	updates["config_encrypted"] = merged.ConfigEncrypted
+	return nil
+}
+
+// EncryptStoredConfigs 批量加密存量任务配置，并为这些任务开启配置加密
+func EncryptStoredConfigs(taskIDs []uint, taskTypes []string) ([]ConfigEncryptResult, error) {
+	var tasks []seatunnelModel.EtlTask
+	query := postgres.DB.Where("config_encrypted = ?", false).Where("task_type IN ?", taskTypes)
+	if len(taskIDs) > 0 {
+		query = query.Where("id IN ?", taskIDs)
+	}
+	if err := query.Order("id asc").Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+
+	results := make([]ConfigEncryptResult, 0, len(tasks))
+	for _, task := range tasks {
+		result := ConfigEncryptResult{TaskID: task.ID, TaskName: task.Name}
+		switch {
+		case task.Config == "":
+			result.Status = "skipped"
+			result.Message = "配置为空"
+		case task.Managed:
+			result.Status = "skipped"
+			result.Message = "任务由 GitOps 目录管理，请在定义文件中开启 encrypt_config"
+		case task.ConfigFormat != "" && task.ConfigFormat != "json":
+			result.Status = "skipped"
+			result.Message = "仅支持 JSON 格式的配置加密"
+		case HasDatasourceReferences(task.Config):
+			if err := postgres.DB.Model(&task).Update("encrypt_config", true).Error; err != nil {
+				result.Status = "failed"
+				result.Message = err.Error()
+				break
+			}
+			result.Status = "deferred"
+			result.Message = "配置含数据源引用，将在提交时加密"
+		default:
+			task.EncryptConfig = true
+			if err := PrepareTaskConfig(&task); err != nil {
+				result.Status = "failed"
+				result.Message = err.Error()
+				break
+			}
+			if err := postgres.DB.Model(&task).Updates(map[string]interface{}{
+				"config":           task.Config,
+				"encrypt_config":   true,
+				"config_encrypted": true,
+			}).Error; err != nil {
+				result.Status = "failed"
+				result.Message = err.Error()
+				break
+			}
+			result.Status = "encrypted"
+		}
+		log.Printf("[ETL] 存量配置加密: taskID=%d, status=%s, message=%s", task.ID, result.Status, result.Message)
+		results = append(results, result)
+	}
+	return results, nil
+}
@@ -0,0 +1,23 @@
+package seatunnel
+
+import (
+	"regexp"
+)
+
+// 运行变量占位符，形如 ${biz_date}
+var configVariablePattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_.]*)\}`)
+
+// RenderConfigVariables 将配置中的 ${变量名} 替换为运行变量的值
+// 未提供的变量保持原样，交由 SeaTunnel 自身的变量替换处理
+func RenderConfigVariables(config string, vars map[string]string) string {
+	if len(vars) == 0 {
+		return config
+	}
+	return configVariablePattern.ReplaceAllStringFunc(config, func(placeholder string) string {
+		name := configVariablePattern.FindStringSubmatch(placeholder)[1]
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		return placeholder
+	})
+}
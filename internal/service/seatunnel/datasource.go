@@ -0,0 +1,429 @@
+package seatunnel
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"octoops/internal/infra/postgres"
+	seatunnelModel "octoops/internal/model/seatunnel"
+	"octoops/internal/utils"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	ErrDatasourceInUse       = errors.New("数据源正在被任务配置引用")
+	ErrDatasourceNameInvalid = errors.New("数据源名称只能包含字母、数字、下划线和中划线")
+	ErrDatasourceNameExists  = errors.New("数据源名称已存在")
+
+	datasourceNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+	// 数据源引用占位符，形如 ${datasource.mysql_prod.password}
+	datasourceRefPattern = regexp.MustCompile(`\$\{datasource\.([A-Za-z0-9_-]+)\.([A-Za-z0-9_.-]+)\}`)
+	jdbcURLPattern       = regexp.MustCompile(`^jdbc:([a-z0-9]+):(?:[a-z]+:)*//([^/:?;,]+)(?::(\d+))?`)
+)
+
+// DatasourceRequest 创建/更新数据源请求，Secrets 中的值只写不读；更新时未传的 Description 保持不变
+type DatasourceRequest struct {
+	Name        string            `json:"name"`
+	Type        string            `json:"type"`
+	Description *string           `json:"description"`
+	Options     map[string]string `json:"options"`
+	Secrets     map[string]string `json:"secrets"`
+}
+
+// DatasourceView 数据源对外展示结构，仅返回敏感参数的键名
+type DatasourceView struct {
+	ID          uint              `json:"id"`
+	Name        string            `json:"name"`
+	Type        string            `json:"type"`
+	Description string            `json:"description"`
+	Options     map[string]string `json:"options"`
+	SecretKeys  []string          `json:"secret_keys"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// DatasourceTestResult 连接测试结果
+type DatasourceTestResult struct {
+	Success   bool   `json:"success"`
+	Message   string `json:"message"`
+	LatencyMs int64  `json:"latency_ms"`
+}
+
+func ToDatasourceView(ds seatunnelModel.Datasource) DatasourceView {
+	view := DatasourceView{
+		ID:          ds.ID,
+		Name:        ds.Name,
+		Type:        ds.Type,
+		Description: ds.Description,
+		Options:     decodeStringMap(ds.Options),
+		SecretKeys:  []string{},
+		CreatedAt:   ds.CreatedAt,
+		UpdatedAt:   ds.UpdatedAt,
+	}
+	for k := range decodeStringMap(ds.Secrets) {
+		view.SecretKeys = append(view.SecretKeys, k)
+	}
+	sort.Strings(view.SecretKeys)
+	return view
+}
+
+func ListDatasources(dsType, name string) ([]DatasourceView, error) {
+	var list []seatunnelModel.Datasource
+	query := postgres.DB.Order("created_at desc")
+	if dsType != "" {
+		query = query.Where("type = ?", dsType)
+	}
+	if name != "" {
+		query = query.Where("name LIKE ?", "%"+name+"%")
+	}
+	if err := query.Find(&list).Error; err != nil {
+		return nil, err
+	}
+	views := make([]DatasourceView, 0, len(list))
+	for _, ds := range list {
+		views = append(views, ToDatasourceView(ds))
+	}
+	return views, nil
+}
+
+func GetDatasourceByID(id interface{}) (seatunnelModel.Datasource, error) {
+	var ds seatunnelModel.Datasource
+	err := postgres.DB.First(&ds, id).Error
+	return ds, err
+}
+
+func CreateDatasource(req DatasourceRequest) (seatunnelModel.Datasource, error) {
+	if !datasourceNamePattern.MatchString(req.Name) {
+		return seatunnelModel.Datasource{}, ErrDatasourceNameInvalid
+	}
+	if err := checkDatasourceName(req.Name, 0); err != nil {
+		return seatunnelModel.Datasource{}, err
+	}
+	secrets, err := encryptSecrets(map[string]string{}, req.Secrets)
+	if err != nil {
+		return seatunnelModel.Datasource{}, err
+	}
+	ds := seatunnelModel.Datasource{
+		Name:        req.Name,
+		Type:        req.Type,
+		Description: derefString(req.Description),
+		Options:     encodeStringMap(req.Options),
+		Secrets:     encodeStringMap(secrets),
+	}
+	err = postgres.DB.Create(&ds).Error
+	return ds, err
+}
+
+// UpdateDatasource 更新数据源；Secrets 中未出现的键保持不变，值为空字符串表示删除该键
+func UpdateDatasource(id string, req DatasourceRequest) (seatunnelModel.Datasource, error) {
+	ds, err := GetDatasourceByID(id)
+	if err != nil {
+		return seatunnelModel.Datasource{}, err
+	}
+	if req.Name != "" && req.Name != ds.Name {
+		if !datasourceNamePattern.MatchString(req.Name) {
+			return seatunnelModel.Datasource{}, ErrDatasourceNameInvalid
+		}
+		if err := checkDatasourceName(req.Name, ds.ID); err != nil {
+			return seatunnelModel.Datasource{}, err
+		}
+		if referenced, err := isDatasourceReferenced(ds.Name); err != nil {
+			return seatunnelModel.Datasource{}, err
+		} else if referenced {
+			return seatunnelModel.Datasource{}, fmt.Errorf("%w，不能修改名称", ErrDatasourceInUse)
+		}
+		ds.Name = req.Name
+	}
+	secrets, err := encryptSecrets(decodeStringMap(ds.Secrets), req.Secrets)
+	if err != nil {
+		return seatunnelModel.Datasource{}, err
+	}
+	updates := map[string]interface{}{
+		"name":    ds.Name,
+		"secrets": encodeStringMap(secrets),
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.Type != "" {
+		updates["type"] = req.Type
+	}
+	if req.Options != nil {
+		updates["options"] = encodeStringMap(req.Options)
+	}
+	if err := postgres.DB.Model(&ds).Updates(updates).Error; err != nil {
+		return seatunnelModel.Datasource{}, err
+	}
+	return ds, nil
+}
+
+func DeleteDatasource(id string) error {
+	ds, err := GetDatasourceByID(id)
+	if err != nil {
+		return err
+	}
+	referenced, err := isDatasourceReferenced(ds.Name)
+	if err != nil {
+		return err
+	}
+	if referenced {
+		return ErrDatasourceInUse
+	}
+	return postgres.DB.Delete(&ds).Error
+}
+
+// checkDatasourceName 名称已被其他数据源使用时返回 ErrDatasourceNameExists
+func checkDatasourceName(name string, excludeID uint) error {
+	var count int64
+	if err := postgres.DB.Model(&seatunnelModel.Datasource{}).Where("name = ? AND id <> ?", name, excludeID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrDatasourceNameExists
+	}
+	return nil
+}
+
+func isDatasourceReferenced(name string) (bool, error) {
+	var count int64
+	err := postgres.DB.Model(&seatunnelModel.EtlTask{}).
+		Where(`config LIKE ? ESCAPE '\'`, "%${datasource."+utils.EscapeLike(name)+".%").
+		Count(&count).Error
+	return count > 0, err
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// ResolveDatasourceReferences 在提交时将配置中的数据源引用替换为实际值（含解密后的敏感参数）
+func ResolveDatasourceReferences(config string) (string, error) {
+	matches := datasourceRefPattern.FindAllStringSubmatch(config, -1)
+	if len(matches) == 0 {
+		return config, nil
+	}
+
+	values := map[string]map[string]string{}
+	for _, m := range matches {
+		name := m[1]
+		if _, loaded := values[name]; loaded {
+			continue
+		}
+		var ds seatunnelModel.Datasource
+		if err := postgres.DB.Where("name = ?", name).First(&ds).Error; err != nil {
+			return "", fmt.Errorf("数据源 %s 不存在", name)
+		}
+		resolved, err := datasourceValues(ds)
+		if err != nil {
+			return "", fmt.Errorf("数据源 %s 解密失败: %v", name, err)
+		}
+		values[name] = resolved
+	}
+
+	var missing []string
+	result := datasourceRefPattern.ReplaceAllStringFunc(config, func(placeholder string) string {
+		m := datasourceRefPattern.FindStringSubmatch(placeholder)
+		v, ok := values[m[1]][m[2]]
+		if !ok {
+			missing = append(missing, m[1]+"."+m[2])
+			return placeholder
+		}
+		return escapeConfigValue(v)
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("数据源参数不存在: %s", strings.Join(missing, ", "))
+	}
+	return result, nil
+}
+
+// HasDatasourceReferences 判断配置中是否包含数据源引用
+func HasDatasourceReferences(config string) bool {
+	return datasourceRefPattern.MatchString(config)
+}
+
+// TestDatasourceConnection 测试数据源连通性
+func TestDatasourceConnection(ds seatunnelModel.Datasource) DatasourceTestResult {
+	values, err := datasourceValues(ds)
+	if err != nil {
+		return DatasourceTestResult{Message: "敏感参数解密失败: " + err.Error()}
+	}
+	start := time.Now()
+	switch ds.Type {
+	case "jdbc":
+		addr, err := jdbcAddress(values["url"])
+		if err != nil {
+			return DatasourceTestResult{Message: err.Error()}
+		}
+		err = dialTCP(addr)
+		return buildTestResult(start, err, "连接 "+addr+" 成功")
+	case "kafka":
+		servers := splitHosts(values["bootstrap.servers"])
+		if len(servers) == 0 {
+			return DatasourceTestResult{Message: "缺少 bootstrap.servers 参数"}
+		}
+		var errs []string
+		for _, addr := range servers {
+			if err := dialTCP(addr); err != nil {
+				errs = append(errs, err.Error())
+				continue
+			}
+			return buildTestResult(start, nil, "连接 "+addr+" 成功")
+		}
+		return buildTestResult(start, errors.New(strings.Join(errs, "; ")), "")
+	case "elasticsearch":
+		hosts := splitHosts(values["hosts"])
+		if len(hosts) == 0 {
+			return DatasourceTestResult{Message: "缺少 hosts 参数"}
+		}
+		err := checkHTTP(hosts[0], values["username"], values["password"])
+		return buildTestResult(start, err, "访问 "+hosts[0]+" 成功")
+	default:
+		if values["host"] == "" || values["port"] == "" {
+			return DatasourceTestResult{Message: "该类型数据源需提供 host 和 port 参数才能测试连接"}
+		}
+		addr := net.JoinHostPort(values["host"], values["port"])
+		err := dialTCP(addr)
+		return buildTestResult(start, err, "连接 "+addr+" 成功")
+	}
+}
+
+func buildTestResult(start time.Time, err error, okMessage string) DatasourceTestResult {
+	result := DatasourceTestResult{LatencyMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Message = "连接失败: " + err.Error()
+		return result
+	}
+	result.Success = true
+	result.Message = okMessage
+	return result
+}
+
+// datasourceValues 合并非敏感参数与解密后的敏感参数
+func datasourceValues(ds seatunnelModel.Datasource) (map[string]string, error) {
+	values := decodeStringMap(ds.Options)
+	for k, encrypted := range decodeStringMap(ds.Secrets) {
+		plain, err := utils.DecryptAES(encrypted)
+		if err != nil {
+			return nil, err
+		}
+		values[k] = plain
+	}
+	return values, nil
+}
+
+func encryptSecrets(existing map[string]string, updates map[string]string) (map[string]string, error) {
+	for k, v := range updates {
+		if v == "" {
+			delete(existing, k)
+			continue
+		}
+		encrypted, err := utils.EncryptAES(v)
+		if err != nil {
+			return nil, fmt.Errorf("敏感参数加密失败: %v", err)
+		}
+		existing[k] = encrypted
+	}
+	return existing, nil
+}
+
+// escapeConfigValue 按 JSON/HOCON 字符串规则转义，避免值中的引号破坏配置
+func escapeConfigValue(v string) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	return string(b[1 : len(b)-1])
+}
+
+var jdbcDefaultPorts = map[string]string{
+	"mysql":      "3306",
+	"mariadb":    "3306",
+	"postgresql": "5432",
+	"oracle":     "1521",
+	"sqlserver":  "1433",
+	"clickhouse": "8123",
+}
+
+// jdbcAddress 从 jdbc:<子协议>://host:port/... 中解析地址
+func jdbcAddress(url string) (string, error) {
+	m := jdbcURLPattern.FindStringSubmatch(url)
+	if m == nil {
+		return "", fmt.Errorf("无法解析 JDBC URL: %s", url)
+	}
+	port := m[3]
+	if port == "" {
+		port = jdbcDefaultPorts[m[1]]
+	}
+	if port == "" {
+		return "", fmt.Errorf("JDBC URL 未指定端口: %s", url)
+	}
+	return net.JoinHostPort(m[2], port), nil
+}
+
+func dialTCP(addr string) error {
+	conn, err := net.DialTimeout("tcp", addr, 5*time.Second)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+func checkHTTP(url, username, password string) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	if username != "" {
+		req.SetBasicAuth(username, password)
+	}
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func(body io.ReadCloser) {
+		closeErr := body.Close()
+		if closeErr != nil {
+			fmt.Printf("关闭响应体失败: %v\n", closeErr)
+		}
+	}(resp.Body)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("状态码: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func splitHosts(s string) []string {
+	var hosts []string
+	for _, h := range strings.Split(s, ",") {
+		if h = strings.TrimSpace(h); h != "" {
+			hosts = append(hosts, h)
+		}
+	}
+	return hosts
+}
+
+func decodeStringMap(s string) map[string]string {
+	m := map[string]string{}
+	if s != "" {
+		_ = json.Unmarshal([]byte(s), &m)
+	}
+	return m
+}
+
+func encodeStringMap(m map[string]string) string {
+	if len(m) == 0 {
+		return ""
+	}
+	b, _ := json.Marshal(m)
+	return string(b)
+}
@@ -0,0 +1,34 @@
+package seatunnel
+
+import (
+	"octoops/internal/event"
+	seatunnelModel "octoops/internal/model/seatunnel"
+	"time"
+)
+
+// publishJobStatusChanged 作业状态发生变化时发布持久化事件
+func publishJobStatusChanged(task seatunnelModel.EtlTask, oldStatus, status string) {
+	if status == oldStatus {
+		return
+	}
+	event.PublishDurable(event.JobStatusChanged{
+		TaskID:    task.ID,
+		TaskName:  task.Name,
+		TaskType:  task.TaskType,
+		OldStatus: oldStatus,
+		NewStatus: status,
+		At:        time.Now(),
+	})
+}
+
+// RegisterEventHandlers 注册 ETL 相关订阅者：任务执行日志
+func RegisterEventHandlers() {
+	event.On("seatunnel.task_log", func(ev event.TaskRunFinished) error {
+		if ev.Source != event.SourceEtl {
+			return nil
+		}
+		task := seatunnelModel.EtlTask{ID: ev.TaskID, Name: ev.TaskName, TaskType: ev.TaskType}
+		WriteTaskLogWithTrigger(task, []byte(ev.Result), ev.Status, ev.Trigger)
+		return nil
+	})
+}
@@ -0,0 +1,62 @@
+package seatunnel
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"octoops/internal/config"
+	"strings"
+	"time"
+)
+
+var ErrUnknownCluster = errors.New("未配置的 SeaTunnel 集群")
+
+// engineURL 返回集群对应的 SeaTunnel API 地址，cluster 为空时使用默认集群
+func engineURL(cluster string) (string, error) {
+	url, ok := config.SeatunnelClusterURL(cluster)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownCluster, cluster)
+	}
+	return url, nil
+}
+
+// ValidateCluster 校验任务或模板指定的集群已配置
+func ValidateCluster(cluster string) error {
+	_, err := engineURL(cluster)
+	return err
+}
+
+// StopSeatunnelJob 调用 SeaTunnel 停止作业，返回响应体和状态码
+func StopSeatunnelJob(cluster, jobID string, isStopWithSavePoint bool) ([]byte, int, error) {
+	baseURL, err := engineURL(cluster)
+	if err != nil {
+		return nil, 0, err
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"jobId":               jobID,
+		"isStopWithSavePoint": isStopWithSavePoint,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("序列化停止作业请求失败: %v", err)
+	}
+	url := baseURL + "/stop-job"
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(url, "application/json", strings.NewReader(string(body)))
+	if err != nil {
+		return nil, 0, fmt.Errorf("停止作业失败: %v", err)
+	}
+	defer func(body io.ReadCloser) {
+		closeErr := body.Close()
+		if closeErr != nil {
+			fmt.Printf("关闭响应体失败: %v\n", closeErr)
+		}
+	}(resp.Body)
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return respBody, resp.StatusCode, fmt.Errorf("停止作业失败，状态码: %d, 响应: %s", resp.StatusCode, string(respBody))
+	}
+	return respBody, resp.StatusCode, nil
+}
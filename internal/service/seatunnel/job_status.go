@@ -6,21 +6,26 @@ import (
 	"io"
 	"log"
 	"net/http"
-	"octoops/internal/config"
 	"time"
 )
 
 // JobStatusResult Seatunnel 作业状态结构体
 type JobStatusResult struct {
-	JobStatus  string `json:"jobStatus"`
-	FinishTime string `json:"finishTime"`
-	JobId      string `json:"jobId"`
-	JobName    string `json:"jobName"`
+	JobStatus  string                 `json:"jobStatus"`
+	FinishTime string                 `json:"finishTime"`
+	JobId      string                 `json:"jobId"`
+	JobName    string                 `json:"jobName"`
+	ErrorMsg   string                 `json:"errorMsg"`
+	Metrics    map[string]interface{} `json:"metrics"`
 }
 
 // QuerySeatunnelJobStatus 查询 seatunnel 作业状态
-func QuerySeatunnelJobStatus(jobId string) JobStatusResult {
-	url := config.SeatunnelBaseURL + "/job-info/" + jobId
+func QuerySeatunnelJobStatus(cluster, jobId string) JobStatusResult {
+	baseURL, err := engineURL(cluster)
+	if err != nil {
+		return JobStatusResult{JobStatus: "UNKNOWN"}
+	}
+	url := baseURL + "/job-info/" + jobId
 	client := &http.Client{Timeout: 10 * time.Second}
 	resp, err := client.Get(url)
 	if err != nil {
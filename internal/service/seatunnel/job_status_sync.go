@@ -14,16 +14,14 @@ func SyncAllJobStatus() {
 	for _, task := range tasks {
 		if task.JobID != nil && *task.JobID != "" {
 			oldStatus := task.JobStatus
-			result := QuerySeatunnelJobStatus(*task.JobID)
+			result := QuerySeatunnelJobStatus(task.Cluster, *task.JobID)
 			status := result.JobStatus
 			postgres.DB.Model(&task).Update("job_status", status)
 			if result.FinishTime != "" {
 				postgres.DB.Model(&task).Update("finish_time", result.FinishTime)
 			}
-			// 状态变为FAILED，alert_group不为空则通知
-			if oldStatus != "FAILED" && status == "FAILED" {
-				SendTaskAlert(task, status)
-			}
+			// 状态变化通过事件总线通知告警、实时推送等订阅者
+			publishJobStatusChanged(task, oldStatus, status)
 		}
 	}
 	log.Printf("[Scheduler] 完成同步作业状态")
@@ -40,7 +38,7 @@ func SyncJobStatusByTaskID(taskID uint) (string, error) {
 	}
 
 	oldStatus := task.JobStatus
-	result := QuerySeatunnelJobStatus(*task.JobID)
+	result := QuerySeatunnelJobStatus(task.Cluster, *task.JobID)
 	status := result.JobStatus
 	if status == "" {
 		status = "UNKNOWN"
@@ -56,8 +54,6 @@ func SyncJobStatusByTaskID(taskID uint) (string, error) {
 		return "", fmt.Errorf("更新任务状态失败: %v", err)
 	}
 
-	if oldStatus != "FAILED" && status == "FAILED" {
-		SendTaskAlert(task, status)
-	}
+	publishJobStatusChanged(task, oldStatus, status)
 	return status, nil
 }
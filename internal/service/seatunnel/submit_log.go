@@ -8,7 +8,6 @@ import (
 	"log"
 	"net/http"
 	"net/url"
-	"octoops/internal/config"
 	"octoops/internal/infra/postgres"
 	seatunnelModel "octoops/internal/model/seatunnel"
 	taskModel "octoops/internal/model/task"
@@ -17,8 +16,8 @@ import (
 	"time"
 )
 
-// SubmitJobInternal 内部提交作业方法
-func SubmitJobInternal(taskID uint, isStartWithSavePoint bool) ([]byte, error) {
+// SubmitJobInternal 内部提交作业方法，vars 为本次运行变量，会替换配置中的 ${变量名} 占位符
+func SubmitJobInternal(taskID uint, isStartWithSavePoint bool, vars map[string]string) ([]byte, error) {
 	var task seatunnelModel.EtlTask
 	if err := postgres.DB.First(&task, taskID).Error; err != nil {
 		return nil, fmt.Errorf("任务不存在: %v", err)
@@ -44,10 +43,29 @@ func SubmitJobInternal(taskID uint, isStartWithSavePoint bool) ([]byte, error) {
 	if isStartWithSavePoint {
 		params.Set("isStartWithSavePoint", "true")
 	}
-	requestURL := config.SeatunnelBaseURL + "/submit-job?" + params.Encode()
+	baseURL, err := engineURL(task.Cluster)
+	if err != nil {
+		return nil, err
+	}
+	requestURL := baseURL + "/submit-job?" + params.Encode()
+
+	// 运行变量与数据源引用仅在提交时注入，不回写任务配置；
+	// 先解析数据源引用再替换运行变量，变量值中的 ${datasource.*} 不会被展开为敏感参数
+	jobConfig, err := ResolveDatasourceReferences(task.Config)
+	if err != nil {
+		return nil, fmt.Errorf("解析任务配置失败: %v", err)
+	}
+	jobConfig = RenderConfigVariables(jobConfig, vars)
+	// 存储时未能加密（如含数据源引用）的配置，在注入后加密再提交，避免明文敏感参数发送到引擎
+	if task.EncryptConfig && !task.ConfigEncrypted {
+		jobConfig, err = EncryptConfigViaEngine(task.Cluster, jobConfig, format, task.ShadeIdentifier)
+		if err != nil {
+			return nil, fmt.Errorf("加密任务配置失败: %v", err)
+		}
+	}
 
 	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Post(requestURL, "text/plain; charset=utf-8", strings.NewReader(task.Config))
+	resp, err := client.Post(requestURL, "text/plain; charset=utf-8", strings.NewReader(jobConfig))
 	if err != nil {
 		return nil, fmt.Errorf("提交作业失败: %v", err)
 	}
@@ -71,23 +89,28 @@ func WriteTaskLog(task seatunnelModel.EtlTask, octoopsRespBody []byte) {
 	WriteTaskLogWithStatus(task, octoopsRespBody, "success")
 }
 
-// UpdateJobIdFromResponse 从响应体中提取 jobId 并更新到数据库
-func UpdateJobIdFromResponse(taskID uint, octoopsRespBody []byte) {
+// ParseJobIDFromResponse 从提交作业的响应体中提取 jobId
+func ParseJobIDFromResponse(octoopsRespBody []byte) string {
 	var resultMap map[string]interface{}
 	decoder := json.NewDecoder(bytes.NewReader(octoopsRespBody))
 	decoder.UseNumber()
 	if err := decoder.Decode(&resultMap); err != nil {
 		log.Printf("[DEBUG] 解析响应失败: %v", err)
-		return
+		return ""
 	}
 
-	jobID := ""
 	if raw, ok := resultMap["jobId"]; ok {
-		jobID = normalizeJobID(raw)
-	} else if raw, ok := resultMap["job_id"]; ok {
-		jobID = normalizeJobID(raw)
+		return normalizeJobID(raw)
 	}
+	if raw, ok := resultMap["job_id"]; ok {
+		return normalizeJobID(raw)
+	}
+	return ""
+}
 
+// UpdateJobIdFromResponse 从响应体中提取 jobId 并更新到数据库
+func UpdateJobIdFromResponse(taskID uint, octoopsRespBody []byte) {
+	jobID := ParseJobIDFromResponse(octoopsRespBody)
 	if jobID != "" {
 		if err := postgres.DB.Model(&seatunnelModel.EtlTask{}).Where("id = ?", taskID).Update("job_id", jobID).Error; err != nil {
 			log.Printf("[ERROR] 更新 jobId 失败: taskID=%d, jobId=%s, error=%v", taskID, jobID, err)
@@ -112,6 +135,11 @@ func normalizeJobID(raw interface{}) string {
 
 // WriteTaskLogWithStatus 写入作业日志（指定状态）
 func WriteTaskLogWithStatus(task seatunnelModel.EtlTask, octoopsRespBody []byte, status string) {
+	WriteTaskLogWithTrigger(task, octoopsRespBody, status, "")
+}
+
+// WriteTaskLogWithTrigger 写入作业日志并记录触发来源
+func WriteTaskLogWithTrigger(task seatunnelModel.EtlTask, octoopsRespBody []byte, status, trigger string) {
 	var resultMap map[string]interface{}
 	_ = json.Unmarshal(octoopsRespBody, &resultMap)
 	taskName := task.Name
@@ -119,8 +147,9 @@ func WriteTaskLogWithStatus(task seatunnelModel.EtlTask, octoopsRespBody []byte,
 		taskName = v
 	}
 	postgres.DB.Create(&taskModel.TaskLog{
-		TaskName: taskName,
-		Result:   string(octoopsRespBody),
-		Status:   status,
+		TaskName:      taskName,
+		Result:        string(octoopsRespBody),
+		Status:        status,
+		TriggerSource: trigger,
 	})
 }
@@ -0,0 +1,111 @@
+package seatunnel
+
+import (
+	"errors"
+	"log"
+	"octoops/internal/infra/postgres"
+	seatunnelModel "octoops/internal/model/seatunnel"
+	"time"
+)
+
+var (
+	ErrTaskRunning           = errors.New("实时数据集成运行中，不允许重复提交作业，请先停止当前作业")
+	ErrSavePointJobIDMissing = errors.New("使用 SavePoint 启动时必须有已存在的 job_id，请先正常启动任务")
+	ErrJobIDEmpty            = errors.New("jobId is empty in database")
+)
+
+// WaitForTaskStatus 轮询同步任务作业状态，直到 shouldStop 返回 true 或达到最大次数
+func WaitForTaskStatus(taskID uint, attempts int, interval time.Duration, shouldStop func(string) bool) string {
+	jobStatus := "UNKNOWN"
+	for i := 0; i < attempts; i++ {
+		status, syncErr := SyncJobStatusByTaskID(taskID)
+		if syncErr != nil {
+			log.Printf("[ETL] 同步作业状态失败: taskID=%d, error=%v", taskID, syncErr)
+		} else {
+			jobStatus = status
+			if shouldStop(status) {
+				return jobStatus
+			}
+		}
+		if i < attempts-1 {
+			time.Sleep(interval)
+		}
+	}
+	return jobStatus
+}
+
+// StartTask 提交任务作业并等待作业进入明确状态，返回作业状态和 SeaTunnel 响应
+func StartTask(task seatunnelModel.EtlTask, isStartWithSavePoint bool) (string, []byte, error) {
+	isStartWithSavePoint, err := checkStartTask(task, isStartWithSavePoint)
+	if err != nil {
+		return "", nil, err
+	}
+	respBody, err := submitTask(task, isStartWithSavePoint)
+	if err != nil {
+		return "", respBody, err
+	}
+
+	// 提交成功后等待作业进入明确状态
+	jobStatus := WaitForTaskStatus(task.ID, 15, time.Second, startSettled)
+	return jobStatus, respBody, nil
+}
+
+// checkStartTask 校验任务是否可以提交，返回修正后的 SavePoint 参数
+func checkStartTask(task seatunnelModel.EtlTask, isStartWithSavePoint bool) (bool, error) {
+	if task.TaskType == "stream" && task.JobStatus == "RUNNING" {
+		return false, ErrTaskRunning
+	}
+	// 只有流式任务支持 SavePoint
+	if task.TaskType != "stream" {
+		isStartWithSavePoint = false
+	}
+	if isStartWithSavePoint && (task.JobID == nil || *task.JobID == "") {
+		return false, ErrSavePointJobIDMissing
+	}
+	return isStartWithSavePoint, nil
+}
+
+// submitTask 提交作业并回写最后运行时间和 jobId
+func submitTask(task seatunnelModel.EtlTask, isStartWithSavePoint bool) ([]byte, error) {
+	respBody, err := SubmitJobInternal(task.ID, isStartWithSavePoint, nil)
+	if err != nil {
+		log.Printf("[ETL] 提交作业失败: taskID=%d, type=%s, error=%v", task.ID, task.TaskType, err)
+		return respBody, err
+	}
+
+	// 更新最后运行时间
+	postgres.DB.Model(&task).Update("last_run_time", time.Now())
+
+	// 从响应中提取 jobId 并更新到数据库
+	UpdateJobIdFromResponse(task.ID, respBody)
+
+	log.Printf("[ETL] 提交作业成功: taskID=%d, type=%s, isStartWithSavePoint=%v, result=%s", task.ID, task.TaskType, isStartWithSavePoint, string(respBody))
+	return respBody, nil
+}
+
+// startSettled 提交后作业是否已进入明确状态
+func startSettled(status string) bool {
+	return status == "RUNNING" || status == "FAILED" || status == "FINISHED" || status == "CANCEL"
+}
+
+// stopSettled 停止后作业是否已退出 RUNNING
+func stopSettled(status string) bool {
+	return status != "" && status != "RUNNING" && status != "UNKNOWN"
+}
+
+// StopTask 停止任务作业并等待状态退出 RUNNING，返回作业状态、SeaTunnel 响应和状态码
+func StopTask(task seatunnelModel.EtlTask, isStopWithSavePoint bool) (string, []byte, int, error) {
+	if task.JobID == nil || *task.JobID == "" {
+		return "", nil, 0, ErrJobIDEmpty
+	}
+	respBody, statusCode, err := StopSeatunnelJob(task.Cluster, *task.JobID, isStopWithSavePoint)
+	if err != nil {
+		log.Printf("[ETL] 停止作业失败: taskID=%d, jobId=%s, statusCode=%d, error=%v", task.ID, *task.JobID, statusCode, err)
+		return "", respBody, statusCode, err
+	}
+
+	jobStatus := WaitForTaskStatus(task.ID, 15, time.Second, stopSettled)
+
+	log.Printf("[ETL] 停止作业成功: taskID=%d, jobId=%s, jobStatus=%s", task.ID, *task.JobID, jobStatus)
+	return jobStatus, respBody, statusCode, nil
+}
@@ -2,7 +2,12 @@ package seatunnel
 
 import (
 	"octoops/internal/infra/postgres"
+	alertModel "octoops/internal/model/alert"
 	seatunnelModel "octoops/internal/model/seatunnel"
+	taskModel "octoops/internal/model/task"
+	alertService "octoops/internal/service/alert"
+
+	"gorm.io/gorm"
 )
 
 type TaskListFilter struct {
@@ -47,8 +52,19 @@ func ListTasks(filter TaskListFilter) ([]seatunnelModel.EtlTask, int64, error) {
 	return tasks, total, nil
 }
 
+// CreateTask 创建任务，并按 AlertGroup 中的告警组 ID 建立告警组关联
 func CreateTask(task *seatunnelModel.EtlTask) error {
-	return postgres.DB.Create(task).Error
+	return CreateTaskTx(postgres.DB, task)
+}
+
+// CreateTaskTx 在指定事务中创建任务并建立告警组关联
+func CreateTaskTx(tx *gorm.DB, task *seatunnelModel.EtlTask) error {
+	if err := tx.Create(task).Error; err != nil {
+		return err
+	}
+	groups, err := alertService.SyncTaskGroups(tx, alertModel.TaskSourceETL, task.ID, task.AlertGroup)
+	task.AlertGroup = groups
+	return err
 }
 
 func GetTaskByID(id interface{}) (seatunnelModel.EtlTask, error) {
@@ -57,10 +73,39 @@ func GetTaskByID(id interface{}) (seatunnelModel.EtlTask, error) {
 	return task, err
 }
 
+// UpdateTask 更新任务，updates 包含 alert_group 时同步告警组关联，已有关联的设置保持不变
 func UpdateTask(task *seatunnelModel.EtlTask, updates map[string]interface{}) error {
-	return postgres.DB.Model(task).Updates(updates).Error
+	return UpdateTaskTx(postgres.DB, task, updates)
+}
+
+// UpdateTaskTx 在指定事务中更新任务并同步告警组关联
+func UpdateTaskTx(tx *gorm.DB, task *seatunnelModel.EtlTask, updates map[string]interface{}) error {
+	groups, syncGroups := updates["alert_group"].(string)
+	delete(updates, "alert_group")
+	if len(updates) > 0 {
+		if err := tx.Model(task).Updates(updates).Error; err != nil {
+			return err
+		}
+	}
+	if !syncGroups {
+		return nil
+	}
+	groups, err := alertService.SyncTaskGroups(tx, alertModel.TaskSourceETL, task.ID, groups)
+	task.AlertGroup = groups
+	updates["alert_group"] = groups
+	return err
 }
 
+// DeleteTask 在同一事务中删除任务及其告警组关联和入站触发器
 func DeleteTask(task *seatunnelModel.EtlTask) error {
-	return postgres.DB.Delete(task).Error
+	return postgres.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(task).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("target_type = ? AND target_id = ?", taskModel.TriggerTargetEtl, task.ID).
+			Delete(&taskModel.TaskTrigger{}).Error; err != nil {
+			return err
+		}
+		return alertService.DeleteTaskGroupsTx(tx, alertModel.TaskSourceETL, task.ID)
+	})
 }
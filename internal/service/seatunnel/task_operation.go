@@ -0,0 +1,277 @@
+package seatunnel
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"octoops/internal/infra/postgres"
+	seatunnelModel "octoops/internal/model/seatunnel"
+	"sync"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+const (
+	OperationSubmit = "submit"
+	OperationStop   = "stop"
+
+	// 后台跟踪作业状态的最长时间，超过后操作标记为 timeout，作业状态仍由定时同步更新
+	operationFollowAttempts = 120
+	operationFollowInterval = time.Second
+
+	// 进行中的操作超过该时间未更新视为所在实例已退出，由其他实例接管；跟踪期间按 operationHeartbeatEvery 次轮询续期
+	operationStaleAfter     = 5 * time.Minute
+	operationHeartbeatEvery = 30
+)
+
+var resumeOperationsOnce sync.Once
+
+var ErrOperationInProgress = errors.New("该任务已有进行中的提交/停止操作，请等待其完成")
+
+// IsOperationFinished 操作是否已进入终态
+func IsOperationFinished(status string) bool {
+	return status == "succeeded" || status == "failed" || status == "timeout"
+}
+
+// SubmitTaskAsync 校验后创建提交操作并立即返回，作业提交与状态跟踪在后台执行
+func SubmitTaskAsync(task seatunnelModel.EtlTask, isStartWithSavePoint bool, operator string) (seatunnelModel.TaskOperation, error) {
+	isStartWithSavePoint, err := checkStartTask(task, isStartWithSavePoint)
+	if err != nil {
+		return seatunnelModel.TaskOperation{}, err
+	}
+	op, err := createTaskOperation(task, OperationSubmit, isStartWithSavePoint, operator)
+	if err != nil {
+		return op, err
+	}
+	go runSubmitOperation(op, task)
+	return op, nil
+}
+
+// StopTaskAsync 校验后创建停止操作并立即返回，作业停止与状态跟踪在后台执行
+func StopTaskAsync(task seatunnelModel.EtlTask, isStopWithSavePoint bool, operator string) (seatunnelModel.TaskOperation, error) {
+	if task.JobID == nil || *task.JobID == "" {
+		return seatunnelModel.TaskOperation{}, ErrJobIDEmpty
+	}
+	op, err := createTaskOperation(task, OperationStop, isStopWithSavePoint, operator)
+	if err != nil {
+		return op, err
+	}
+	go runStopOperation(op, task)
+	return op, nil
+}
+
+func createTaskOperation(task seatunnelModel.EtlTask, action string, savePoint bool, operator string) (seatunnelModel.TaskOperation, error) {
+	var active int64
+	if err := postgres.DB.Model(&seatunnelModel.TaskOperation{}).
+		Where("task_id = ? AND status IN ?", task.ID, []string{"pending", "running"}).
+		Count(&active).Error; err != nil {
+		return seatunnelModel.TaskOperation{}, err
+	}
+	if active > 0 {
+		return seatunnelModel.TaskOperation{}, ErrOperationInProgress
+	}
+	op := seatunnelModel.TaskOperation{
+		TaskID:    task.ID,
+		TaskName:  task.Name,
+		TaskType:  task.TaskType,
+		Action:    action,
+		Status:    "pending",
+		SavePoint: savePoint,
+		CreatedBy: operator,
+	}
+	if task.JobID != nil {
+		op.JobID = *task.JobID
+	}
+	// 并发请求同时通过上面的检查时，由 idx_task_operation_active 部分唯一索引保证只有一个操作写入
+	result := postgres.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&op)
+	if result.Error != nil {
+		return op, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return seatunnelModel.TaskOperation{}, ErrOperationInProgress
+	}
+	return op, nil
+}
+
+// ListTaskOperations 操作列表，taskID 为 0 时不按任务过滤
+func ListTaskOperations(taskID uint, page, pageSize int) ([]seatunnelModel.TaskOperation, int64, error) {
+	var ops []seatunnelModel.TaskOperation
+	var total int64
+	db := postgres.DB.Model(&seatunnelModel.TaskOperation{})
+	if taskID > 0 {
+		db = db.Where("task_id = ?", taskID)
+	}
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := db.Order("id desc").Offset((page - 1) * pageSize).Limit(pageSize).Find(&ops).Error
+	return ops, total, err
+}
+
+// GetTaskOperation 查询单个操作
+func GetTaskOperation(id interface{}) (seatunnelModel.TaskOperation, error) {
+	var op seatunnelModel.TaskOperation
+	err := postgres.DB.First(&op, id).Error
+	return op, err
+}
+
+// ResumeTaskOperations 服务启动时及之后定期接管已失联实例遗留的操作：已提交的继续跟踪，未确认提交的标记失败；
+// 其他实例仍在执行（未超过 operationStaleAfter）的操作不受影响
+func ResumeTaskOperations() {
+	resumeOperationsOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(operationStaleAfter)
+			defer ticker.Stop()
+			for {
+				resumeStaleTaskOperations()
+				<-ticker.C
+			}
+		}()
+	})
+}
+
+func resumeStaleTaskOperations() {
+	cutoff := time.Now().Add(-operationStaleAfter)
+	var ops []seatunnelModel.TaskOperation
+	if err := postgres.DB.Where("status IN ? AND updated_at < ?", []string{"pending", "running"}, cutoff).Find(&ops).Error; err != nil {
+		log.Printf("[ETL] 加载未完成的任务操作失败: %v", err)
+		return
+	}
+	for _, op := range ops {
+		if op.Status == "pending" {
+			// 条件更新，避免覆盖期间已由原实例完成提交的操作
+			res := postgres.DB.Model(&seatunnelModel.TaskOperation{}).
+				Where("id = ? AND status = ? AND updated_at < ?", op.ID, "pending", cutoff).
+				Updates(map[string]interface{}{
+					"status":      "failed",
+					"message":     "执行实例中断，操作结果未知，请检查作业状态后重试",
+					"finished_at": time.Now(),
+				})
+			if res.Error != nil {
+				log.Printf("[ETL] 更新任务操作失败: id=%d, error=%v", op.ID, res.Error)
+			}
+			continue
+		}
+		// 多个实例同时接管时只有续期成功的实例继续跟踪
+		res := postgres.DB.Model(&seatunnelModel.TaskOperation{}).
+			Where("id = ? AND status = ? AND updated_at < ?", op.ID, "running", cutoff).
+			Update("updated_at", time.Now())
+		if res.Error != nil || res.RowsAffected == 0 {
+			continue
+		}
+		log.Printf("[ETL] 恢复任务操作跟踪: id=%d, taskID=%d, action=%s", op.ID, op.TaskID, op.Action)
+		go func(op seatunnelModel.TaskOperation) {
+			defer recoverTaskOperation(op)
+			followTaskOperation(op)
+		}(op)
+	}
+}
+
+func runSubmitOperation(op seatunnelModel.TaskOperation, task seatunnelModel.EtlTask) {
+	defer recoverTaskOperation(op)
+	respBody, err := submitTask(task, op.SavePoint)
+	if err != nil {
+		finishTaskOperation(op.ID, "failed", "", "提交作业失败: "+err.Error())
+		return
+	}
+	op = markTaskOperationRunning(op, respBody)
+	followTaskOperation(op)
+}
+
+func runStopOperation(op seatunnelModel.TaskOperation, task seatunnelModel.EtlTask) {
+	defer recoverTaskOperation(op)
+	respBody, statusCode, err := StopSeatunnelJob(task.Cluster, *task.JobID, op.SavePoint)
+	if err != nil {
+		log.Printf("[ETL] 停止作业失败: taskID=%d, jobId=%s, statusCode=%d, error=%v", task.ID, *task.JobID, statusCode, err)
+		message := "停止作业失败: " + err.Error()
+		if statusCode == 0 {
+			message = "无法连接到 Seatunnel 服务，请检查服务是否已启动且网络正常"
+		}
+		postgres.DB.Model(&seatunnelModel.TaskOperation{}).Where("id = ?", op.ID).Update("result", string(respBody))
+		finishTaskOperation(op.ID, "failed", "", message)
+		return
+	}
+	op = markTaskOperationRunning(op, respBody)
+	followTaskOperation(op)
+}
+
+// recoverTaskOperation 后台操作 panic 时标记失败，避免操作一直停留在进行中并阻塞后续操作
+func recoverTaskOperation(op seatunnelModel.TaskOperation) {
+	if r := recover(); r != nil {
+		log.Printf("[ETL] 任务操作异常: id=%d, taskID=%d, action=%s, panic=%v", op.ID, op.TaskID, op.Action, r)
+		finishTaskOperation(op.ID, "failed", "", fmt.Sprintf("操作执行异常: %v", r))
+	}
+}
+
+// markTaskOperationRunning 作业已提交/已发出停止请求，进入状态跟踪阶段
+func markTaskOperationRunning(op seatunnelModel.TaskOperation, respBody []byte) seatunnelModel.TaskOperation {
+	updates := map[string]interface{}{"status": "running", "result": string(respBody)}
+	var task seatunnelModel.EtlTask
+	if err := postgres.DB.Select("job_id").First(&task, op.TaskID).Error; err == nil && task.JobID != nil {
+		updates["job_id"] = *task.JobID
+		op.JobID = *task.JobID
+	}
+	postgres.DB.Model(&seatunnelModel.TaskOperation{}).Where("id = ?", op.ID).Updates(updates)
+	op.Status = "running"
+	return op
+}
+
+// touchTaskOperation 续期跟踪中的操作，表明所在实例仍在处理，避免被其他实例接管
+func touchTaskOperation(id uint) {
+	postgres.DB.Model(&seatunnelModel.TaskOperation{}).Where("id = ? AND status = ?", id, "running").Update("updated_at", time.Now())
+}
+
+// followTaskOperation 轮询作业状态并写回操作，供轮询接口和 SSE 推送读取
+func followTaskOperation(op seatunnelModel.TaskOperation) {
+	settled := startSettled
+	if op.Action == OperationStop {
+		settled = stopSettled
+	}
+	lastStatus := ""
+	for i := 0; i < operationFollowAttempts; i++ {
+		status, err := SyncJobStatusByTaskID(op.TaskID)
+		if err != nil {
+			log.Printf("[ETL] 同步作业状态失败: operationID=%d, taskID=%d, error=%v", op.ID, op.TaskID, err)
+		} else {
+			if status != lastStatus {
+				lastStatus = status
+				postgres.DB.Model(&seatunnelModel.TaskOperation{}).Where("id = ?", op.ID).Update("job_status", status)
+			} else if i%operationHeartbeatEvery == 0 {
+				touchTaskOperation(op.ID)
+			}
+			if settled(status) {
+				if op.Action == OperationSubmit && (status == "FAILED" || status == "CANCEL") {
+					finishTaskOperation(op.ID, "failed", status, "作业状态为 "+status)
+				} else {
+					finishTaskOperation(op.ID, "succeeded", status, "")
+				}
+				return
+			}
+		}
+		if err != nil && i%operationHeartbeatEvery == 0 {
+			touchTaskOperation(op.ID)
+		}
+		time.Sleep(operationFollowInterval)
+	}
+	finishTaskOperation(op.ID, "timeout", lastStatus, fmt.Sprintf("等待作业状态超时（%s）", operationFollowInterval*operationFollowAttempts))
+}
+
+func finishTaskOperation(id uint, status, jobStatus, message string) {
+	if runes := []rune(message); len(runes) > 500 {
+		message = string(runes[:500])
+	}
+	updates := map[string]interface{}{
+		"status":      status,
+		"message":     message,
+		"finished_at": time.Now(),
+	}
+	if jobStatus != "" {
+		updates["job_status"] = jobStatus
+	}
+	if err := postgres.DB.Model(&seatunnelModel.TaskOperation{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+		log.Printf("[ETL] 更新任务操作失败: id=%d, error=%v", id, err)
+		return
+	}
+	log.Printf("[ETL] 任务操作结束: id=%d, status=%s, jobStatus=%s", id, status, jobStatus)
+}
@@ -0,0 +1,473 @@
+package seatunnel
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"octoops/internal/infra/postgres"
+	alertModel "octoops/internal/model/alert"
+	seatunnelModel "octoops/internal/model/seatunnel"
+	alertService "octoops/internal/service/alert"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTemplateInvalid = errors.New("模板无效")
+	ErrTemplateInUse   = errors.New("模板已被任务使用")
+)
+
+var templateParamNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// maxDiffLines 超过该行数时不做逐行比对，直接整体替换
+const maxDiffLines = 2000
+
+// TemplateParam 模板参数定义
+type TemplateParam struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Default     string `json:"default"`
+	Required    bool   `json:"required"`
+}
+
+// TemplateRequest 新增/更新模板请求
+type TemplateRequest struct {
+	Name         string          `json:"name"`
+	Description  string          `json:"description"`
+	TaskType     string          `json:"task_type"`
+	Config       string          `json:"config"`
+	ConfigFormat string          `json:"config_format"`
+	Params       []TemplateParam `json:"params"`
+	CronExpr     string          `json:"cron_expr"`
+	AlertGroup   string          `json:"alert_group"`
+	Cluster      string          `json:"cluster"`
+}
+
+// TemplateView 模板详情，参数定义解析为数组
+type TemplateView struct {
+	seatunnelModel.EtlTaskTemplate
+	Params []TemplateParam `json:"params"`
+}
+
+// TemplateTaskRequest 从模板创建任务请求，cron/告警组/集群为空时使用模板默认值
+type TemplateTaskRequest struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Params      map[string]string `json:"params"`
+	CronExpr    string            `json:"cron_expr"`
+	AlertGroup  string            `json:"alert_group"`
+	Cluster     string            `json:"cluster"`
+	Status      int               `json:"status"`
+}
+
+// PropagateResult 模板变更下发到单个任务的结果
+type PropagateResult struct {
+	TaskID   uint     `json:"task_id"`
+	TaskName string   `json:"task_name"`
+	Status   string   `json:"status"` // changed/unchanged/skipped/failed/updated
+	Diff     []string `json:"diff,omitempty"`
+	Message  string   `json:"message,omitempty"`
+}
+
+func ToTemplateView(tpl seatunnelModel.EtlTaskTemplate) TemplateView {
+	view := TemplateView{EtlTaskTemplate: tpl, Params: []TemplateParam{}}
+	if tpl.Params != "" {
+		_ = json.Unmarshal([]byte(tpl.Params), &view.Params)
+	}
+	return view
+}
+
+func ListTemplates(taskType, name string) ([]TemplateView, error) {
+	var list []seatunnelModel.EtlTaskTemplate
+	query := postgres.DB.Order("created_at desc")
+	if taskType != "" {
+		query = query.Where("task_type = ?", taskType)
+	}
+	if name != "" {
+		query = query.Where("name LIKE ?", "%"+name+"%")
+	}
+	if err := query.Find(&list).Error; err != nil {
+		return nil, err
+	}
+	views := make([]TemplateView, 0, len(list))
+	for _, tpl := range list {
+		views = append(views, ToTemplateView(tpl))
+	}
+	return views, nil
+}
+
+func GetTemplateByID(id interface{}) (seatunnelModel.EtlTaskTemplate, error) {
+	var tpl seatunnelModel.EtlTaskTemplate
+	err := postgres.DB.First(&tpl, id).Error
+	return tpl, err
+}
+
+func CreateTemplate(req TemplateRequest) (seatunnelModel.EtlTaskTemplate, error) {
+	params, err := validateTemplateRequest(req)
+	if err != nil {
+		return seatunnelModel.EtlTaskTemplate{}, err
+	}
+	tpl := seatunnelModel.EtlTaskTemplate{
+		Name:         req.Name,
+		Description:  req.Description,
+		TaskType:     req.TaskType,
+		Config:       req.Config,
+		ConfigFormat: req.ConfigFormat,
+		Params:       params,
+		CronExpr:     req.CronExpr,
+		AlertGroup:   req.AlertGroup,
+		Cluster:      req.Cluster,
+		Version:      1,
+	}
+	err = postgres.DB.Create(&tpl).Error
+	return tpl, err
+}
+
+// UpdateTemplate 更新模板，配置或参数变化时递增版本，已派生的任务需通过下发同步
+func UpdateTemplate(id interface{}, req TemplateRequest) (seatunnelModel.EtlTaskTemplate, error) {
+	tpl, err := GetTemplateByID(id)
+	if err != nil {
+		return tpl, err
+	}
+	if req.TaskType != tpl.TaskType {
+		return tpl, fmt.Errorf("%w: 任务类型不允许变更", ErrTemplateInvalid)
+	}
+	params, err := validateTemplateRequest(req)
+	if err != nil {
+		return tpl, err
+	}
+	updates := map[string]interface{}{
+		"name":          req.Name,
+		"description":   req.Description,
+		"config":        req.Config,
+		"config_format": req.ConfigFormat,
+		"params":        params,
+		"cron_expr":     req.CronExpr,
+		"alert_group":   req.AlertGroup,
+		"cluster":       req.Cluster,
+	}
+	if req.Config != tpl.Config || req.ConfigFormat != tpl.ConfigFormat || params != tpl.Params {
+		updates["version"] = tpl.Version + 1
+	}
+	if err := postgres.DB.Model(&tpl).Updates(updates).Error; err != nil {
+		return tpl, err
+	}
+	return GetTemplateByID(tpl.ID)
+}
+
+// DeleteTemplate 删除模板，存在派生任务时不允许删除
+func DeleteTemplate(id interface{}) error {
+	tpl, err := GetTemplateByID(id)
+	if err != nil {
+		return err
+	}
+	var count int64
+	postgres.DB.Model(&seatunnelModel.EtlTask{}).Where("template_id = ?", tpl.ID).Count(&count)
+	if count > 0 {
+		return fmt.Errorf("%w: %d 个任务", ErrTemplateInUse, count)
+	}
+	return postgres.DB.Delete(&tpl).Error
+}
+
+func validateTemplateRequest(req TemplateRequest) (string, error) {
+	if req.Name == "" || req.Config == "" {
+		return "", fmt.Errorf("%w: name 和 config 不能为空", ErrTemplateInvalid)
+	}
+	if req.TaskType != "stream" && req.TaskType != "batch" {
+		return "", fmt.Errorf("%w: 无效的任务类型 %s", ErrTemplateInvalid, req.TaskType)
+	}
+	if err := ValidateCluster(req.Cluster); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrTemplateInvalid, err)
+	}
+	seen := map[string]bool{}
+	for _, p := range req.Params {
+		if !templateParamNamePattern.MatchString(p.Name) {
+			return "", fmt.Errorf("%w: 参数名只能包含字母、数字和下划线: %s", ErrTemplateInvalid, p.Name)
+		}
+		if seen[p.Name] {
+			return "", fmt.Errorf("%w: 参数重复: %s", ErrTemplateInvalid, p.Name)
+		}
+		seen[p.Name] = true
+	}
+	if _, err := parseConfigTemplate(req.Config); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrTemplateInvalid, err)
+	}
+	if req.Params == nil {
+		req.Params = []TemplateParam{}
+	}
+	params, err := json.Marshal(req.Params)
+	return string(params), err
+}
+
+func parseConfigTemplate(config string) (*template.Template, error) {
+	return template.New("config").Option("missingkey=error").Parse(config)
+}
+
+// RenderTemplateConfig 使用参数渲染模板配置，未提供的参数使用默认值，必填参数缺失时报错
+// JSON 格式的配置中参数值按 JSON 字符串转义，避免引号等字符破坏配置结构
+func RenderTemplateConfig(tpl seatunnelModel.EtlTaskTemplate, values map[string]string) (string, error) {
+	defs := ToTemplateView(tpl).Params
+	escape := tpl.ConfigFormat == "" || tpl.ConfigFormat == "json"
+	data := map[string]string{}
+	defined := map[string]bool{}
+	for _, p := range defs {
+		defined[p.Name] = true
+		v, ok := values[p.Name]
+		if !ok || v == "" {
+			if p.Required && p.Default == "" {
+				return "", fmt.Errorf("%w: 缺少必填参数 %s", ErrTemplateInvalid, p.Name)
+			}
+			v = p.Default
+		}
+		if escape {
+			v = escapeConfigValue(v)
+		}
+		data[p.Name] = v
+	}
+	for name := range values {
+		if !defined[name] {
+			return "", fmt.Errorf("%w: 未定义的参数 %s", ErrTemplateInvalid, name)
+		}
+	}
+	t, err := parseConfigTemplate(tpl.Config)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrTemplateInvalid, err)
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrTemplateInvalid, err)
+	}
+	return sb.String(), nil
+}
+
+// NewTaskFromTemplate 根据模板和参数生成任务，由调用方保存
+func NewTaskFromTemplate(tpl seatunnelModel.EtlTaskTemplate, req TemplateTaskRequest) (seatunnelModel.EtlTask, error) {
+	config, err := RenderTemplateConfig(tpl, req.Params)
+	if err != nil {
+		return seatunnelModel.EtlTask{}, err
+	}
+	params, _ := json.Marshal(req.Params)
+	tplID := tpl.ID
+	task := seatunnelModel.EtlTask{
+		Name:            req.Name,
+		Description:     req.Description,
+		TaskType:        tpl.TaskType,
+		CronExpr:        firstNonEmpty(req.CronExpr, tpl.CronExpr),
+		Config:          config,
+		ConfigFormat:    tpl.ConfigFormat,
+		AlertGroup:      firstNonEmpty(req.AlertGroup, tpl.AlertGroup),
+		Cluster:         firstNonEmpty(req.Cluster, tpl.Cluster),
+		Status:          req.Status,
+		TemplateID:      &tplID,
+		TemplateParams:  string(params),
+		TemplateVersion: tpl.Version,
+	}
+	if task.Name == "" {
+		return task, fmt.Errorf("%w: 任务名称不能为空", ErrTemplateInvalid)
+	}
+	if err := ValidateCluster(task.Cluster); err != nil {
+		return task, err
+	}
+	return task, nil
+}
+
+// PropagateTemplate 将模板最新配置下发到派生任务，dryRun 时仅返回逐行差异
+// taskIDs 为空时下发到全部派生任务，GitOps 托管的任务跳过；
+// 仅下发配置，cron、告警组与集群在创建时可按任务覆盖，不随模板下发
+func PropagateTemplate(tplID interface{}, taskIDs []uint, dryRun bool) ([]PropagateResult, error) {
+	tpl, err := GetTemplateByID(tplID)
+	if err != nil {
+		return nil, err
+	}
+	var tasks []seatunnelModel.EtlTask
+	query := postgres.DB.Where("template_id = ?", tpl.ID)
+	if len(taskIDs) > 0 {
+		query = query.Where("id IN ?", taskIDs)
+	}
+	if err := query.Order("id asc").Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+
+	results := make([]PropagateResult, 0, len(tasks))
+	for _, task := range tasks {
+		result := PropagateResult{TaskID: task.ID, TaskName: task.Name}
+		if task.Managed {
+			result.Status = "skipped"
+			result.Message = "任务由 GitOps 目录管理"
+			results = append(results, result)
+			continue
+		}
+		var values map[string]string
+		if task.TemplateParams != "" {
+			_ = json.Unmarshal([]byte(task.TemplateParams), &values)
+		}
+		config, err := RenderTemplateConfig(tpl, values)
+		if err != nil {
+			result.Status = "failed"
+			result.Message = err.Error()
+			results = append(results, result)
+			continue
+		}
+
+		changed := config != task.Config || tpl.ConfigFormat != task.ConfigFormat
+		if task.ConfigEncrypted {
+			// 存储的是密文，无法逐行比对，按模板版本判断是否需要下发
+			changed = task.TemplateVersion < tpl.Version
+			result.Message = "配置已加密，无法预览差异"
+		} else if changed {
+			result.Diff = LineDiff(task.Config, config)
+		}
+		if !changed {
+			result.Status = "unchanged"
+			results = append(results, result)
+			continue
+		}
+		if dryRun {
+			result.Status = "changed"
+			results = append(results, result)
+			continue
+		}
+
+		updates := map[string]interface{}{
+			"config":           config,
+			"config_format":    tpl.ConfigFormat,
+			"template_version": tpl.Version,
+			// 模板渲染结果为完整明文，可替换已加密的配置
+			"config_plaintext": true,
+		}
+		if err := PrepareTaskConfigUpdate(task, updates); err != nil {
+			result.Status = "failed"
+			result.Message = err.Error()
+		} else if err := UpdateTask(&task, updates); err != nil {
+			result.Status = "failed"
+			result.Message = err.Error()
+		} else {
+			result.Status = "updated"
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// CloneTask 复制任务定义及告警组关联设置为新任务，新任务默认禁用且不继承作业状态与托管标记
+func CloneTask(id interface{}, name string) (seatunnelModel.EtlTask, error) {
+	src, err := GetTaskByID(id)
+	if err != nil {
+		return src, err
+	}
+	if name == "" {
+		name = src.Name + "-copy"
+		for i := 2; ; i++ {
+			var count int64
+			postgres.DB.Model(&seatunnelModel.EtlTask{}).Where("name = ? AND task_type = ?", name, src.TaskType).Count(&count)
+			if count == 0 {
+				break
+			}
+			name = fmt.Sprintf("%s-copy-%d", src.Name, i)
+		}
+	}
+	task := seatunnelModel.EtlTask{
+		Name:            name,
+		Description:     src.Description,
+		TaskType:        src.TaskType,
+		CronExpr:        src.CronExpr,
+		Config:          src.Config,
+		ConfigFormat:    src.ConfigFormat,
+		EncryptConfig:   src.EncryptConfig,
+		ShadeIdentifier: src.ShadeIdentifier,
+		ConfigEncrypted: src.ConfigEncrypted,
+		AlertGroup:      src.AlertGroup,
+		Cluster:         src.Cluster,
+		TemplateID:      src.TemplateID,
+		TemplateParams:  src.TemplateParams,
+		TemplateVersion: src.TemplateVersion,
+		Status:          0,
+	}
+	srcLinks, err := alertService.ListTaskGroups(alertModel.TaskSourceETL, src.ID, 0)
+	if err != nil {
+		return task, err
+	}
+	links := make([]alertService.TaskGroupLink, 0, len(srcLinks))
+	for _, l := range srcLinks {
+		links = append(links, alertService.TaskGroupLink{GroupID: l.GroupID, MinSeverity: l.MinSeverity, NotifyOn: l.NotifyOn})
+	}
+	err = postgres.DB.Transaction(func(tx *gorm.DB) error {
+		if err := CreateTaskTx(tx, &task); err != nil {
+			return err
+		}
+		// 关联的严重级别与通知事件一并复制
+		groups, err := alertService.SetTaskGroupsTx(tx, alertModel.TaskSourceETL, task.ID, links)
+		task.AlertGroup = groups
+		return err
+	})
+	return task, err
+}
+
+// LineDiff 逐行比对两段文本，返回以 "  "、"- "、"+ " 为前缀的差异行
+func LineDiff(oldText, newText string) []string {
+	a := strings.Split(oldText, "\n")
+	b := strings.Split(newText, "\n")
+	if len(a) > maxDiffLines || len(b) > maxDiffLines {
+		diff := make([]string, 0, len(a)+len(b))
+		for _, line := range a {
+			diff = append(diff, "- "+line)
+		}
+		for _, line := range b {
+			diff = append(diff, "+ "+line)
+		}
+		return diff
+	}
+
+	// lcs[i][j] 为 a[i:] 与 b[j:] 的最长公共子序列长度
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var diff []string
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			diff = append(diff, "  "+a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, "- "+a[i])
+			i++
+		default:
+			diff = append(diff, "+ "+b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		diff = append(diff, "- "+a[i])
+	}
+	for ; j < len(b); j++ {
+		diff = append(diff, "+ "+b[j])
+	}
+	return diff
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
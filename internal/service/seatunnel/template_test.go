@@ -0,0 +1,53 @@
+package seatunnel
+
+import (
+	"reflect"
+	"testing"
+
+	seatunnelModel "octoops/internal/model/seatunnel"
+)
+
+func TestRenderTemplateConfig(t *testing.T) {
+	tpl := seatunnelModel.EtlTaskTemplate{
+		Config: `{"source":{"table":"{{ .table }}","db":"{{ .db }}"}}`,
+		Params: `[{"name":"table","required":true},{"name":"db","default":"ods"}]`,
+	}
+	tests := []struct {
+		name      string
+		values    map[string]string
+		want      string
+		shouldErr bool
+	}{
+		{name: "default used", values: map[string]string{"table": "orders"}, want: `{"source":{"table":"orders","db":"ods"}}`},
+		{name: "override default", values: map[string]string{"table": "orders", "db": "dwd"}, want: `{"source":{"table":"orders","db":"dwd"}}`},
+		{name: "missing required", values: map[string]string{}, shouldErr: true},
+		{name: "undefined param", values: map[string]string{"table": "orders", "schema": "x"}, shouldErr: true},
+		{name: "json escaped", values: map[string]string{"table": `a"b\c`}, want: `{"source":{"table":"a\"b\\c","db":"ods"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderTemplateConfig(tpl, tt.values)
+			if tt.shouldErr {
+				if err == nil {
+					t.Fatalf("expected error, got %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("unexpected render result:\n got: %s\nwant: %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLineDiff(t *testing.T) {
+	got := LineDiff("a\nb\nc", "a\nx\nc\nd")
+	want := []string{"  a", "- b", "+ x", "  c", "+ d"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected diff:\n got: %q\nwant: %q", got, want)
+	}
+}
@@ -0,0 +1,390 @@
+package trigger
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"octoops/internal/event"
+	"octoops/internal/infra/postgres"
+	infraRedis "octoops/internal/infra/redis"
+	seatunnelModel "octoops/internal/model/seatunnel"
+	taskModel "octoops/internal/model/task"
+	"octoops/internal/scheduler"
+	seatunnelService "octoops/internal/service/seatunnel"
+	webhookService "octoops/internal/service/webhook"
+	"octoops/internal/utils"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	TargetEtl    = taskModel.TriggerTargetEtl
+	TargetCustom = taskModel.TriggerTargetCustom
+
+	defaultRateLimit = 6
+	// 签名时间戳允许的最大偏差
+	signatureTolerance = 5 * time.Minute
+)
+
+var (
+	ErrInvalidTrigger   = errors.New("触发器参数无效")
+	ErrTriggerNotFound  = errors.New("触发器不存在或已禁用")
+	ErrInvalidSignature = errors.New("签名校验失败")
+	ErrRateLimited      = errors.New("触发过于频繁，请稍后重试")
+	ErrUnsupportedTask  = errors.New("仅支持触发离线 ETL 任务或自定义任务")
+	ErrTaskDisabled     = errors.New("目标任务已禁用")
+
+	// decryptSecret 解密签名密钥，测试中可替换
+	decryptSecret = utils.DecryptAES
+)
+
+// TriggerRequest 创建/更新触发器请求，更新时为 nil 的字段保持不变
+type TriggerRequest struct {
+	Name       *string `json:"name"`
+	TargetType string  `json:"target_type"` // 创建时必填
+	TargetID   uint    `json:"target_id"`   // 创建时必填
+	Secret     *string `json:"secret"`      // 传空字符串表示关闭签名校验
+	RateLimit  *int    `json:"rate_limit"`
+	Status     *int    `json:"status"`
+}
+
+// TriggerView 触发器详情，Token 仅在创建和重置时返回
+type TriggerView struct {
+	taskModel.TaskTrigger
+	TargetName string `json:"target_name"`
+	HasSecret  bool   `json:"has_secret"`
+	Token      string `json:"token,omitempty"`
+}
+
+// FirePayload 触发请求体
+type FirePayload struct {
+	Variables map[string]string `json:"variables"` // 运行变量，仅对 ETL 任务生效，值中不允许包含数据源引用
+}
+
+// FireResult 触发结果
+type FireResult struct {
+	TargetType string `json:"target_type"`
+	TargetID   uint   `json:"target_id"`
+	TaskName   string `json:"task_name"`
+	JobID      string `json:"job_id,omitempty"`
+	Async      bool   `json:"async"` // 自定义任务在后台执行
+}
+
+func toView(t taskModel.TaskTrigger) TriggerView {
+	view := TriggerView{TaskTrigger: t, HasSecret: t.Secret != ""}
+	view.TargetName, _ = targetName(t.TargetType, t.TargetID)
+	return view
+}
+
+func targetName(targetType string, targetID uint) (string, error) {
+	switch targetType {
+	case TargetEtl:
+		var task seatunnelModel.EtlTask
+		if err := postgres.DB.Select("id", "name", "task_type").First(&task, targetID).Error; err != nil {
+			return "", err
+		}
+		if task.TaskType != "batch" {
+			return "", ErrUnsupportedTask
+		}
+		return task.Name, nil
+	case TargetCustom:
+		var task taskModel.CustomTask
+		if err := postgres.DB.Select("id", "name").First(&task, targetID).Error; err != nil {
+			return "", err
+		}
+		return task.Name, nil
+	default:
+		return "", ErrUnsupportedTask
+	}
+}
+
+// ListTriggers 触发器列表，targetType 为空时不过滤
+func ListTriggers(targetType string, targetID uint) ([]TriggerView, error) {
+	var triggers []taskModel.TaskTrigger
+	db := postgres.DB.Order("id desc")
+	if targetType != "" {
+		db = db.Where("target_type = ?", targetType)
+	}
+	if targetID > 0 {
+		db = db.Where("target_id = ?", targetID)
+	}
+	if err := db.Find(&triggers).Error; err != nil {
+		return nil, err
+	}
+	views := make([]TriggerView, 0, len(triggers))
+	for _, t := range triggers {
+		views = append(views, toView(t))
+	}
+	return views, nil
+}
+
+func GetTrigger(id interface{}) (TriggerView, error) {
+	var t taskModel.TaskTrigger
+	if err := postgres.DB.First(&t, id).Error; err != nil {
+		return TriggerView{}, err
+	}
+	return toView(t), nil
+}
+
+// CreateTrigger 创建触发器并返回 token 明文，之后无法再次查看
+func CreateTrigger(req TriggerRequest, operator string) (TriggerView, error) {
+	if req.Name == nil || strings.TrimSpace(*req.Name) == "" {
+		return TriggerView{}, fmt.Errorf("%w: name 不能为空", ErrInvalidTrigger)
+	}
+	if _, err := targetName(req.TargetType, req.TargetID); err != nil {
+		return TriggerView{}, err
+	}
+	t := taskModel.TaskTrigger{
+		TargetType: req.TargetType,
+		TargetID:   req.TargetID,
+		RateLimit:  defaultRateLimit,
+		Status:     1,
+		CreatedBy:  operator,
+	}
+	if err := applyRequest(&t, req); err != nil {
+		return TriggerView{}, err
+	}
+	token, err := newToken(&t)
+	if err != nil {
+		return TriggerView{}, err
+	}
+	if err := postgres.DB.Create(&t).Error; err != nil {
+		return TriggerView{}, err
+	}
+	view := toView(t)
+	view.Token = token
+	return view, nil
+}
+
+func UpdateTrigger(id interface{}, req TriggerRequest) (TriggerView, error) {
+	var t taskModel.TaskTrigger
+	if err := postgres.DB.First(&t, id).Error; err != nil {
+		return TriggerView{}, err
+	}
+	if err := applyRequest(&t, req); err != nil {
+		return TriggerView{}, err
+	}
+	if err := postgres.DB.Save(&t).Error; err != nil {
+		return TriggerView{}, err
+	}
+	return toView(t), nil
+}
+
+// RotateToken 重置触发器 token，旧 token 立即失效
+func RotateToken(id interface{}) (TriggerView, error) {
+	var t taskModel.TaskTrigger
+	if err := postgres.DB.First(&t, id).Error; err != nil {
+		return TriggerView{}, err
+	}
+	token, err := newToken(&t)
+	if err != nil {
+		return TriggerView{}, err
+	}
+	if err := postgres.DB.Model(&t).Updates(map[string]interface{}{"token_hash": t.TokenHash, "token_prefix": t.TokenPrefix}).Error; err != nil {
+		return TriggerView{}, err
+	}
+	view := toView(t)
+	view.Token = token
+	return view, nil
+}
+
+func DeleteTrigger(id interface{}) error {
+	return postgres.DB.Delete(&taskModel.TaskTrigger{}, id).Error
+}
+
+// DeleteTargetTriggers 删除指向指定任务的全部触发器，任务删除时调用
+func DeleteTargetTriggers(targetType string, targetID uint) error {
+	return postgres.DB.Where("target_type = ? AND target_id = ?", targetType, targetID).Delete(&taskModel.TaskTrigger{}).Error
+}
+
+func applyRequest(t *taskModel.TaskTrigger, req TriggerRequest) error {
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return fmt.Errorf("%w: name 不能为空", ErrInvalidTrigger)
+		}
+		t.Name = name
+	}
+	if req.RateLimit != nil {
+		if *req.RateLimit < 1 || *req.RateLimit > 600 {
+			return fmt.Errorf("%w: rate_limit 取值范围为 1-600", ErrInvalidTrigger)
+		}
+		t.RateLimit = *req.RateLimit
+	}
+	if req.Status != nil {
+		t.Status = *req.Status
+	}
+	if req.Secret != nil {
+		if *req.Secret == "" {
+			t.Secret = ""
+		} else {
+			encrypted, err := utils.EncryptAES(*req.Secret)
+			if err != nil {
+				return fmt.Errorf("加密签名密钥失败: %w", err)
+			}
+			t.Secret = encrypted
+		}
+	}
+	return nil
+}
+
+func newToken(t *taskModel.TaskTrigger) (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(buf)
+	t.TokenHash = hashToken(token)
+	t.TokenPrefix = token[:8]
+	return token, nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Fire 校验 token、限流与签名后执行目标任务；ETL 任务同步提交，自定义任务在后台执行
+func Fire(token string, body []byte, timestamp, signature string) (FireResult, error) {
+	var t taskModel.TaskTrigger
+	if err := postgres.DB.Where("token_hash = ? AND status = ?", hashToken(token), 1).First(&t).Error; err != nil {
+		return FireResult{}, ErrTriggerNotFound
+	}
+	payload, err := checkFireRequest(t, body, timestamp, signature)
+	if err != nil {
+		return FireResult{}, err
+	}
+	postgres.DB.Model(&t).Update("last_triggered_at", time.Now())
+
+	switch t.TargetType {
+	case TargetEtl:
+		return fireEtl(t, payload.Variables)
+	case TargetCustom:
+		return fireCustom(t)
+	default:
+		return FireResult{}, ErrUnsupportedTask
+	}
+}
+
+// checkFireRequest 依次校验签名、限流与请求体，返回解析后的触发参数；
+// 签名无效的请求不计入限流，避免伪造请求耗尽合法调用方的配额
+func checkFireRequest(t taskModel.TaskTrigger, body []byte, timestamp, signature string) (FirePayload, error) {
+	var payload FirePayload
+	if t.Secret != "" {
+		if err := verifySignature(t.Secret, body, timestamp, signature); err != nil {
+			return payload, err
+		}
+	}
+	if !allow(t) {
+		return payload, ErrRateLimited
+	}
+	if len(strings.TrimSpace(string(body))) > 0 {
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return payload, fmt.Errorf("%w: 请求体不是合法的 JSON: %v", ErrInvalidTrigger, err)
+		}
+	}
+	// 未签名的调用方也可传入变量，禁止通过变量值引用数据源敏感参数
+	for name, value := range payload.Variables {
+		if strings.Contains(value, "${datasource.") {
+			return payload, fmt.Errorf("%w: 变量 %s 的值不允许包含数据源引用", ErrInvalidTrigger, name)
+		}
+	}
+	return payload, nil
+}
+
+// allow 每分钟计数限流，多副本通过 Redis 共享计数；Redis 不可用时按实例在进程内计数
+func allow(t taskModel.TaskTrigger) bool {
+	limit := t.RateLimit
+	if limit <= 0 {
+		limit = defaultRateLimit
+	}
+	key := fmt.Sprintf("octoops:trigger:rate:%d:%d", t.ID, time.Now().Unix()/60)
+	return infraRedis.CountInWindow(key, 2*time.Minute) <= int64(limit)
+}
+
+// verifySignature 校验 X-OctoOps-Signature，签名方式与出站 Webhook 相同
+func verifySignature(encryptedSecret string, body []byte, timestamp, signature string) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: 缺少或无效的 X-OctoOps-Timestamp", ErrInvalidSignature)
+	}
+	if diff := time.Since(time.Unix(ts, 0)); diff > signatureTolerance || diff < -signatureTolerance {
+		return fmt.Errorf("%w: 时间戳已过期", ErrInvalidSignature)
+	}
+	secret, err := decryptSecret(encryptedSecret)
+	if err != nil {
+		return fmt.Errorf("解密签名密钥失败: %w", err)
+	}
+	expected := "sha256=" + webhookService.Sign(secret, timestamp, body)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func fireEtl(t taskModel.TaskTrigger, vars map[string]string) (FireResult, error) {
+	var task seatunnelModel.EtlTask
+	if err := postgres.DB.First(&task, t.TargetID).Error; err != nil {
+		return FireResult{}, fmt.Errorf("任务不存在: %v", err)
+	}
+	if task.TaskType != "batch" {
+		return FireResult{}, ErrUnsupportedTask
+	}
+	if task.Status != 1 {
+		return FireResult{}, ErrTaskDisabled
+	}
+	result := FireResult{TargetType: TargetEtl, TargetID: task.ID, TaskName: task.Name}
+	now := time.Now()
+	postgres.DB.Model(&task).Update("last_run_time", now)
+	event.Publish(event.TaskRunStarted{Source: event.SourceEtl, Trigger: event.TriggerWebhook, TaskID: task.ID, TaskName: task.Name, TaskType: task.TaskType, At: now})
+
+	respBody, err := seatunnelService.SubmitJobInternal(task.ID, false, vars)
+	finished := event.TaskRunFinished{
+		Source:   event.SourceEtl,
+		Trigger:  event.TriggerWebhook,
+		TaskID:   task.ID,
+		TaskName: task.Name,
+		TaskType: task.TaskType,
+		Status:   "success",
+		Result:   string(respBody),
+		At:       time.Now(),
+	}
+	if err != nil {
+		log.Printf("[Trigger] 触发 ETL 任务失败: trigger=%d, taskID=%d, error=%v", t.ID, task.ID, err)
+		finished.Status = "failed"
+		finished.Result = err.Error()
+		event.PublishDurable(finished)
+		return result, err
+	}
+	seatunnelService.UpdateJobIdFromResponse(task.ID, respBody)
+	event.PublishDurable(finished)
+	result.JobID = seatunnelService.ParseJobIDFromResponse(respBody)
+	log.Printf("[Trigger] 触发 ETL 任务成功: trigger=%d, taskID=%d, jobId=%s", t.ID, task.ID, result.JobID)
+	return result, nil
+}
+
+func fireCustom(t taskModel.TaskTrigger) (FireResult, error) {
+	var task taskModel.CustomTask
+	if err := postgres.DB.First(&task, t.TargetID).Error; err != nil {
+		return FireResult{}, fmt.Errorf("任务不存在: %v", err)
+	}
+	if task.Status != 1 {
+		return FireResult{}, ErrTaskDisabled
+	}
+	job := scheduler.GetJobFuncByType(task.CustomType)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("[Trigger][Panic] 自定义任务执行异常: id=%d, name=%s, err=%v", task.ID, task.Name, r)
+			}
+		}()
+		scheduler.RunCustomJob(task, event.TriggerWebhook, job)
+	}()
+	return FireResult{TargetType: TargetCustom, TargetID: task.ID, TaskName: task.Name, Async: true}, nil
+}
@@ -0,0 +1,126 @@
+package trigger
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	taskModel "octoops/internal/model/task"
+	webhookService "octoops/internal/service/webhook"
+)
+
+func init() {
+	// 测试中签名密钥以明文保存
+	decryptSecret = func(s string) (string, error) { return s, nil }
+}
+
+func sign(secret, timestamp, body string) string {
+	return "sha256=" + webhookService.Sign(secret, timestamp, []byte(body))
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := `{"variables":{"dt":"2024-01-01"}}`
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	expired := strconv.FormatInt(time.Now().Add(-10*time.Minute).Unix(), 10)
+	tests := []struct {
+		name      string
+		timestamp string
+		signature string
+		wantErr   bool
+	}{
+		{name: "valid", timestamp: now, signature: sign("s3cret", now, body)},
+		{name: "wrong secret", timestamp: now, signature: sign("other", now, body), wantErr: true},
+		{name: "body tampered", timestamp: now, signature: sign("s3cret", now, body+" "), wantErr: true},
+		{name: "missing timestamp", timestamp: "", signature: sign("s3cret", "", body), wantErr: true},
+		{name: "expired timestamp", timestamp: expired, signature: sign("s3cret", expired, body), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifySignature("s3cret", []byte(body), tt.timestamp, tt.signature)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidSignature) {
+					t.Fatalf("expected ErrInvalidSignature, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestAllow(t *testing.T) {
+	trigger := taskModel.TaskTrigger{ID: 900001, RateLimit: 2}
+	for i, want := range []bool{true, true, false, false} {
+		if got := allow(trigger); got != want {
+			t.Fatalf("call %d: allow() = %v, want %v", i+1, got, want)
+		}
+	}
+	// 各触发器独立计数
+	if !allow(taskModel.TaskTrigger{ID: 900002, RateLimit: 1}) {
+		t.Fatal("expected other trigger to be allowed")
+	}
+}
+
+func TestCheckFireRequest(t *testing.T) {
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	signedBody := `{"variables":{"dt":"2024-01-01"}}`
+	tests := []struct {
+		name      string
+		trigger   taskModel.TaskTrigger
+		body      string
+		signature string
+		wantErr   error
+		wantVars  map[string]string
+	}{
+		{name: "empty body", trigger: taskModel.TaskTrigger{ID: 900101}},
+		{name: "variables", trigger: taskModel.TaskTrigger{ID: 900102}, body: signedBody, wantVars: map[string]string{"dt": "2024-01-01"}},
+		{name: "invalid json", trigger: taskModel.TaskTrigger{ID: 900103}, body: `{"variables":`, wantErr: ErrInvalidTrigger},
+		{name: "datasource reference in variable", trigger: taskModel.TaskTrigger{ID: 900104},
+			body: `{"variables":{"dt":"${datasource.mysql_prod.password}"}}`, wantErr: ErrInvalidTrigger},
+		{name: "signed", trigger: taskModel.TaskTrigger{ID: 900105, Secret: "s3cret"}, body: signedBody,
+			signature: sign("s3cret", now, signedBody), wantVars: map[string]string{"dt": "2024-01-01"}},
+		{name: "bad signature", trigger: taskModel.TaskTrigger{ID: 900106, Secret: "s3cret"}, body: signedBody,
+			signature: sign("other", now, signedBody), wantErr: ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := checkFireRequest(tt.trigger, []byte(tt.body), now, tt.signature)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for k, v := range tt.wantVars {
+				if payload.Variables[k] != v {
+					t.Fatalf("variable %s = %q, want %q", k, payload.Variables[k], v)
+				}
+			}
+		})
+	}
+
+	limited := taskModel.TaskTrigger{ID: 900107, RateLimit: 1}
+	if _, err := checkFireRequest(limited, nil, now, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := checkFireRequest(limited, nil, now, ""); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+
+	// 签名无效的请求不占用限流配额
+	signedLimited := taskModel.TaskTrigger{ID: 900108, Secret: "s3cret", RateLimit: 1}
+	for i := 0; i < 3; i++ {
+		if _, err := checkFireRequest(signedLimited, []byte(signedBody), now, sign("other", now, signedBody)); !errors.Is(err, ErrInvalidSignature) {
+			t.Fatalf("expected ErrInvalidSignature, got %v", err)
+		}
+	}
+	if _, err := checkFireRequest(signedLimited, []byte(signedBody), now, sign("s3cret", now, signedBody)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
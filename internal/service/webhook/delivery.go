@@ -0,0 +1,220 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"octoops/internal/infra/postgres"
+	alertModel "octoops/internal/model/alert"
+	"octoops/internal/utils"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	workerPollInterval = 2 * time.Second
+	workerBatchSize    = 20
+	// 单条投递认领后的租约时间，需大于 deliveryTimeout；实例崩溃时租约过期由其他实例接手
+	deliveryLease    = 2 * time.Minute
+	deliveryTimeout  = 10 * time.Second
+	maxAttempts      = 6
+	maxResponseBytes = 2048
+)
+
+var (
+	wake       = make(chan struct{}, 1)
+	workerOnce sync.Once
+	httpClient = &http.Client{Timeout: deliveryTimeout}
+)
+
+func notifyWorker() {
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+}
+
+// StartWorker 启动投递协程，多副本部署时通过 SKIP LOCKED 认领投递记录
+func StartWorker() {
+	workerOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(workerPollInterval)
+			defer ticker.Stop()
+			for {
+				for deliverBatch() {
+				}
+				select {
+				case <-ticker.C:
+				case <-wake:
+				}
+			}
+		}()
+	})
+}
+
+// deliverBatch 逐条认领并投递到期记录，每条记录单独设置租约，返回是否处理满一批
+func deliverBatch() bool {
+	for i := 0; i < workerBatchSize; i++ {
+		d, err := claimDelivery()
+		if err != nil {
+			log.Printf("[Webhook] 认领投递记录失败: %v", err)
+			return false
+		}
+		if d == nil {
+			return false
+		}
+		deliver(*d)
+	}
+	return true
+}
+
+// claimDelivery 在短事务中认领一条到期记录：递增 attempts 并将 next_retry_at 推后一个租约，没有到期记录时返回 nil
+func claimDelivery() (*alertModel.WebhookDelivery, error) {
+	var d alertModel.WebhookDelivery
+	found := false
+	err := postgres.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_retry_at <= ?", "pending", time.Now()).
+			Order("id").Limit(1).Find(&d)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		found = true
+		d.Attempts++
+		return tx.Model(&alertModel.WebhookDelivery{}).Where("id = ?", d.ID).Updates(map[string]interface{}{
+			"attempts":      d.Attempts,
+			"next_retry_at": time.Now().Add(deliveryLease),
+		}).Error
+	})
+	if err != nil || !found {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// deliver 发送单条已认领的投递并回写结果，attempts 已在认领时递增
+func deliver(d alertModel.WebhookDelivery) {
+	attempts := d.Attempts
+	updates := map[string]interface{}{}
+
+	var sub alertModel.WebhookSubscription
+	if err := postgres.DB.First(&sub, d.SubscriptionID).Error; err != nil {
+		updates["status"] = "failed"
+		updates["error"] = "订阅不存在或已删除"
+		saveDeliveryResult(d, updates)
+		return
+	}
+
+	code, body, err := send(sub, d)
+	updates["response_code"] = code
+	updates["response_body"] = body
+	if err == nil && code >= 200 && code < 300 {
+		updates["status"] = "succeeded"
+		updates["error"] = ""
+		updates["delivered_at"] = time.Now()
+	} else {
+		message := fmt.Sprintf("HTTP %d", code)
+		if err != nil {
+			message = err.Error()
+		}
+		if runes := []rune(message); len(runes) > 500 {
+			message = string(runes[:500])
+		}
+		updates["error"] = message
+		if attempts >= maxAttempts {
+			updates["status"] = "failed"
+			log.Printf("[Webhook] 投递失败且重试次数耗尽: id=%d, subscription=%d, error=%s", d.ID, d.SubscriptionID, message)
+		} else {
+			updates["next_retry_at"] = time.Now().Add(retryBackoff(attempts))
+		}
+	}
+	saveDeliveryResult(d, updates)
+}
+
+// saveDeliveryResult 仅在记录仍处于本次认领时回写结果，租约过期后被其他实例重新认领时放弃
+func saveDeliveryResult(d alertModel.WebhookDelivery, updates map[string]interface{}) {
+	result := postgres.DB.Model(&alertModel.WebhookDelivery{}).
+		Where("id = ? AND status = ? AND attempts = ?", d.ID, "pending", d.Attempts).Updates(updates)
+	if result.Error != nil {
+		log.Printf("[Webhook] 更新投递记录失败: id=%d, error=%v", d.ID, result.Error)
+	} else if result.RowsAffected == 0 {
+		log.Printf("[Webhook] 投递租约已过期并被重新认领，忽略本次结果: id=%d", d.ID)
+	}
+}
+
+// send 按订阅配置发送请求，返回状态码和截断后的响应体
+func send(sub alertModel.WebhookSubscription, d alertModel.WebhookDelivery) (int, string, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"id":         d.ID,
+		"event":      d.EventName,
+		"created_at": d.CreatedAt,
+		"data":       json.RawMessage(d.Payload),
+	})
+	if err != nil {
+		return 0, "", err
+	}
+	req, err := http.NewRequest(http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+	if sub.Headers != "" {
+		var headers map[string]string
+		if err := json.Unmarshal([]byte(sub.Headers), &headers); err == nil {
+			for k, v := range headers {
+				req.Header.Set(k, v)
+			}
+		}
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-OctoOps-Event", d.EventName)
+	req.Header.Set("X-OctoOps-Delivery", strconv.FormatUint(uint64(d.ID), 10))
+	req.Header.Set("X-OctoOps-Timestamp", timestamp)
+	if sub.Secret != "" {
+		secret, err := utils.DecryptAES(sub.Secret)
+		if err != nil {
+			return 0, "", fmt.Errorf("解密签名密钥失败: %w", err)
+		}
+		req.Header.Set("X-OctoOps-Signature", "sha256="+Sign(secret, timestamp, body))
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	return resp.StatusCode, strings.ToValidUTF8(string(respBody), ""), nil
+}
+
+// Sign 计算请求签名：HMAC-SHA256(secret, timestamp + "." + body) 的十六进制编码，
+// 接收方用相同方式计算并比较 X-OctoOps-Signature
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// retryBackoff 指数退避，从 30 秒起翻倍，最长 1 小时
+func retryBackoff(attempts int) time.Duration {
+	if attempts > 8 {
+		return time.Hour
+	}
+	d := 30 * time.Second << (attempts - 1)
+	if d > time.Hour {
+		return time.Hour
+	}
+	return d
+}
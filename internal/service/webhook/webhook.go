@@ -0,0 +1,345 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"octoops/internal/event"
+	"octoops/internal/infra/postgres"
+	alertModel "octoops/internal/model/alert"
+	"octoops/internal/utils"
+	"strings"
+	"time"
+)
+
+// EventPing 测试投递使用的事件名称
+const EventPing = "ping"
+
+var (
+	ErrInvalidSubscription = errors.New("Webhook 订阅参数无效")
+	ErrDeliveryPending     = errors.New("投递尚未结束，无需重投")
+)
+
+// Filter 投递筛选条件，为空的字段不参与筛选
+type Filter struct {
+	TaskIDs   []uint   `json:"task_ids,omitempty"`
+	TaskTypes []string `json:"task_types,omitempty"`
+	Statuses  []string `json:"statuses,omitempty"` // 匹配事件中的 new_status 或 status
+}
+
+// SubscriptionRequest 创建/更新订阅请求，更新时为 nil 的字段保持不变
+type SubscriptionRequest struct {
+	Name       *string           `json:"name"`
+	URL        *string           `json:"url"`
+	EventTypes []string          `json:"event_types"`
+	Filter     *Filter           `json:"filter"`
+	Secret     *string           `json:"secret"` // 传空字符串表示清除密钥
+	Headers    map[string]string `json:"headers"`
+	Status     *int              `json:"status"`
+}
+
+// SubscriptionView 订阅详情，不返回密钥明文
+type SubscriptionView struct {
+	alertModel.WebhookSubscription
+	HasSecret bool `json:"has_secret"`
+}
+
+func toView(sub alertModel.WebhookSubscription) SubscriptionView {
+	return SubscriptionView{WebhookSubscription: sub, HasSecret: sub.Secret != ""}
+}
+
+// EventTypes 可订阅的事件名称
+func EventTypes() []string {
+	return event.Names()
+}
+
+func ListSubscriptions() ([]SubscriptionView, error) {
+	var subs []alertModel.WebhookSubscription
+	if err := postgres.DB.Order("created_at desc").Find(&subs).Error; err != nil {
+		return nil, err
+	}
+	views := make([]SubscriptionView, 0, len(subs))
+	for _, sub := range subs {
+		views = append(views, toView(sub))
+	}
+	return views, nil
+}
+
+func GetSubscription(id interface{}) (SubscriptionView, error) {
+	var sub alertModel.WebhookSubscription
+	if err := postgres.DB.First(&sub, id).Error; err != nil {
+		return SubscriptionView{}, err
+	}
+	return toView(sub), nil
+}
+
+func CreateSubscription(req SubscriptionRequest) (SubscriptionView, error) {
+	sub := alertModel.WebhookSubscription{Status: 1}
+	if req.Name == nil || strings.TrimSpace(*req.Name) == "" || req.URL == nil || len(req.EventTypes) == 0 {
+		return SubscriptionView{}, fmt.Errorf("%w: name、url、event_types 不能为空", ErrInvalidSubscription)
+	}
+	if err := applyRequest(&sub, req); err != nil {
+		return SubscriptionView{}, err
+	}
+	if err := postgres.DB.Create(&sub).Error; err != nil {
+		return SubscriptionView{}, err
+	}
+	return toView(sub), nil
+}
+
+func UpdateSubscription(id interface{}, req SubscriptionRequest) (SubscriptionView, error) {
+	var sub alertModel.WebhookSubscription
+	if err := postgres.DB.First(&sub, id).Error; err != nil {
+		return SubscriptionView{}, err
+	}
+	if err := applyRequest(&sub, req); err != nil {
+		return SubscriptionView{}, err
+	}
+	if err := postgres.DB.Save(&sub).Error; err != nil {
+		return SubscriptionView{}, err
+	}
+	return toView(sub), nil
+}
+
+func DeleteSubscription(id interface{}) error {
+	return postgres.DB.Delete(&alertModel.WebhookSubscription{}, id).Error
+}
+
+// applyRequest 校验并写入请求字段
+func applyRequest(sub *alertModel.WebhookSubscription, req SubscriptionRequest) error {
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return fmt.Errorf("%w: name 不能为空", ErrInvalidSubscription)
+		}
+		sub.Name = name
+	}
+	if req.URL != nil {
+		u, err := url.Parse(strings.TrimSpace(*req.URL))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: url 必须是 http(s) 地址", ErrInvalidSubscription)
+		}
+		sub.URL = u.String()
+	}
+	if req.EventTypes != nil {
+		known := map[string]bool{"*": true}
+		for _, name := range event.Names() {
+			known[name] = true
+		}
+		var types []string
+		for _, t := range req.EventTypes {
+			t = strings.TrimSpace(t)
+			if t == "" {
+				continue
+			}
+			if !known[t] {
+				return fmt.Errorf("%w: 未知事件类型 %s", ErrInvalidSubscription, t)
+			}
+			types = append(types, t)
+		}
+		if len(types) == 0 {
+			return fmt.Errorf("%w: event_types 不能为空", ErrInvalidSubscription)
+		}
+		sub.EventTypes = strings.Join(types, ",")
+	}
+	if req.Filter != nil {
+		data, _ := json.Marshal(req.Filter)
+		sub.Filter = string(data)
+	}
+	if req.Headers != nil {
+		for key := range req.Headers {
+			if strings.HasPrefix(strings.ToLower(key), "x-octoops-") || strings.EqualFold(key, "Content-Type") {
+				return fmt.Errorf("%w: 不允许自定义请求头 %s", ErrInvalidSubscription, key)
+			}
+		}
+		data, _ := json.Marshal(req.Headers)
+		sub.Headers = string(data)
+	}
+	if req.Secret != nil {
+		if *req.Secret == "" {
+			sub.Secret = ""
+		} else {
+			encrypted, err := utils.EncryptAES(*req.Secret)
+			if err != nil {
+				return fmt.Errorf("加密签名密钥失败: %w", err)
+			}
+			sub.Secret = encrypted
+		}
+	}
+	if req.Status != nil {
+		sub.Status = *req.Status
+	}
+	return nil
+}
+
+// ListDeliveries 投递记录，subscriptionID 为 0 时不按订阅过滤
+func ListDeliveries(subscriptionID uint, status string, page, pageSize int) ([]alertModel.WebhookDelivery, int64, error) {
+	var deliveries []alertModel.WebhookDelivery
+	var total int64
+	db := postgres.DB.Model(&alertModel.WebhookDelivery{})
+	if subscriptionID > 0 {
+		db = db.Where("subscription_id = ?", subscriptionID)
+	}
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := db.Order("id desc").Offset((page - 1) * pageSize).Limit(pageSize).Find(&deliveries).Error
+	return deliveries, total, err
+}
+
+// Redeliver 以原投递内容新建一条投递记录
+func Redeliver(id interface{}) (alertModel.WebhookDelivery, error) {
+	var origin alertModel.WebhookDelivery
+	if err := postgres.DB.First(&origin, id).Error; err != nil {
+		return origin, err
+	}
+	if origin.Status == "pending" {
+		return origin, ErrDeliveryPending
+	}
+	if _, err := GetSubscription(origin.SubscriptionID); err != nil {
+		return origin, err
+	}
+	delivery := alertModel.WebhookDelivery{
+		SubscriptionID: origin.SubscriptionID,
+		EventName:      origin.EventName,
+		Payload:        origin.Payload,
+		Status:         "pending",
+		NextRetryAt:    time.Now(),
+		RedeliveryOf:   &origin.ID,
+	}
+	if err := postgres.DB.Create(&delivery).Error; err != nil {
+		return delivery, err
+	}
+	notifyWorker()
+	return delivery, nil
+}
+
+// SendPing 向订阅地址投递一条测试事件
+func SendPing(id interface{}) (alertModel.WebhookDelivery, error) {
+	sub, err := GetSubscription(id)
+	if err != nil {
+		return alertModel.WebhookDelivery{}, err
+	}
+	payload, _ := json.Marshal(map[string]interface{}{"message": "OctoOps webhook 测试", "at": time.Now()})
+	delivery := alertModel.WebhookDelivery{
+		SubscriptionID: sub.ID,
+		EventName:      EventPing,
+		Payload:        string(payload),
+		Status:         "pending",
+		NextRetryAt:    time.Now(),
+	}
+	if err := postgres.DB.Create(&delivery).Error; err != nil {
+		return delivery, err
+	}
+	notifyWorker()
+	return delivery, nil
+}
+
+// RegisterEventHandlers 订阅全部领域事件，为匹配的 Webhook 订阅生成投递记录
+func RegisterEventHandlers() {
+	event.Subscribe("*", "webhook.enqueue", func(ev event.Event) error {
+		return enqueue(ev)
+	})
+}
+
+func enqueue(ev event.Event) error {
+	var subs []alertModel.WebhookSubscription
+	if err := postgres.DB.Where("status = ?", 1).Find(&subs).Error; err != nil {
+		return err
+	}
+	if len(subs) == 0 {
+		return nil
+	}
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	var data map[string]interface{}
+	_ = json.Unmarshal(payload, &data)
+
+	created := 0
+	for _, sub := range subs {
+		if !subscribes(sub, ev.EventName()) || !matchFilter(sub.Filter, data) {
+			continue
+		}
+		delivery := alertModel.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventName:      ev.EventName(),
+			Payload:        string(payload),
+			Status:         "pending",
+			NextRetryAt:    time.Now(),
+		}
+		if err := postgres.DB.Create(&delivery).Error; err != nil {
+			return err
+		}
+		created++
+	}
+	if created > 0 {
+		notifyWorker()
+	}
+	return nil
+}
+
+func subscribes(sub alertModel.WebhookSubscription, name string) bool {
+	for _, t := range strings.Split(sub.EventTypes, ",") {
+		if t = strings.TrimSpace(t); t == "*" || t == name {
+			return true
+		}
+	}
+	return false
+}
+
+// matchFilter 判断事件内容是否满足订阅的筛选条件
+func matchFilter(raw string, data map[string]interface{}) bool {
+	if raw == "" {
+		return true
+	}
+	var f Filter
+	if err := json.Unmarshal([]byte(raw), &f); err != nil {
+		return false
+	}
+	if len(f.TaskIDs) > 0 {
+		id, ok := data["task_id"].(float64)
+		if !ok || !containsUint(f.TaskIDs, uint(id)) {
+			return false
+		}
+	}
+	if len(f.TaskTypes) > 0 {
+		taskType, _ := data["task_type"].(string)
+		if !containsString(f.TaskTypes, taskType) {
+			return false
+		}
+	}
+	if len(f.Statuses) > 0 {
+		status, _ := data["new_status"].(string)
+		if status == "" {
+			status, _ = data["status"].(string)
+		}
+		if !containsString(f.Statuses, status) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsUint(list []uint, v uint) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, v string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package webhook
+
+import (
+	"testing"
+
+	alertModel "octoops/internal/model/alert"
+)
+
+func TestSign(t *testing.T) {
+	// echo -n '1700000000.{"a":1}' | openssl dgst -sha256 -hmac secret
+	got := Sign("secret", "1700000000", []byte(`{"a":1}`))
+	want := "49f24e537407743fa4a0242bb63b94b9a47ee99cbbe071ccd8a22550ae411686"
+	if got != want {
+		t.Fatalf("Sign() = %s, want %s", got, want)
+	}
+}
+
+func TestMatchFilter(t *testing.T) {
+	data := map[string]interface{}{"task_id": float64(3), "task_type": "stream", "new_status": "FAILED"}
+	tests := []struct {
+		name   string
+		filter string
+		want   bool
+	}{
+		{name: "empty filter", filter: "", want: true},
+		{name: "task id match", filter: `{"task_ids":[1,3]}`, want: true},
+		{name: "task id mismatch", filter: `{"task_ids":[1,2]}`, want: false},
+		{name: "task type and status", filter: `{"task_types":["stream"],"statuses":["failed"]}`, want: true},
+		{name: "status mismatch", filter: `{"statuses":["FINISHED"]}`, want: false},
+		{name: "invalid filter", filter: `{`, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchFilter(tt.filter, data); got != tt.want {
+				t.Errorf("matchFilter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubscribes(t *testing.T) {
+	sub := alertModel.WebhookSubscription{EventTypes: "job.status_changed, task.run_finished"}
+	if !subscribes(sub, "task.run_finished") {
+		t.Error("expected task.run_finished to be subscribed")
+	}
+	if subscribes(sub, "user.logged_in") {
+		t.Error("unexpected user.logged_in subscription")
+	}
+	if !subscribes(alertModel.WebhookSubscription{EventTypes: "*"}, "user.logged_in") {
+		t.Error("wildcard should match all events")
+	}
+}
@@ -1,13 +1,19 @@
 package utils
 
 import (
+	"fmt"
 	"octoops/internal/config"
+	"strings"
+	"time"
 
 	"gopkg.in/gomail.v2"
 )
 
+// 单封邮件从建连到发送完成的最长时间
+const mailSendTimeout = 30 * time.Second
+
 type MailOptions struct {
-	To      string   // 收件人
+	To      string   // 收件人，多个以逗号分隔
 	Cc      []string // 抄送，可选
 	Subject string   // 主题
 	Body    string   // 正文（支持HTML）
@@ -25,7 +31,13 @@ func SendMail(opt MailOptions) error {
 	} else {
 		m.SetHeader("From", cfg.SMTPUser)
 	}
-	m.SetHeader("To", opt.To)
+	var to []string
+	for _, addr := range strings.Split(opt.To, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			to = append(to, addr)
+		}
+	}
+	m.SetHeader("To", to...)
 	if len(opt.Cc) > 0 {
 		m.SetHeader("Cc", opt.Cc...)
 	}
@@ -35,5 +47,15 @@ func SendMail(opt MailOptions) error {
 	d := gomail.NewDialer(cfg.SMTPAddress, cfg.SMTPPort, cfg.SMTPUser, cfg.SMTPPassword)
 
 	d.SSL = cfg.SSL
-	return d.DialAndSend(m)
+	// gomail 只限制建连时间，SMTP 服务器无响应时整体超时返回，避免占用告警发送协程
+	done := make(chan error, 1)
+	go func() {
+		done <- d.DialAndSend(m)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(mailSendTimeout):
+		return fmt.Errorf("发送邮件超时（%s）", mailSendTimeout)
+	}
 }
@@ -7,10 +7,16 @@ package utils
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
+// EscapeLike 转义 LIKE 通配符，配合 ESCAPE '\' 使用，使 _ 与 % 按字面匹配
+func EscapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
 // 从中间件上下文获取当前用户的 uid, roleId, 类型是 interface
 
 func GetContextUser(c *gin.Context) (int, int, error) {
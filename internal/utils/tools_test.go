@@ -0,0 +1,23 @@
+package utils
+
+import "testing"
+
+func TestEscapeLike(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"plain", "orders", "orders"},
+		{"underscore", "user_sync", `user\_sync`},
+		{"percent", "100%", `100\%`},
+		{"backslash", `a\b`, `a\\b`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EscapeLike(tt.input); got != tt.want {
+				t.Errorf("EscapeLike(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}